@@ -4,13 +4,16 @@ import (
 	"context"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/core/accesstoken"
 )
 
 func (a *API) createAccessToken(ctx context.Context, x struct {
 	ID   string `json:"id"`
 	Type string `json:"type"`
+	accesstoken.Policy
 }) Response {
-	token, err := a.accessTokens.Create(x.ID, x.Type)
+	token, err := a.accessTokens.Create(x.ID, x.Type, &x.Policy)
 	if err != nil {
 		return NewErrorResponse(err)
 	}
@@ -41,7 +44,7 @@ func (a *API) checkAccessToken(ctx context.Context, x struct {
 	ID     string `json:"id"`
 	Secret string `json:"secret"`
 }) Response {
-	if err := a.accessTokens.Check(x.ID, x.Secret); err != nil {
+	if _, err := a.accessTokens.Check(x.ID, x.Secret); err != nil {
 		return NewErrorResponse(err)
 	}
 
@@ -18,8 +18,10 @@ func (a *API) createAccount(ctx context.Context, ins struct {
 	RootXPubs []chainkd.XPub `json:"root_xpubs"`
 	Quorum    int            `json:"quorum"`
 	Alias     string         `json:"alias"`
+	KeyScheme string         `json:"key_scheme"`
+	WatchOnly bool           `json:"watch_only"`
 }) Response {
-	acc, err := a.wallet.AccountMgr.Create(ins.RootXPubs, ins.Quorum, ins.Alias)
+	acc, err := a.wallet.AccountMgr.CreateWithScheme(ins.RootXPubs, ins.Quorum, ins.Alias, ins.KeyScheme, ins.WatchOnly)
 	if err != nil {
 		return NewErrorResponse(err)
 	}
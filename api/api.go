@@ -213,6 +213,7 @@ func (a *API) buildHandler() {
 		m.Handle("/list-assets", jsonHandler(a.listAssets))
 
 		m.Handle("/create-key", jsonHandler(a.pseudohsmCreateKey))
+		m.Handle("/recover-key", jsonHandler(a.pseudohsmRecoverKey))
 		m.Handle("/list-keys", jsonHandler(a.pseudohsmListKeys))
 		m.Handle("/delete-key", jsonHandler(a.pseudohsmDeleteKey))
 		m.Handle("/reset-key-password", jsonHandler(a.pseudohsmResetPassword))
@@ -224,6 +225,16 @@ func (a *API) buildHandler() {
 		m.Handle("/get-transaction", jsonHandler(a.getTransaction))
 		m.Handle("/list-transactions", jsonHandler(a.listTransactions))
 
+		m.Handle("/create-transaction-feed", jsonHandler(a.createTxFeed))
+		m.Handle("/get-transaction-feed", jsonHandler(a.getTxFeed))
+		m.Handle("/list-transaction-feeds", jsonHandler(a.listTxFeeds))
+		m.Handle("/delete-transaction-feed", jsonHandler(a.deleteTxFeed))
+		m.Handle("/update-transaction-feed", jsonHandler(a.updateTxFeed))
+		m.Handle("/list-transaction-feed-txs", jsonHandler(a.listTxFeedTxs))
+
+		m.Handle("/register-contract-abi", jsonHandler(a.registerContractABI))
+		m.Handle("/list-contracts", jsonHandler(a.listContracts))
+
 		m.Handle("/list-balances", jsonHandler(a.listBalances))
 		m.Handle("/list-unspent-outputs", jsonHandler(a.listUnspentOutputs))
 
@@ -231,6 +242,8 @@ func (a *API) buildHandler() {
 		m.Handle("/restore-wallet", jsonHandler(a.restoreWalletImage))
 		m.Handle("/rescan-wallet", jsonHandler(a.rescanWallet))
 		m.Handle("/wallet-info", jsonHandler(a.getWalletInfo))
+		m.Handle("/abandon-transaction", jsonHandler(a.abandonTransaction))
+		m.Handle("/rebroadcast-transactions", jsonHandler(a.rebroadcastTransactions))
 	} else {
 		log.Warn("Please enable wallet")
 	}
@@ -256,6 +269,9 @@ func (a *API) buildHandler() {
 	m.Handle("/get-block-count", jsonHandler(a.getBlockCount))
 	m.Handle("/get-difficulty", jsonHandler(a.getDifficulty))
 	m.Handle("/get-hash-rate", jsonHandler(a.getHashRate))
+	m.Handle("/get-transaction-receipt", jsonHandler(a.getTransactionReceipt))
+	m.Handle("/get-logs", jsonHandler(a.getLogs))
+	m.Handle("/trace-transaction", jsonHandler(a.traceTransaction))
 
 	m.Handle("/is-mining", jsonHandler(a.isMining))
 	m.Handle("/set-mining", jsonHandler(a.setMining))
@@ -276,8 +292,11 @@ func (a *API) buildHandler() {
 
 	m.Handle("/call-contract", jsonHandler(a.callContract))
 	m.Handle("/balance-of", jsonHandler(a.balanceOf))
+	m.Handle("/get-storage-at", jsonHandler(a.getStorageAt))
+	m.Handle(ethRPCPath, a.ethHandler())
 
 	handler := latencyHandler(m, walletEnable)
+	handler = a.authzHandler(m, handler)
 	handler = maxBytesHandler(handler) // TODO(tessr): consider moving this to non-core specific mux
 	handler = webAssetsHandler(handler)
 	handler = gzip.Handler{Handler: handler}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/accesstoken"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/core/txbuilder"
+	"github.com/doslink/doslink/net/http/authn"
+	"github.com/doslink/doslink/protocol/bc"
+)
+
+var errNotAuthorized = errors.New("not authorized")
+
+// routeScopes is the access token scope of the routes, the routes which are
+// not listed require the admin scope
+var routeScopes = map[string]string{
+	"/":      accesstoken.ScopeReadOnly,
+	"/error": accesstoken.ScopeReadOnly,
+
+	"/list-accounts":             accesstoken.ScopeReadOnly,
+	"/list-addresses":            accesstoken.ScopeReadOnly,
+	"/validate-address":          accesstoken.ScopeReadOnly,
+	"/list-pubkeys":              accesstoken.ScopeReadOnly,
+	"/get-mining-address":        accesstoken.ScopeReadOnly,
+	"/get-coinbase-arbitrary":    accesstoken.ScopeReadOnly,
+	"/get-asset":                 accesstoken.ScopeReadOnly,
+	"/list-assets":               accesstoken.ScopeReadOnly,
+	"/list-keys":                 accesstoken.ScopeReadOnly,
+	"/get-transaction":           accesstoken.ScopeReadOnly,
+	"/list-transactions":         accesstoken.ScopeReadOnly,
+	"/get-transaction-feed":      accesstoken.ScopeReadOnly,
+	"/list-transaction-feeds":    accesstoken.ScopeReadOnly,
+	"/list-transaction-feed-txs": accesstoken.ScopeReadOnly,
+	"/list-contracts":            accesstoken.ScopeReadOnly,
+	"/list-balances":             accesstoken.ScopeReadOnly,
+	"/list-unspent-outputs":      accesstoken.ScopeReadOnly,
+	"/wallet-info":               accesstoken.ScopeReadOnly,
+
+	"/estimate-transaction-gas":      accesstoken.ScopeReadOnly,
+	"/get-unconfirmed-transaction":   accesstoken.ScopeReadOnly,
+	"/list-unconfirmed-transactions": accesstoken.ScopeReadOnly,
+	"/decode-raw-transaction":        accesstoken.ScopeReadOnly,
+	"/get-block":                     accesstoken.ScopeReadOnly,
+	"/get-block-hash":                accesstoken.ScopeReadOnly,
+	"/get-block-header":              accesstoken.ScopeReadOnly,
+	"/get-block-count":               accesstoken.ScopeReadOnly,
+	"/get-difficulty":                accesstoken.ScopeReadOnly,
+	"/get-hash-rate":                 accesstoken.ScopeReadOnly,
+	"/get-transaction-receipt":       accesstoken.ScopeReadOnly,
+	"/get-logs":                      accesstoken.ScopeReadOnly,
+	"/trace-transaction":             accesstoken.ScopeReadOnly,
+	"/is-mining":                     accesstoken.ScopeReadOnly,
+	"/decode-program":                accesstoken.ScopeReadOnly,
+	"/gas-rate":                      accesstoken.ScopeReadOnly,
+	"/net-info":                      accesstoken.ScopeReadOnly,
+	"/list-peers":                    accesstoken.ScopeReadOnly,
+	"/call-contract":                 accesstoken.ScopeReadOnly,
+	"/balance-of":                    accesstoken.ScopeReadOnly,
+	"/get-storage-at":                accesstoken.ScopeReadOnly,
+	ethRPCPath:                       accesstoken.ScopeReadOnly,
+
+	"/create-account":           accesstoken.ScopeWallet,
+	"/create-account-receiver":  accesstoken.ScopeWallet,
+	"/create-asset":             accesstoken.ScopeWallet,
+	"/update-asset-alias":       accesstoken.ScopeWallet,
+	"/build-transaction":        accesstoken.ScopeWallet,
+	"/submit-transaction":       accesstoken.ScopeWallet,
+	"/create-transaction-feed":  accesstoken.ScopeWallet,
+	"/update-transaction-feed":  accesstoken.ScopeWallet,
+	"/delete-transaction-feed":  accesstoken.ScopeWallet,
+	"/register-contract-abi":    accesstoken.ScopeWallet,
+	"/rescan-wallet":            accesstoken.ScopeWallet,
+	"/abandon-transaction":      accesstoken.ScopeWallet,
+	"/rebroadcast-transactions": accesstoken.ScopeWallet,
+
+	"/create-key":         accesstoken.ScopeSigning,
+	"/recover-key":        accesstoken.ScopeSigning,
+	"/check-key-password": accesstoken.ScopeSigning,
+	"/reset-key-password": accesstoken.ScopeSigning,
+	"/sign-transaction":   accesstoken.ScopeSigning,
+
+	"/set-mining":             accesstoken.ScopeMining,
+	"/set-mining-address":     accesstoken.ScopeMining,
+	"/set-coinbase-arbitrary": accesstoken.ScopeMining,
+	"/get-work":               accesstoken.ScopeMining,
+	"/get-work-json":          accesstoken.ScopeMining,
+	"/submit-work":            accesstoken.ScopeMining,
+	"/submit-work-json":       accesstoken.ScopeMining,
+}
+
+// accountRoutes are the wallet routes restricted by the accounts of the access
+// token, the request must name the accounts by account_id, account_alias or
+// the output_id of the spent output. The /sign-transaction is restricted by
+// the accounts controlling the inputs of the template. The handlers of
+// /list-accounts, /list-balances, /list-unspent-outputs, /get-transaction,
+// /list-transactions, /list-transaction-feed-txs and /abandon-transaction
+// keep to the granted accounts themselves, see restrictedToken.
+var accountRoutes = map[string]bool{
+	"/create-account-receiver": true,
+	"/list-addresses":          true,
+	"/list-pubkeys":            true,
+	"/build-transaction":       true,
+	"/sign-transaction":        true,
+}
+
+// walletRoutes act on the whole wallet, the account restricted tokens can not
+// call them
+var walletRoutes = map[string]bool{
+	"/delete-account":           true,
+	"/backup-wallet":            true,
+	"/restore-wallet":           true,
+	"/rescan-wallet":            true,
+	"/rebroadcast-transactions": true,
+}
+
+// authzHandler checks the scopes and the accounts of the access token of the
+// request, the requests without token are authorized by the AuthHandler
+func (a *API) authzHandler(m *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		token := authn.AccessToken(req.Context())
+		if token == nil {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		_, pattern := m.Handler(req)
+		scope, ok := routeScopes[pattern]
+		if !ok {
+			scope = accesstoken.ScopeAdmin
+		}
+		if !token.HasScope(scope) {
+			err := errors.WithDetailf(errNotAuthorized, "route %s requires the %s scope", pattern, scope)
+			errorFormatter.Write(req.Context(), w, err)
+			return
+		}
+
+		if len(token.Accounts) > 0 && walletRoutes[pattern] {
+			err := errors.WithDetailf(errNotAuthorized, "route %s is not available to an account restricted token", pattern)
+			errorFormatter.Write(req.Context(), w, err)
+			return
+		}
+		if len(token.Accounts) > 0 && accountRoutes[pattern] {
+			if err := a.checkRequestAccounts(token, pattern, req); err != nil {
+				errorFormatter.Write(req.Context(), w, err)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+}
+
+// checkRequestAccounts check the accounts named by the request body are
+// granted to the token, the body is kept for the route handler
+func (a *API) checkRequestAccounts(token *accesstoken.Token, pattern string, req *http.Request) error {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if pattern == "/sign-transaction" {
+		return a.checkTemplateAccounts(token, body)
+	}
+
+	var data interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &data); err != nil {
+			return errors.WithDetail(errNotAuthorized, "invalid request body")
+		}
+	}
+
+	ids, aliases, outputIDs := requestAccounts(data)
+	if len(ids)+len(aliases)+len(outputIDs) == 0 {
+		return errors.WithDetail(errNotAuthorized, "the request of an account restricted token must name its accounts")
+	}
+
+	for _, id := range ids {
+		if !a.grantedAccount(token, id) {
+			return errors.WithDetailf(errNotAuthorized, "account %s is not granted", id)
+		}
+	}
+	for _, alias := range aliases {
+		account, err := a.wallet.AccountMgr.FindByAlias(alias)
+		if err != nil || !token.HasAccount(account.ID, account.Alias) {
+			return errors.WithDetailf(errNotAuthorized, "account %s is not granted", alias)
+		}
+	}
+	for _, outputID := range outputIDs {
+		var hash bc.Hash
+		if err := hash.UnmarshalText([]byte(outputID)); err != nil {
+			return errors.WithDetailf(errNotAuthorized, "invalid output id %s", outputID)
+		}
+
+		utxo, err := a.wallet.AccountMgr.FindUtxo(hash)
+		if err != nil || !a.grantedAccount(token, utxo.AccountID) {
+			return errors.WithDetailf(errNotAuthorized, "output %s is not controlled by a granted account", outputID)
+		}
+	}
+	return nil
+}
+
+// checkTemplateAccounts check every input of the transaction template to sign
+// is controlled by an account granted to the token
+func (a *API) checkTemplateAccounts(token *accesstoken.Token, body []byte) error {
+	var in struct {
+		Txs txbuilder.Template `json:"transaction"`
+	}
+	if err := json.Unmarshal(body, &in); err != nil || in.Txs.Transaction == nil {
+		return errors.WithDetail(errNotAuthorized, "invalid request body")
+	}
+
+	for i, input := range in.Txs.Transaction.Inputs {
+		cp, err := a.wallet.AccountMgr.GetLocalCtrlProgram(input.ControlProgram())
+		if err != nil || !a.grantedAccount(token, cp.AccountID) {
+			return errors.WithDetailf(errNotAuthorized, "input %d is not controlled by a granted account", i)
+		}
+	}
+	return nil
+}
+
+// restrictedToken returns the access token of the request if it is restricted
+// to accounts, the handlers of the wallet read routes only return the data of
+// the granted accounts to it
+func restrictedToken(ctx context.Context) *accesstoken.Token {
+	if token := authn.AccessToken(ctx); token != nil && len(token.Accounts) > 0 {
+		return token
+	}
+	return nil
+}
+
+// grantedAccount reports whether the account of the id is granted to the token
+func (a *API) grantedAccount(token *accesstoken.Token, id string) bool {
+	account, err := a.wallet.AccountMgr.FindByID(id)
+	return err == nil && token.HasAccount(account.ID, account.Alias)
+}
+
+// grantedTxs filters the transactions spending from or paying to the accounts
+// granted to the token
+func grantedTxs(token *accesstoken.Token, txs []*query.AnnotatedTx) []*query.AnnotatedTx {
+	result := []*query.AnnotatedTx{}
+	for _, tx := range txs {
+		granted := false
+		for _, input := range tx.Inputs {
+			granted = granted || (input.AccountID != "" && token.HasAccount(input.AccountID, input.AccountAlias))
+		}
+		for _, output := range tx.Outputs {
+			granted = granted || (output.AccountID != "" && token.HasAccount(output.AccountID, output.AccountAlias))
+		}
+		if granted {
+			result = append(result, tx)
+		}
+	}
+	return result
+}
+
+// requestAccounts collects the account_id, account_alias and output_id fields
+// of the request, including the ones of the nested actions
+func requestAccounts(data interface{}) (ids []string, aliases []string, outputIDs []string) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			s, isString := value.(string)
+			switch {
+			case key == "account_id" && isString && s != "":
+				ids = append(ids, s)
+			case key == "account_alias" && isString && s != "":
+				aliases = append(aliases, s)
+			case key == "output_id" && isString && s != "":
+				outputIDs = append(outputIDs, s)
+			default:
+				subIDs, subAliases, subOutputIDs := requestAccounts(value)
+				ids, aliases, outputIDs = append(ids, subIDs...), append(aliases, subAliases...), append(outputIDs, subOutputIDs...)
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			subIDs, subAliases, subOutputIDs := requestAccounts(value)
+			ids, aliases, outputIDs = append(ids, subIDs...), append(aliases, subAliases...), append(outputIDs, subOutputIDs...)
+		}
+	}
+	return ids, aliases, outputIDs
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/accesstoken"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/core/rpc"
+	"github.com/doslink/doslink/core/wallet"
+	"github.com/doslink/doslink/testutil"
+)
+
+func TestRequestAccounts(t *testing.T) {
+	body := `{
+		"base_transaction": null,
+		"actions": [
+			{"type": "spend_account", "account_id": "acc-1", "amount": 100},
+			{"type": "spend_account", "account_alias": "alice", "amount": 100},
+			{"type": "spend_account_unspent_output", "output_id": "aa"},
+			{"type": "control_address", "address": "bb", "amount": 100}
+		]
+	}`
+
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, aliases, outputIDs := requestAccounts(data)
+	if !testutil.DeepEqual(ids, []string{"acc-1"}) {
+		t.Errorf("got ids %v", ids)
+	}
+	if !testutil.DeepEqual(aliases, []string{"alice"}) {
+		t.Errorf("got aliases %v", aliases)
+	}
+	if !testutil.DeepEqual(outputIDs, []string{"aa"}) {
+		t.Errorf("got output ids %v", outputIDs)
+	}
+}
+
+func TestGrantedTxs(t *testing.T) {
+	token := &accesstoken.Token{Accounts: []string{"acc-1", "bob"}}
+	txs := []*query.AnnotatedTx{
+		{Inputs: []*query.AnnotatedInput{{AccountID: "acc-1"}}},
+		{Outputs: []*query.AnnotatedOutput{{AccountID: "acc-2", AccountAlias: "bob"}}},
+		{Inputs: []*query.AnnotatedInput{{AccountID: "acc-3"}}, Outputs: []*query.AnnotatedOutput{{}}},
+		{},
+	}
+
+	got := grantedTxs(token, txs)
+	if !testutil.DeepEqual(got, txs[:2]) {
+		t.Errorf("got %d granted txs, want the first 2", len(got))
+	}
+}
+
+func TestRestrictedTokenRoutes(t *testing.T) {
+	defer os.RemoveAll("temp")
+	chain := mockChain(t)
+	walletDB := dbm.NewDB("walletdb", "leveldb", "temp")
+
+	accountMgr := account.NewManager(walletDB, chain)
+	for _, alias := range []string{"alice", "bob"} {
+		_, xpub, err := chainkd.NewXKeys(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := accountMgr.Create([]chainkd.XPub{xpub}, 1, alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tokens := accesstoken.NewStore(dbm.NewDB("tokendb", "leveldb", "temp"))
+	token, err := tokens.Create("alice-reader", "client", &accesstoken.Policy{Accounts: []string{"alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &API{
+		chain:        chain,
+		accessTokens: tokens,
+		wallet:       &wallet.Wallet{DB: walletDB, AccountMgr: accountMgr},
+	}
+	a.buildHandler()
+	server := httptest.NewServer(AuthHandler(a.handler, tokens))
+	defer server.Close()
+	client := &rpc.Client{BaseURL: server.URL, AccessToken: token.Token}
+
+	// the denied requests respond with 403 Forbidden
+	cases := []struct {
+		path    string
+		request map[string]string
+		denied  bool
+	}{
+		{path: "/list-pubkeys", request: map[string]string{"account_alias": "alice"}},
+		{path: "/list-pubkeys", request: map[string]string{"account_alias": "bob"}, denied: true},
+		{path: "/list-pubkeys", request: map[string]string{}, denied: true},
+		{path: "/list-addresses", request: map[string]string{"account_alias": "bob"}, denied: true},
+		{path: "/list-balances", request: map[string]string{}},
+		{path: "/list-unspent-outputs", request: map[string]string{}},
+		{path: "/backup-wallet", request: map[string]string{"account_alias": "alice"}, denied: true},
+		{path: "/rescan-wallet", request: map[string]string{"account_alias": "alice"}, denied: true},
+	}
+
+	for i, c := range cases {
+		err := client.Call(context.Background(), c.path, c.request, &Response{})
+		if statusErr, ok := errors.Root(err).(rpc.ErrStatusCode); c.denied != (ok && statusErr.StatusCode == http.StatusForbidden) || !c.denied && err != nil {
+			t.Errorf("case %d: %s got error %v, want denied %v", i, c.path, err, c.denied)
+		}
+	}
+
+	// the accounts of other owners are left out of the listing
+	var accounts struct {
+		Status string                   `json:"status"`
+		Data   []query.AnnotatedAccount `json:"data"`
+	}
+	if err := client.Call(context.Background(), "/list-accounts", map[string]string{}, &accounts); err != nil {
+		t.Fatal(err)
+	}
+	if accounts.Status != "success" || len(accounts.Data) != 1 || accounts.Data[0].Alias != "alice" {
+		t.Errorf("got accounts %v, want only alice", accounts.Data)
+	}
+}
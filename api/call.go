@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"math"
 	"math/big"
 	"strings"
@@ -10,7 +11,11 @@ import (
 	chainjson "github.com/doslink/doslink/basis/encoding/json"
 	"github.com/doslink/doslink/basis/errors"
 	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/core/contract"
+	"github.com/doslink/doslink/net/http/httpjson"
 	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/vm"
 	"github.com/doslink/doslink/protocol/vm/evm"
 	"github.com/doslink/doslink/protocol/vm/state"
@@ -20,14 +25,40 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// StateReq selects the block whose state a contract query runs on, the best
+// block is used when neither the height nor the hash is given
+type StateReq struct {
+	BlockHeight *uint64            `json:"block_height"`
+	BlockHash   chainjson.HexBytes `json:"block_hash"`
+}
+
+func (a *API) stateHeader(req StateReq) (*types.BlockHeader, error) {
+	if len(req.BlockHash) != 0 {
+		if len(req.BlockHash) != 32 {
+			return nil, errors.WithDetailf(httpjson.ErrBadRequest, "block_hash is %d bytes, want 32 bytes", len(req.BlockHash))
+		}
+		b32 := [32]byte{}
+		copy(b32[:], req.BlockHash)
+		hash := bc.NewHash(b32)
+		return a.chain.GetHeaderByHash(&hash)
+	}
+	if req.BlockHeight != nil {
+		return a.chain.GetHeaderByHeight(*req.BlockHeight)
+	}
+	return a.chain.BestBlockHeader(), nil
+}
+
 // POST /call-contract
 func (a *API) callContract(ctx context.Context, ins struct {
+	StateReq
 	Sender          chainjson.HexBytes `json:"from"`
 	ContractAddress chainjson.HexBytes `json:"to"`
 	AssetId         string             `json:"asset_id"`
 	AssertAlias     string             `json:"asset_alias"`
 	AssetAmount     uint64             `json:"value"`
 	Data            chainjson.HexBytes `json:"input"`
+	Method          string             `json:"method"`
+	Args            json.RawMessage    `json:"args"`
 }) Response {
 
 	assetID := ins.AssetId
@@ -46,17 +77,45 @@ func (a *API) callContract(ctx context.Context, ins struct {
 		}
 	}
 
-	res, gas, _, err := doCall(a.chain, ins.Sender, ins.ContractAddress, assetID, ins.AssetAmount, ins.Data)
+	header, err := a.stateHeader(ins.StateReq)
 	if err != nil {
 		return NewErrorResponse(err)
 	}
+
+	var abiContract *contract.Contract
+	if ins.Method != "" {
+		if abiContract, err = a.wallet.ContractReg.Find(ins.ContractAddress); err != nil {
+			return NewErrorResponse(err)
+		}
+		if ins.Data, err = abiContract.Pack(ins.Method, ins.Args); err != nil {
+			return NewErrorResponse(err)
+		}
+	}
+
+	res, gas, _, err := doCall(a.chain, header, ins.Sender, ins.ContractAddress, assetID, ins.AssetAmount, ins.Data)
+	if err != nil {
+		resp := NewErrorResponse(err)
+		if len(res) > 0 {
+			resp.Data = revertData(res, gas)
+		}
+		return resp
+	}
 	resMap := map[string]interface{}{"ret": hex.EncodeToString(res)}
 	resMap["gas"] = gas
+	if abiContract != nil {
+		decoded, err := abiContract.UnpackOutput(ins.Method, res)
+		if err != nil {
+			return NewErrorResponse(err)
+		}
+		resMap["decoded"] = decoded
+	}
 	return NewSuccessResponse(resMap)
 }
 
+// doCall executes the message on top of the state of the given block
 func doCall(
 	chain *protocol.Chain,
+	header *types.BlockHeader,
 	sender []byte,
 	contractAddress []byte,
 	assetID string,
@@ -73,12 +132,10 @@ func doCall(
 
 		msg      evm_types.Message
 		author   *evm_common.Address
-		header   = chain.BestBlockHeader()
-		stateDB  evm.StateDB
 		vmConfig = evm.Config{}
 	)
 
-	stateDB, err = protocol.NewState(&header.StateRoot, chain)
+	stateDB, err := chain.StateAt(header)
 	if err != nil {
 		return nil, 0, false, err
 	}
@@ -100,31 +157,53 @@ func doCall(
 	gp := new(state.GasPool).AddGas(math.MaxUint64)
 
 	res, gas, failed, err = state.ApplyMessage(evmEnv, msg, gp)
-
+	if dbErr := stateDB.Error(); dbErr != nil {
+		return res, gas, failed, protocol.StateError(dbErr, header)
+	}
+	if err != nil {
+		err = protocol.RevertError(res, err)
+	}
 	return res, gas, failed, err
 }
 
+// revertData is the data of the failed call response, it carries the return
+// data and the decoded revert reason if any
+func revertData(ret []byte, gas uint64) map[string]interface{} {
+	data := map[string]interface{}{"ret": hex.EncodeToString(ret), "gas": gas}
+	if reason, ok := state.UnpackRevert(ret); ok {
+		data["revert_reason"] = reason
+	}
+	return data
+}
+
+// POST /balance-of
 func (a *API) balanceOf(ctx context.Context, ins struct {
+	StateReq
 	Sender          chainjson.HexBytes `json:"sender"`
 	ContractAddress chainjson.HexBytes `json:"contract"`
 }) Response {
+	header, err := a.stateHeader(ins.StateReq)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
 
 	if ins.ContractAddress == nil {
-		chain := a.chain
-		header := chain.BestBlockHeader()
-		stateDB, err := protocol.NewState(&header.StateRoot, chain)
+		stateDB, err := a.chain.StateAt(header)
 		if err != nil {
 			return NewErrorResponse(err)
 		}
 		addr := evm_common.BytesToAddress(ins.Sender)
 		balance := stateDB.GetBalance(addr)
+		if err := stateDB.Error(); err != nil {
+			return NewErrorResponse(protocol.StateError(err, header))
+		}
 		resMap := map[string]interface{}{"balance": balance}
 		return NewSuccessResponse(resMap)
 	}
 
 	dataHex := "70a08231" + "000000000000000000000000" + hex.EncodeToString(ins.Sender)
 	data, _ := hex.DecodeString(dataHex)
-	res, gas, _, err := doCall(a.chain, ins.Sender, ins.ContractAddress, "", 0, data)
+	res, gas, _, err := doCall(a.chain, header, ins.Sender, ins.ContractAddress, "", 0, data)
 	if err != nil {
 		return NewErrorResponse(err)
 	}
@@ -132,3 +211,26 @@ func (a *API) balanceOf(ctx context.Context, ins struct {
 	resMap["gas"] = gas
 	return NewSuccessResponse(resMap)
 }
+
+// POST /get-storage-at
+func (a *API) getStorageAt(ctx context.Context, ins struct {
+	StateReq
+	ContractAddress chainjson.HexBytes `json:"contract"`
+	Key             chainjson.HexBytes `json:"key"`
+}) Response {
+	header, err := a.stateHeader(ins.StateReq)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	stateDB, err := a.chain.StateAt(header)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	value := stateDB.GetState(evm_common.BytesToAddress(ins.ContractAddress), evm_common.BytesToHash(ins.Key))
+	if err := stateDB.Error(); err != nil {
+		return NewErrorResponse(protocol.StateError(err, header))
+	}
+	return NewSuccessResponse(map[string]interface{}{"value": hex.EncodeToString(value.Bytes())})
+}
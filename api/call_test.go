@@ -0,0 +1,18 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/net/http/httpjson"
+)
+
+func TestStateHeaderBadHash(t *testing.T) {
+	a := &API{}
+	height := uint64(0)
+	for _, hash := range [][]byte{{1, 2, 3}, make([]byte, 33)} {
+		if _, err := a.stateHeader(StateReq{BlockHeight: &height, BlockHash: hash}); errors.Root(err) != httpjson.ErrBadRequest {
+			t.Errorf("block hash %x: got error %v, want %v", hash, err, httpjson.ErrBadRequest)
+		}
+	}
+}
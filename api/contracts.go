@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+)
+
+// POST /register-contract-abi
+func (a *API) registerContractABI(ctx context.Context, in struct {
+	Address chainjson.HexBytes `json:"address"`
+	Alias   string             `json:"alias"`
+	ABI     json.RawMessage    `json:"abi"`
+}) Response {
+	contract, err := a.wallet.ContractReg.Register(in.Address, in.Alias, in.ABI)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	// rescan the chain so that the token balances include the transfers
+	// before the registration
+	if contract.IsToken() {
+		a.wallet.RescanBlocks()
+	}
+	return NewSuccessResponse(contract)
+}
+
+// POST /list-contracts
+func (a *API) listContracts(ctx context.Context) Response {
+	return NewSuccessResponse(a.wallet.ContractReg.List())
+}
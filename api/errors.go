@@ -3,15 +3,20 @@ package api
 import (
 	"context"
 
+	"github.com/doslink/doslink/basis/crypto/mnemonic"
+	"github.com/doslink/doslink/core/accesstoken"
 	"github.com/doslink/doslink/core/account"
 	"github.com/doslink/doslink/core/asset"
+	"github.com/doslink/doslink/core/contract"
 	"github.com/doslink/doslink/core/pseudohsm"
 	"github.com/doslink/doslink/core/rpc"
 	"github.com/doslink/doslink/core/signers"
 	"github.com/doslink/doslink/core/txbuilder"
+	"github.com/doslink/doslink/core/txfeed"
 	"github.com/doslink/doslink/basis/errors"
 	"github.com/doslink/doslink/net/http/httperror"
 	"github.com/doslink/doslink/net/http/httpjson"
+	"github.com/doslink/doslink/protocol"
 	"github.com/doslink/doslink/protocol/validation"
 	"github.com/doslink/doslink/protocol/vm"
 )
@@ -45,11 +50,12 @@ var respErrFormatter = map[error]httperror.Info{
 	ErrDefault: {500, "000", "API Error"},
 
 	// Signers error namespace (2xx)
-	signers.ErrBadQuorum: {400, "200", "Quorum must be greater than 1 and less than or equal to the length of xpubs"},
-	signers.ErrBadXPub:   {400, "201", "Invalid xpub format"},
-	signers.ErrNoXPubs:   {400, "202", "At least one xpub is required"},
-	signers.ErrBadType:   {400, "203", "Retrieved type does not match expected type"},
-	signers.ErrDupeXPub:  {400, "204", "Root XPubs cannot contain the same key more than once"},
+	signers.ErrBadQuorum:    {400, "200", "Quorum must be greater than 1 and less than or equal to the length of xpubs"},
+	signers.ErrBadXPub:      {400, "201", "Invalid xpub format"},
+	signers.ErrNoXPubs:      {400, "202", "At least one xpub is required"},
+	signers.ErrBadType:      {400, "203", "Retrieved type does not match expected type"},
+	signers.ErrDupeXPub:     {400, "204", "Root XPubs cannot contain the same key more than once"},
+	signers.ErrBadKeyScheme: {400, "206", "Unsupported key scheme"},
 
 	// Transaction error namespace (7xx)
 	// Build transaction error namespace (70x ~ 72x)
@@ -67,6 +73,14 @@ var respErrFormatter = map[error]httperror.Info{
 	txbuilder.ErrBadContractArgType: {400, "711", "Invalid contract argument type"},
 	txbuilder.ErrOrphanTx:           {400, "712", "Not found transaction input utxo"},
 	txbuilder.ErrExtTxFee:           {400, "713", "Transaction fee exceed max limit"},
+	protocol.ErrTxNotFound:          {400, "714", "Not found transaction in the main chain"},
+	protocol.ErrBadLogFilter:        {400, "715", "Invalid log filter"},
+	protocol.ErrLogRangeTooLarge:    {400, "716", "Log filter block range too large"},
+	protocol.ErrStatePruned:         {400, "717", "State of the block has been pruned"},
+	errUnknownTracer:                {400, "718", "Unknown transaction tracer"},
+	protocol.ErrGasLimitExceeded:    {400, "719", "Gas required exceeds the transaction gas limit"},
+	protocol.ErrExecutionReverted:   {400, "720", "Contract execution reverted"},
+	account.ErrGasPriceInactive:     {400, "721", "Gas price of contract message is not active"},
 
 	// Submit transaction error namespace (73x ~ 79x)
 	// Validation error (73x ~ 75x)
@@ -114,6 +128,34 @@ var respErrFormatter = map[error]httperror.Info{
 	pseudohsm.ErrLoadKey:              {400, "802", "Key not found or wrong password"},
 	pseudohsm.ErrTooManyAliasesToList: {400, "803", "Requested key aliases exceeds limit"},
 	pseudohsm.ErrDecrypt:              {400, "804", "Could not decrypt key with given passphrase"},
+	pseudohsm.ErrDuplicateKey:         {400, "805", "Key already exists"},
+	mnemonic.ErrInvalidMnemonic:       {400, "806", "Invalid mnemonic"},
+	errRemoteSigner:                   {400, "807", "Operation is not supported by the remote signer"},
+
+	// Access token error namespace (86x)
+	accesstoken.ErrBadID:        {400, "862", "Invalid access token ID"},
+	accesstoken.ErrDuplicateID:  {400, "863", "Access token ID already exists"},
+	accesstoken.ErrNoMatchID:    {400, "864", "Not found access token ID"},
+	accesstoken.ErrInvalidToken: {400, "865", "Invalid access token"},
+	accesstoken.ErrBadScope:     {400, "866", "Invalid access token scope"},
+	accesstoken.ErrExpiredToken: {400, "867", "Access token expired"},
+
+	// Transaction feed error namespace (9xx)
+	txfeed.ErrBadFilter:      {400, "900", "Invalid transaction feed filter"},
+	txfeed.ErrDuplicateAlias: {400, "901", "Transaction feed alias already exists"},
+	txfeed.ErrEmptyAlias:     {400, "902", "Empty transaction feed alias"},
+	txfeed.ErrFindTxFeed:     {400, "903", "Not found transaction feed"},
+	txfeed.ErrNumExceedLimit: {400, "904", "Transaction feed number exceeds limit"},
+	txfeed.ErrBadCursor:      {400, "905", "Invalid transaction feed cursor"},
+
+	// Contract ABI error namespace (91x)
+	contract.ErrBadAddress:     {400, "910", "Invalid contract address"},
+	contract.ErrBadABI:         {400, "911", "Invalid contract ABI"},
+	contract.ErrDuplicateAlias: {400, "912", "Contract alias already exists"},
+	contract.ErrFindContract:   {400, "913", "Not found contract ABI"},
+	contract.ErrFindMethod:     {400, "914", "Not found contract method"},
+	contract.ErrBadArgument:    {400, "915", "Invalid contract method argument"},
+	contract.ErrUnpack:         {400, "916", "Contract data does not match the ABI"},
 }
 
 // Map error values to standard error codes. Missing entries
@@ -132,5 +174,6 @@ var errorFormatter = httperror.Formatter{
 
 		//accesstoken authz err namespace (86x)
 		errNotAuthenticated: {401, "860", "Request could not be authenticated"},
+		errNotAuthorized:    {403, "861", "Request is not authorized by the access token"},
 	},
 }
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+	evm_types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/consensus/difficulty"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+const ethRPCPath = "/eth"
+
+var (
+	errEthMissingTo    = errors.New("missing contract address")
+	errEthValueTooHigh = errors.New("value exceeds the max asset amount")
+	errEthBlockHash    = errors.New("block hash is not in the main chain")
+)
+
+// ethHandler serves the ethereum compatible JSON-RPC 2.0 methods of the
+// embedded EVM, so that web3 tooling can talk to the node. The debug
+// namespace serves the transaction traces.
+func (a *API) ethHandler() http.Handler {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &EthAPI{chain: a.chain}); err != nil {
+		panic(err)
+	}
+	if err := server.RegisterName("debug", &DebugAPI{chain: a.chain}); err != nil {
+		panic(err)
+	}
+	return server
+}
+
+// EthAPI implements the eth namespace on top of protocol.Chain, the block
+// number arguments select the block whose state root the call runs on.
+type EthAPI struct {
+	chain *protocol.Chain
+}
+
+// EthCallArgs is the transaction call object of eth_call and eth_estimateGas
+type EthCallArgs struct {
+	From     evm_common.Address  `json:"from"`
+	To       *evm_common.Address `json:"to"`
+	Gas      hexutil.Uint64      `json:"gas"`
+	GasPrice hexutil.Big         `json:"gasPrice"`
+	Value    hexutil.Big         `json:"value"`
+	Data     hexutil.Bytes       `json:"data"`
+}
+
+func (e *EthAPI) headerByNumber(blockNr rpc.BlockNumber) (*types.BlockHeader, error) {
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		return e.chain.BestBlockHeader(), nil
+	}
+	return e.chain.GetHeaderByHeight(uint64(blockNr))
+}
+
+// readState runs the read on the state of the block, and reports the pruned
+// state met during the read
+func (e *EthAPI) readState(blockNr rpc.BlockNumber, read func(*evm_state.StateDB)) error {
+	header, err := e.headerByNumber(blockNr)
+	if err != nil {
+		return err
+	}
+
+	stateDB, err := e.chain.StateAt(header)
+	if err != nil {
+		return err
+	}
+
+	read(stateDB)
+	return protocol.StateError(stateDB.Error(), header)
+}
+
+func (e *EthAPI) doCall(args EthCallArgs, blockNr rpc.BlockNumber) ([]byte, uint64, error) {
+	if args.To == nil {
+		return nil, 0, errEthMissingTo
+	}
+
+	header, err := e.headerByNumber(blockNr)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value := args.Value.ToInt()
+	if !value.IsUint64() {
+		return nil, 0, errEthValueTooHigh
+	}
+
+	res, gas, _, err := doCall(e.chain, header, args.From.Bytes(), args.To.Bytes(), consensus.NativeAssetID.String(), value.Uint64(), args.Data)
+	return res, gas, err
+}
+
+// BlockNumber returns the height of the best block
+func (e *EthAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(e.chain.BestBlockHeight())
+}
+
+// GetBalance returns the native asset balance of the address in the state
+func (e *EthAPI) GetBalance(address evm_common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	var balance *big.Int
+	err := e.readState(blockNr, func(stateDB *evm_state.StateDB) {
+		balance = stateDB.GetBalance(address)
+	})
+	return (*hexutil.Big)(balance), err
+}
+
+// GetCode returns the code of the contract at the address
+func (e *EthAPI) GetCode(address evm_common.Address, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	var code []byte
+	err := e.readState(blockNr, func(stateDB *evm_state.StateDB) {
+		code = stateDB.GetCode(address)
+	})
+	return code, err
+}
+
+// GetStorageAt returns the value of the storage slot of the contract
+func (e *EthAPI) GetStorageAt(address evm_common.Address, key string, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	var value evm_common.Hash
+	err := e.readState(blockNr, func(stateDB *evm_state.StateDB) {
+		value = stateDB.GetState(address, evm_common.HexToHash(key))
+	})
+	return value.Bytes(), err
+}
+
+// GetTransactionCount returns the nonce of the address
+func (e *EthAPI) GetTransactionCount(address evm_common.Address, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
+	var nonce uint64
+	err := e.readState(blockNr, func(stateDB *evm_state.StateDB) {
+		nonce = stateDB.GetNonce(address)
+	})
+	return hexutil.Uint64(nonce), err
+}
+
+// Call executes the message call without creating a transaction
+func (e *EthAPI) Call(args EthCallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	res, _, err := e.doCall(args, blockNr)
+	return res, err
+}
+
+// EstimateGas returns the lowest gas limit the message call or the contract
+// creation succeeds with on the best block
+func (e *EthAPI) EstimateGas(args EthCallArgs) (hexutil.Uint64, error) {
+	value := args.Value.ToInt()
+	if !value.IsUint64() {
+		return 0, errEthValueTooHigh
+	}
+
+	var to []byte
+	if args.To != nil {
+		to = args.To.Bytes()
+	}
+	gas, err := e.chain.EstimateGas(args.From.Bytes(), to, value.Uint64(), args.Data)
+	return hexutil.Uint64(gas), err
+}
+
+// EthFilterCriteria is the filter object of eth_getLogs. The address is
+// either one address or a list of addresses, every topic position is null,
+// one topic or a list of alternative topics.
+type EthFilterCriteria struct {
+	BlockHash *evm_common.Hash
+	FromBlock *rpc.BlockNumber
+	ToBlock   *rpc.BlockNumber
+	Addresses [][]byte
+	Topics    [][][]byte
+}
+
+func (c *EthFilterCriteria) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		BlockHash *evm_common.Hash  `json:"blockHash"`
+		FromBlock *rpc.BlockNumber  `json:"fromBlock"`
+		ToBlock   *rpc.BlockNumber  `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	c.BlockHash, c.FromBlock, c.ToBlock = raw.BlockHash, raw.FromBlock, raw.ToBlock
+	addresses, err := decodeOneOrMany(raw.Address, func(b []byte) error {
+		var address evm_common.Address
+		return address.UnmarshalJSON(b)
+	})
+	if err != nil {
+		return err
+	}
+	c.Addresses = addresses
+
+	for _, rawTopic := range raw.Topics {
+		topics, err := decodeOneOrMany(rawTopic, func(b []byte) error {
+			var topic evm_common.Hash
+			return topic.UnmarshalJSON(b)
+		})
+		if err != nil {
+			return err
+		}
+		c.Topics = append(c.Topics, topics)
+	}
+	return nil
+}
+
+// decodeOneOrMany decodes null, a hex string or a list of hex strings, check
+// validates the format of every item.
+func decodeOneOrMany(data json.RawMessage, check func([]byte) error) ([][]byte, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	items := []json.RawMessage{}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+	} else {
+		items = append(items, data)
+	}
+
+	result := [][]byte{}
+	for _, item := range items {
+		if err := check(item); err != nil {
+			return nil, err
+		}
+
+		var b hexutil.Bytes
+		if err := b.UnmarshalJSON(item); err != nil {
+			return nil, err
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// GetLogs returns the main chain logs matching the filter
+func (e *EthAPI) GetLogs(crit EthFilterCriteria) ([]*evm_types.Log, error) {
+	filter := &protocol.LogFilter{Addresses: crit.Addresses, Topics: crit.Topics}
+	if crit.BlockHash != nil {
+		hash := bc.NewHash(*crit.BlockHash)
+		if !e.chain.InMainChain(hash) {
+			return nil, errEthBlockHash
+		}
+
+		header, err := e.chain.GetHeaderByHash(&hash)
+		if err != nil {
+			return nil, err
+		}
+		filter.FromHeight, filter.ToHeight = header.Height, header.Height
+	} else {
+		filter.FromHeight, filter.ToHeight = e.blockHeight(crit.FromBlock), e.blockHeight(crit.ToBlock)
+	}
+
+	entries, err := e.chain.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := []*evm_types.Log{}
+	for _, entry := range entries {
+		topics := []evm_common.Hash{}
+		for _, topic := range entry.Log.Topics {
+			topics = append(topics, evm_common.BytesToHash(topic))
+		}
+		logs = append(logs, &evm_types.Log{
+			Address:     evm_common.BytesToAddress(entry.Log.Address),
+			Topics:      topics,
+			Data:        entry.Log.Data,
+			BlockNumber: entry.BlockHeight,
+			TxHash:      evm_common.Hash(entry.TxID.Byte32()),
+			TxIndex:     uint(entry.TxIndex),
+			BlockHash:   evm_common.Hash(entry.BlockHash.Byte32()),
+			Index:       uint(entry.LogIndex),
+		})
+	}
+	return logs, nil
+}
+
+// blockHeight resolves an optional block number, missing means the best block
+func (e *EthAPI) blockHeight(blockNr *rpc.BlockNumber) uint64 {
+	if blockNr == nil || *blockNr == rpc.LatestBlockNumber || *blockNr == rpc.PendingBlockNumber {
+		return e.chain.BestBlockHeight()
+	}
+	return uint64(*blockNr)
+}
+
+// GetBlockByNumber returns the block at the height, the transactions are
+// returned as hashes unless fullTx is set. A missing block is returned as
+// null.
+func (e *EthAPI) GetBlockByNumber(blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	height := e.blockHeight(&blockNr)
+	if height > e.chain.BestBlockHeight() {
+		return nil, nil
+	}
+
+	block, err := e.chain.GetBlockByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	blockHash := block.Hash()
+	txStatus, err := e.chain.GetTransactionStatus(&blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	rawBlock, err := block.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	gasUsed := uint64(0)
+	bloom := new(big.Int)
+	for _, result := range txStatus.VerifyStatus {
+		gasUsed = result.CumulativeGasUsed
+		bloom.Or(bloom, new(big.Int).SetBytes(result.Bloom))
+	}
+
+	transactions := []interface{}{}
+	for i, tx := range block.Transactions {
+		txHash := evm_common.Hash(tx.ID.Byte32())
+		if !fullTx {
+			transactions = append(transactions, txHash)
+			continue
+		}
+
+		transactions = append(transactions, map[string]interface{}{
+			"hash":             txHash,
+			"blockHash":        evm_common.Hash(blockHash.Byte32()),
+			"blockNumber":      hexutil.Uint64(block.Height),
+			"transactionIndex": hexutil.Uint64(i),
+		})
+	}
+
+	return map[string]interface{}{
+		"number":           hexutil.Uint64(block.Height),
+		"hash":             evm_common.Hash(blockHash.Byte32()),
+		"parentHash":       evm_common.Hash(block.PreviousBlockHash.Byte32()),
+		"nonce":            evm_types.EncodeNonce(block.Nonce),
+		"timestamp":        hexutil.Uint64(block.Timestamp),
+		"difficulty":       (*hexutil.Big)(difficulty.CalcWork(block.Bits)),
+		"stateRoot":        evm_common.Hash(block.StateRoot.Byte32()),
+		"transactionsRoot": evm_common.Hash(block.TransactionsMerkleRoot.Byte32()),
+		"logsBloom":        evm_types.BytesToBloom(bloom.Bytes()),
+		"size":             hexutil.Uint64(len(rawBlock)),
+		"gasLimit":         hexutil.Uint64(consensus.MaxBlockGas),
+		"gasUsed":          hexutil.Uint64(gasUsed),
+		"transactions":     transactions,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/doslink/doslink/testutil"
+)
+
+func TestEthFilterCriteria(t *testing.T) {
+	addrA := "0x00000000000000000000000000000000000000aa"
+	addrB := "0x00000000000000000000000000000000000000bb"
+	topic := "0x00000000000000000000000000000000000000000000000000000000000000cc"
+
+	cases := []struct {
+		data      string
+		addresses [][]byte
+		topics    [][][]byte
+		wantErr   bool
+	}{
+		{
+			data: `{}`,
+		},
+		{
+			data:      `{"address":"` + addrA + `"}`,
+			addresses: [][]byte{hexutil.MustDecode(addrA)},
+		},
+		{
+			data:      `{"address":["` + addrA + `","` + addrB + `"]}`,
+			addresses: [][]byte{hexutil.MustDecode(addrA), hexutil.MustDecode(addrB)},
+		},
+		{
+			data:   `{"topics":[null,"` + topic + `",["` + topic + `"]]}`,
+			topics: [][][]byte{nil, {hexutil.MustDecode(topic)}, {hexutil.MustDecode(topic)}},
+		},
+		{
+			data:    `{"address":"0xaa"}`,
+			wantErr: true,
+		},
+		{
+			data:    `{"topics":["0xcc"]}`,
+			wantErr: true,
+		},
+	}
+
+	for i, c := range cases {
+		crit := EthFilterCriteria{}
+		err := json.Unmarshal([]byte(c.data), &crit)
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %d: got error %v, want error %v", i, err, c.wantErr)
+			continue
+		}
+		if c.wantErr {
+			continue
+		}
+
+		if !testutil.DeepEqual(crit.Addresses, c.addresses) {
+			t.Errorf("case %d: got addresses %x, want %x", i, crit.Addresses, c.addresses)
+		}
+		if !testutil.DeepEqual(crit.Topics, c.topics) {
+			t.Errorf("case %d: got topics %x, want %x", i, crit.Topics, c.topics)
+		}
+	}
+}
+
+func TestEthHandler(t *testing.T) {
+	defer os.RemoveAll("temp")
+	a := &API{chain: mockChain(t)}
+	server := httptest.NewServer(a.ethHandler())
+	defer server.Close()
+
+	client, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var height hexutil.Uint64
+	if err := client.Call(&height, "eth_blockNumber"); err != nil {
+		t.Fatal(err)
+	}
+	if height != 0 {
+		t.Errorf("got block number %d, want 0", height)
+	}
+
+	var balance hexutil.Big
+	if err := client.Call(&balance, "eth_getBalance", evm_common.Address{1}, "latest"); err != nil {
+		t.Fatal(err)
+	}
+	if balance.ToInt().Sign() != 0 {
+		t.Errorf("got balance %v, want 0", balance.ToInt())
+	}
+
+	genesis := a.chain.BestBlockHeader()
+	block := map[string]interface{}{}
+	if err := client.Call(&block, "eth_getBlockByNumber", "0x0", false); err != nil {
+		t.Fatal(err)
+	}
+	if want := hexutil.Encode(genesis.Hash().Bytes()); block["hash"] != want {
+		t.Errorf("got block hash %v, want %s", block["hash"], want)
+	}
+
+	var missing map[string]interface{}
+	if err := client.Call(&missing, "eth_getBlockByNumber", "0x5", false); err != nil {
+		t.Fatal(err)
+	}
+	if missing != nil {
+		t.Errorf("got block %v above the best block, want null", missing)
+	}
+
+	var res hexutil.Bytes
+	if err := client.Call(&res, "eth_call", map[string]interface{}{"data": "0x"}, "latest"); err == nil || err.Error() != errEthMissingTo.Error() {
+		t.Errorf("got call error %v, want %v", err, errEthMissingTo)
+	}
+
+	logs := []interface{}{}
+	if err := client.Call(&logs, "eth_getLogs", map[string]interface{}{"blockHash": evm_common.Hash{1}}); err == nil || err.Error() != errEthBlockHash.Error() {
+		t.Errorf("got logs error %v, want %v", err, errEthBlockHash)
+	}
+}
@@ -5,15 +5,96 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/signers"
 	"github.com/doslink/doslink/core/txbuilder"
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/basis/errors"
 )
 
+var errRemoteSigner = errors.New("operation is not supported by the remote signer")
+
+// localHSM return the local HSM, the key management beyond pseudohsm.Signer is
+// not supported by the remote signer
+func (a *API) localHSM() (*pseudohsm.HSM, error) {
+	hsm, ok := a.wallet.Hsm.(*pseudohsm.HSM)
+	if !ok {
+		return nil, errRemoteSigner
+	}
+	return hsm, nil
+}
+
+type createKeyResp struct {
+	*pseudohsm.XPub
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
 func (a *API) pseudohsmCreateKey(ctx context.Context, in struct {
-	Alias    string `json:"alias"`
-	Password string `json:"password"`
+	Alias        string `json:"alias"`
+	Password     string `json:"password"`
+	WithMnemonic bool   `json:"with_mnemonic"`
+	Passphrase   string `json:"passphrase"`
+	KeyScheme    string `json:"key_scheme"`
+}) Response {
+	switch in.KeyScheme {
+	case "", signers.KeySchemeEd25519:
+	case signers.KeySchemeSM2:
+		return a.createSM2Key(in.Alias, in.Password, in.WithMnemonic)
+	default:
+		return NewErrorResponse(errors.WithDetailf(signers.ErrBadKeyScheme, "key scheme=%s", in.KeyScheme))
+	}
+
+	if !in.WithMnemonic {
+		xpub, err := a.wallet.Hsm.XCreate(in.Alias, in.Password)
+		if err != nil {
+			return NewErrorResponse(err)
+		}
+		return NewSuccessResponse(xpub)
+	}
+
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	xpub, words, err := hsm.XCreateMnemonic(in.Alias, in.Password, in.Passphrase)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(&createKeyResp{XPub: xpub, Mnemonic: words})
+}
+
+// createSM2Key creates the SM2 key of the local HSM, the mnemonic keys are
+// ed25519 only
+func (a *API) createSM2Key(alias, password string, withMnemonic bool) Response {
+	if withMnemonic {
+		return NewErrorResponse(errors.WithDetail(signers.ErrBadKeyScheme, "the mnemonic keys are ed25519 only"))
+	}
+
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	xpub, err := hsm.XCreateSM2(alias, password)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(xpub)
+}
+
+func (a *API) pseudohsmRecoverKey(ctx context.Context, in struct {
+	Alias      string `json:"alias"`
+	Password   string `json:"password"`
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase"`
 }) Response {
-	xpub, err := a.wallet.Hsm.XCreate(in.Alias, in.Password)
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	xpub, err := hsm.RecoverKey(in.Alias, in.Password, in.Mnemonic, in.Passphrase)
 	if err != nil {
 		return NewErrorResponse(err)
 	}
@@ -28,7 +109,12 @@ func (a *API) pseudohsmDeleteKey(ctx context.Context, x struct {
 	Password string       `json:"password"`
 	XPub     chainkd.XPub `json:"xpub"`
 }) Response {
-	if err := a.wallet.Hsm.XDelete(x.XPub, x.Password); err != nil {
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	if err := hsm.XDelete(x.XPub, x.Password); err != nil {
 		return NewErrorResponse(err)
 	}
 	return NewSuccessResponse(nil)
@@ -65,8 +151,13 @@ func (a *API) pseudohsmResetPassword(ctx context.Context, ins struct {
 	OldPassword string       `json:"old_password"`
 	NewPassword string       `json:"new_password"`
 }) Response {
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
 	resp := &ResetPasswordResp{Changed: false}
-	if err := a.wallet.Hsm.ResetPassword(ins.XPub, ins.OldPassword, ins.NewPassword); err != nil {
+	if err := hsm.ResetPassword(ins.XPub, ins.OldPassword, ins.NewPassword); err != nil {
 		return NewSuccessResponse(resp)
 	}
 	resp.Changed = true
@@ -82,8 +173,13 @@ func (a *API) pseudohsmCheckPassword(ctx context.Context, ins struct {
 	XPub     chainkd.XPub `json:"xpub"`
 	Password string       `json:"password"`
 }) Response {
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
 	resp := &CheckPasswordResp{CheckResult: false}
-	if _, err := a.wallet.Hsm.LoadChainKDKey(ins.XPub, ins.Password); err != nil {
+	if _, err := hsm.LoadChainKDKey(ins.XPub, ins.Password); err != nil {
 		return NewSuccessResponse(resp)
 	}
 	resp.CheckResult = true
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+)
+
+// LogResp is a contract log returned by the getLogs API
+type LogResp struct {
+	Address     chainjson.HexBytes   `json:"address"`
+	Topics      []chainjson.HexBytes `json:"topics"`
+	Data        chainjson.HexBytes   `json:"data"`
+	BlockHash   bc.Hash              `json:"block_hash"`
+	BlockHeight uint64               `json:"block_height"`
+	TxID        bc.Hash              `json:"tx_id"`
+	TxIndex     uint32               `json:"tx_index"`
+	LogIndex    uint32               `json:"log_index"`
+}
+
+// POST /get-logs
+// an absent to_height defaults to the best block height. topics are
+// positional, the i-th list holds the alternatives of the i-th topic and an
+// empty list matches any topic.
+func (a *API) getLogs(ctx context.Context, in struct {
+	FromHeight uint64                 `json:"from_height"`
+	ToHeight   *uint64                `json:"to_height"`
+	Addresses  []chainjson.HexBytes   `json:"addresses"`
+	Topics     [][]chainjson.HexBytes `json:"topics"`
+}) Response {
+	filter := &protocol.LogFilter{FromHeight: in.FromHeight}
+	if in.ToHeight != nil {
+		filter.ToHeight = *in.ToHeight
+	} else {
+		filter.ToHeight = a.chain.BestBlockHeight()
+	}
+	for _, address := range in.Addresses {
+		filter.Addresses = append(filter.Addresses, address)
+	}
+	for _, alternatives := range in.Topics {
+		topics := [][]byte{}
+		for _, topic := range alternatives {
+			topics = append(topics, topic)
+		}
+		filter.Topics = append(filter.Topics, topics)
+	}
+
+	entries, err := a.chain.GetLogs(filter)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	logs := []*LogResp{}
+	for _, entry := range entries {
+		var topics []chainjson.HexBytes
+		for _, topic := range entry.Log.Topics {
+			topics = append(topics, topic)
+		}
+		logs = append(logs, &LogResp{
+			Address:     entry.Log.Address,
+			Topics:      topics,
+			Data:        entry.Log.Data,
+			BlockHash:   entry.BlockHash,
+			BlockHeight: entry.BlockHeight,
+			TxID:        entry.TxID,
+			TxIndex:     entry.TxIndex,
+			LogIndex:    entry.LogIndex,
+		})
+	}
+	return NewSuccessResponse(map[string]interface{}{"logs": logs})
+}
@@ -38,8 +38,12 @@ func (a *API) listAccounts(ctx context.Context, filter struct {
 		return NewErrorResponse(err)
 	}
 
+	token := restrictedToken(ctx)
 	annotatedAccounts := []query.AnnotatedAccount{}
 	for _, acc := range accounts {
+		if token != nil && !token.HasAccount(acc.ID, acc.Alias) {
+			continue
+		}
 		annotatedAccounts = append(annotatedAccounts, *account.Annotated(acc))
 	}
 
@@ -78,7 +82,20 @@ func (a *API) listBalances(ctx context.Context) Response {
 	if err != nil {
 		return NewErrorResponse(err)
 	}
-	return NewSuccessResponse(balances)
+
+	token := restrictedToken(ctx)
+	resp := make([]interface{}, 0, len(balances))
+	for _, balance := range balances {
+		if token == nil || token.HasAccount(balance.AccountID, balance.Alias) {
+			resp = append(resp, balance)
+		}
+	}
+	for _, balance := range a.wallet.GetTokenBalances("") {
+		if token == nil || token.HasAccount(balance.AccountID, balance.Alias) {
+			resp = append(resp, balance)
+		}
+	}
+	return NewSuccessResponse(resp)
 }
 
 // POST /get-transaction
@@ -97,6 +114,9 @@ func (a *API) getTransaction(ctx context.Context, txInfo struct {
 		}
 	}
 
+	if token := restrictedToken(ctx); token != nil && len(grantedTxs(token, []*query.AnnotatedTx{annotatedTx})) == 0 {
+		return NewErrorResponse(errors.WithDetailf(errNotAuthorized, "transaction %s is not of a granted account", txInfo.TxID))
+	}
 	return NewSuccessResponse(annotatedTx)
 }
 
@@ -137,6 +157,10 @@ func (a *API) listTransactions(ctx context.Context, filter struct {
 		}
 	}
 
+	if token := restrictedToken(ctx); token != nil {
+		transactions = grantedTxs(token, transactions)
+	}
+
 	if filter.Detail == false {
 		txSummary := a.wallet.GetTransactionsSummary(transactions)
 		start, end := getPageRange(len(txSummary), filter.From, filter.Count)
@@ -260,8 +284,12 @@ func (a *API) listUnspentOutputs(ctx context.Context, filter struct {
 }) Response {
 	accountUTXOs := a.wallet.GetAccountUtxos(filter.ID, filter.Unconfirmed, filter.SmartContract)
 
+	token := restrictedToken(ctx)
 	UTXOs := []query.AnnotatedUTXO{}
 	for _, utxo := range accountUTXOs {
+		if token != nil && !a.grantedAccount(token, utxo.AccountID) {
+			continue
+		}
 		UTXOs = append([]query.AnnotatedUTXO{{
 			AccountID:           utxo.AccountID,
 			OutputID:            utxo.OutputID.String(),
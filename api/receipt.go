@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/vm/state"
+)
+
+// TxReceiptResp is the resp struct for getTransactionReceipt API
+type TxReceiptResp struct {
+	TxID              bc.Hash               `json:"tx_id"`
+	BlockHash         bc.Hash               `json:"block_hash"`
+	BlockHeight       uint64                `json:"block_height"`
+	TxIndex           uint32                `json:"tx_index"`
+	StatusFail        bool                  `json:"status_fail"`
+	RevertData        chainjson.HexBytes    `json:"revert_data,omitempty"`
+	RevertReason      string                `json:"revert_reason,omitempty"`
+	GasUsed           uint64                `json:"gas_used"`
+	CumulativeGasUsed uint64                `json:"cumulative_gas_used"`
+	ContractAddress   chainjson.HexBytes    `json:"contract_address,omitempty"`
+	Logs              []*query.AnnotatedLog `json:"logs"`
+	LogsBloom         chainjson.HexBytes    `json:"logs_bloom,omitempty"`
+}
+
+// POST /get-transaction-receipt
+func (a *API) getTransactionReceipt(ctx context.Context, in struct {
+	TxID bc.Hash `json:"tx_id"`
+}) Response {
+	location, err := a.chain.GetTransactionLocation(&in.TxID)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	header, err := a.chain.GetHeaderByHash(&location.BlockHash)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	txStatus, err := a.chain.GetTransactionStatus(&location.BlockHash)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	result, err := txStatus.GetVerifyResult(int(location.Position))
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	resp := &TxReceiptResp{
+		TxID:              in.TxID,
+		BlockHash:         location.BlockHash,
+		BlockHeight:       header.Height,
+		TxIndex:           location.Position,
+		StatusFail:        result.StatusFail,
+		RevertData:        result.RevertData,
+		GasUsed:           result.GasUsed,
+		CumulativeGasUsed: result.CumulativeGasUsed,
+		ContractAddress:   result.ContractAddress,
+		Logs:              []*query.AnnotatedLog{},
+		LogsBloom:         result.Bloom,
+	}
+	resp.RevertReason, _ = state.UnpackRevert(result.RevertData)
+	for _, log := range result.Logs {
+		var topics []chainjson.HexBytes
+		for _, topic := range log.Topics {
+			topics = append(topics, topic)
+		}
+		resp.Logs = append(resp.Logs,
+			&query.AnnotatedLog{
+				Address: log.Address,
+				Topics:  topics,
+				Data:    log.Data,
+			},
+		)
+	}
+	return NewSuccessResponse(resp)
+}
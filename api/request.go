@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"strings"
 
 	"github.com/doslink/doslink/consensus"
@@ -33,6 +35,9 @@ func (a *API) completeMissingIDs(ctx context.Context, br *BuildRequest) error {
 		if err := a.completeMissingAccountID(m, i, ctx); err != nil {
 			return err
 		}
+		if err := a.completeContractInput(m, i); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -68,3 +73,36 @@ func (a *API) completeMissingAccountID(m map[string]interface{}, index int, ctx
 	}
 	return nil
 }
+
+// completeContractInput packs the input of the sendto_contract action from
+// the method name and the JSON arguments with the registered contract ABI
+func (a *API) completeContractInput(m map[string]interface{}, index int) error {
+	method, _ := m["method"].(string)
+	input, _ := m["input"].(string)
+	if m["type"] != "sendto_contract" || method == "" || input != "" {
+		return nil
+	}
+
+	to, _ := m["to"].(string)
+	address, err := hex.DecodeString(to)
+	if err != nil {
+		return errors.WithDetailf(ErrBadAction, "invalid contract address %s on action %d", to, index)
+	}
+
+	c, err := a.wallet.ContractReg.Find(address)
+	if err != nil {
+		return errors.WithDetailf(err, "on action %d", index)
+	}
+
+	args, err := json.Marshal(m["args"])
+	if err != nil {
+		return errors.WithDetailf(ErrBadAction, "invalid contract arguments on action %d", index)
+	}
+
+	data, err := c.Pack(method, args)
+	if err != nil {
+		return errors.WithDetailf(err, "on action %d", index)
+	}
+	m["input"] = hex.EncodeToString(data)
+	return nil
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/vm/evm"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+)
+
+// the tracers of /trace-transaction
+const (
+	structLogTracer = "struct_logs"
+	callTracer      = "call_tracer"
+	prestateTracer  = "prestate_tracer"
+)
+
+var errUnknownTracer = errors.New("unknown tracer")
+
+// TxTraceResp is the resp struct for traceTransaction API
+type TxTraceResp struct {
+	TxID       bc.Hash            `json:"tx_id"`
+	BlockHash  bc.Hash            `json:"block_hash"`
+	TxIndex    uint32             `json:"tx_index"`
+	GasUsed    uint64             `json:"gas_used"`
+	StatusFail bool               `json:"status_fail"`
+	Error      string             `json:"error,omitempty"`
+	Tracer     string             `json:"tracer"`
+	ReturnData chainjson.HexBytes `json:"return_data,omitempty"`
+
+	StructLogs []evm.StructLog                          `json:"struct_logs,omitempty"`
+	Calls      []*evm.CallFrame                         `json:"calls,omitempty"`
+	Pre        map[evm_common.Address]*evm.AccountState `json:"pre,omitempty"`
+	Post       map[evm_common.Address]*evm.AccountState `json:"post,omitempty"`
+}
+
+// POST /trace-transaction
+func (a *API) traceTransaction(ctx context.Context, in struct {
+	TxID           bc.Hash `json:"tx_id"`
+	Tracer         string  `json:"tracer"`
+	DisableMemory  bool    `json:"disable_memory"`
+	DisableStack   bool    `json:"disable_stack"`
+	DisableStorage bool    `json:"disable_storage"`
+	Limit          int     `json:"limit"`
+}) Response {
+	logConfig := &evm.LogConfig{
+		DisableMemory:  in.DisableMemory,
+		DisableStack:   in.DisableStack,
+		DisableStorage: in.DisableStorage,
+		Limit:          in.Limit,
+	}
+	resp, err := traceTx(a.chain, &in.TxID, in.Tracer, logConfig)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(resp)
+}
+
+// traceTx re-executes the mined tx with the named tracer, the log config
+// only applies to the struct logs
+func traceTx(chain *protocol.Chain, txID *bc.Hash, tracer string, logConfig *evm.LogConfig) (*TxTraceResp, error) {
+	if tracer == "" {
+		tracer = structLogTracer
+	}
+
+	switch tracer {
+	case structLogTracer, callTracer, prestateTracer:
+	default:
+		return nil, errors.WithDetailf(errUnknownTracer, "tracer %s", tracer)
+	}
+
+	var (
+		structLogger *evm.StructLogger
+		calls        *evm.CallTracer
+		prestate     *evm.PrestateTracer
+	)
+	newTracer := func(stateDB evm.StateDB) evm.Tracer {
+		switch tracer {
+		case callTracer:
+			calls = evm.NewCallTracer()
+			return calls
+		case prestateTracer:
+			prestate = evm.NewPrestateTracer(stateDB)
+			return prestate
+		default:
+			structLogger = evm.NewStructLogger(&evm.LogConfig{
+				DisableMemory:  logConfig.DisableMemory,
+				DisableStack:   logConfig.DisableStack,
+				DisableStorage: logConfig.DisableStorage,
+				Limit:          logConfig.Limit,
+			})
+			return structLogger
+		}
+	}
+
+	trace, err := chain.TraceTransaction(txID, newTracer)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &TxTraceResp{
+		TxID:       *txID,
+		BlockHash:  trace.BlockHash,
+		TxIndex:    trace.Position,
+		GasUsed:    trace.GasUsed,
+		StatusFail: trace.StatusFail,
+		Tracer:     tracer,
+	}
+	if trace.Err != nil {
+		resp.Error = trace.Err.Error()
+	}
+
+	switch {
+	case structLogger != nil:
+		resp.StructLogs = structLogger.StructLogs()
+		resp.ReturnData = structLogger.Output()
+	case calls != nil:
+		resp.Calls = calls.Calls()
+	case prestate != nil:
+		resp.Pre, resp.Post = prestate.Diff()
+	}
+	return resp, nil
+}
+
+// DebugAPI implements the debug namespace of the JSON-RPC server
+type DebugAPI struct {
+	chain *protocol.Chain
+}
+
+// DebugTraceConfig is the trace option of debug_traceTransaction
+type DebugTraceConfig struct {
+	*evm.LogConfig
+	Tracer *string `json:"tracer"`
+}
+
+// TraceTransaction returns the trace of the mined tx, the tracer is one of
+// callTracer and prestateTracer, the struct logs are returned by default
+func (d *DebugAPI) TraceTransaction(hash evm_common.Hash, config *DebugTraceConfig) (*TxTraceResp, error) {
+	tracer, logConfig := structLogTracer, &evm.LogConfig{}
+	if config != nil {
+		if config.LogConfig != nil {
+			logConfig = config.LogConfig
+		}
+		if config.Tracer != nil {
+			tracer = *config.Tracer
+			if name, ok := debugTracers[tracer]; ok {
+				tracer = name
+			}
+		}
+	}
+
+	txID := bc.NewHash(hash)
+	return traceTx(d.chain, &txID, tracer, logConfig)
+}
+
+// debugTracers maps the tracer names of debug_traceTransaction
+var debugTracers = map[string]string{
+	"callTracer":     callTracer,
+	"prestateTracer": prestateTracer,
+}
@@ -187,6 +187,7 @@ type EstimateTxGasResp struct {
 	TotalUny   int64 `json:"total_uny"`
 	StorageUny int64 `json:"storage_uny"`
 	VMUny      int64 `json:"vm_uny"`
+	EVMUny     int64 `json:"evm_uny"`
 }
 
 // EstimateTxGas estimate consumed uny for transaction
@@ -249,11 +250,18 @@ func EstimateTxGas(template txbuilder.Template, chain *protocol.Chain) (*Estimat
 				sigInst := template.SigningInstructions[pos]
 				totalP2WSHGas += estimateP2WSHGas(sigInst)
 			}
+			// already estimated by the builder
+			if template.EstimatedGas > 0 {
+				continue
+			}
 		case *bc.Call:
 			if segwit.IsP2WSHScript(e.From.Code) {
 				sigInst := template.SigningInstructions[pos]
 				totalP2WSHGas += estimateP2WSHGas(sigInst)
 			}
+			if template.EstimatedGas > 0 {
+				continue
+			}
 		case *bc.Contract:
 			if segwit.IsP2WSHScript(e.From.Code) {
 				sigInst := template.SigningInstructions[pos]
@@ -293,8 +301,13 @@ func EstimateTxGas(template txbuilder.Template, chain *protocol.Chain) (*Estimat
 
 	}
 
+	totalEVMGas, err := evmGasToVMGas(template.EstimatedGas)
+	if err != nil {
+		return nil, err
+	}
+
 	// total estimate gas
-	totalGas := totalTxSizeGas + totalP2WSHGas + totalVMGas
+	totalGas := totalTxSizeGas + totalP2WSHGas + totalVMGas + totalEVMGas
 
 	// rounding totalUny with base rate 100000
 	totalUny := float64(totalGas*consensus.VMGasRate) / defaultBaseRate
@@ -309,14 +322,27 @@ func EstimateTxGas(template txbuilder.Template, chain *protocol.Chain) (*Estimat
 		WithField("totalP2WSHGas", totalP2WSHGas).
 		WithField("VMGas", totalVMGas).
 		WithField("totalVMGas", totalP2WSHGas+totalVMGas).
+		WithField("totalEVMGas", totalEVMGas).
 		Println("EstimateTxGas")
 	return &EstimateTxGasResp{
 		TotalUny:   estimateUny,
 		StorageUny: totalTxSizeGas * consensus.VMGasRate,
 		VMUny:      (totalP2WSHGas + totalVMGas) * consensus.VMGasRate,
+		EVMUny:     totalEVMGas * consensus.VMGasRate,
 	}, nil
 }
 
+// evmGasToVMGas converts the EVM gas estimated for the contract messages of
+// the tx to vm gas. Only the messages without a gas price are estimated, the
+// vm charges the EVM gas they use one for one, the others buy their gas from
+// the native asset balance of the sender.
+func evmGasToVMGas(gas uint64) (int64, error) {
+	if gas > math.MaxInt64/uint64(consensus.VMGasRate) {
+		return 0, errors.New("calculate evm gas got a math error")
+	}
+	return int64(gas), nil
+}
+
 // estimate p2wsh gas.
 // OP_CHECKMULTISIG consume (984 * a - 72 * b - 63) gas,
 // where a represent the num of public keys, and b represent the num of quorum.
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// POST /create-transaction-feed
+func (a *API) createTxFeed(ctx context.Context, in struct {
+	Alias  string `json:"alias"`
+	Filter string `json:"filter"`
+}) Response {
+	feed, err := a.wallet.TxFeedTracker.Create(in.Alias, in.Filter)
+	if err != nil {
+		log.WithField("error", err).Error("Add TxFeed Failed")
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(feed)
+}
+
+// POST /get-transaction-feed
+func (a *API) getTxFeed(ctx context.Context, in struct {
+	Alias string `json:"alias"`
+}) Response {
+	feed, err := a.wallet.TxFeedTracker.Get(in.Alias)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(feed)
+}
+
+// POST /list-transaction-feeds
+func (a *API) listTxFeeds(ctx context.Context) Response {
+	return NewSuccessResponse(a.wallet.TxFeedTracker.List())
+}
+
+// POST /delete-transaction-feed
+func (a *API) deleteTxFeed(ctx context.Context, in struct {
+	Alias string `json:"alias"`
+}) Response {
+	if err := a.wallet.TxFeedTracker.Delete(in.Alias); err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(nil)
+}
+
+// POST /update-transaction-feed
+func (a *API) updateTxFeed(ctx context.Context, in struct {
+	Alias  string `json:"alias"`
+	Filter string `json:"filter"`
+}) Response {
+	feed, err := a.wallet.TxFeedTracker.Update(in.Alias, in.Filter)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(feed)
+}
+
+// POST /list-transaction-feed-txs
+func (a *API) listTxFeedTxs(ctx context.Context, in struct {
+	Alias string `json:"alias"`
+	After string `json:"after"`
+	Count int    `json:"count"`
+}) Response {
+	txs, next, err := a.wallet.TxFeedTracker.ListTransactions(in.Alias, in.After, in.Count)
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	// the account restricted token only sees the txs of its accounts
+	if token := restrictedToken(ctx); token != nil {
+		txs = grantedTxs(token, txs)
+	}
+
+	return NewSuccessResponse(map[string]interface{}{
+		"transactions": txs,
+		"next":         next,
+	})
+}
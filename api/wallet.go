@@ -6,6 +6,7 @@ import (
 	"github.com/doslink/doslink/core/account"
 	"github.com/doslink/doslink/core/asset"
 	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/query"
 	"github.com/doslink/doslink/basis/errors"
 )
 
@@ -22,7 +23,12 @@ type WalletImage struct {
 }
 
 func (a *API) restoreWalletImage(ctx context.Context, image WalletImage) Response {
-	if err := a.wallet.Hsm.Restore(image.KeyImages); err != nil {
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	if err := hsm.Restore(image.KeyImages); err != nil {
 		return NewErrorResponse(errors.Wrap(err, "restore key images"))
 	}
 	if err := a.wallet.AssetReg.Restore(image.AssetImage); err != nil {
@@ -36,7 +42,12 @@ func (a *API) restoreWalletImage(ctx context.Context, image WalletImage) Respons
 }
 
 func (a *API) backupWalletImage() Response {
-	keyImages, err := a.wallet.Hsm.Backup()
+	hsm, err := a.localHSM()
+	if err != nil {
+		return NewErrorResponse(err)
+	}
+
+	keyImages, err := hsm.Backup()
 	if err != nil {
 		return NewErrorResponse(errors.Wrap(err, "backup key images"))
 	}
@@ -77,3 +88,29 @@ func (a *API) getWalletInfo() Response {
 		WalletHeight:    walletStatus.WorkHeight,
 	})
 }
+
+func (a *API) abandonTransaction(ctx context.Context, ins struct {
+	TxID string `json:"tx_id"`
+}) Response {
+	if token := restrictedToken(ctx); token != nil {
+		tx, err := a.wallet.GetUnconfirmedTxByTxID(ins.TxID)
+		if err != nil {
+			return NewErrorResponse(err)
+		}
+		if len(grantedTxs(token, []*query.AnnotatedTx{tx})) == 0 {
+			return NewErrorResponse(errors.WithDetailf(errNotAuthorized, "transaction %s is not of a granted account", ins.TxID))
+		}
+	}
+
+	if err := a.wallet.AbandonTransaction(ins.TxID); err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(nil)
+}
+
+func (a *API) rebroadcastTransactions() Response {
+	if err := a.wallet.RebroadcastTxs(); err != nil {
+		return NewErrorResponse(err)
+	}
+	return NewSuccessResponse(nil)
+}
@@ -0,0 +1,126 @@
+// Package mnemonic implements the BIP39 mnemonic of the key seed.
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/doslink/doslink/basis/errors"
+)
+
+const (
+	// DefaultEntropyBits is the entropy size of a 12 words mnemonic
+	DefaultEntropyBits = 128
+	seedIterations     = 2048
+	seedSize           = 64
+)
+
+var (
+	ErrEntropySize     = errors.New("entropy size must be a multiple of 32 bits in [128, 256]")
+	ErrInvalidMnemonic = errors.New("invalid mnemonic")
+)
+
+var wordIndex = make(map[string]int, len(englishWords))
+
+func init() {
+	for i, word := range englishWords {
+		wordIndex[word] = i
+	}
+}
+
+func checkEntropyBits(bits int) error {
+	if bits%32 != 0 || bits < 128 || bits > 256 {
+		return errors.WithDetailf(ErrEntropySize, "entropy size %d", bits)
+	}
+	return nil
+}
+
+// NewEntropy return the random entropy of the bits size
+func NewEntropy(bits int) ([]byte, error) {
+	if err := checkEntropyBits(bits); err != nil {
+		return nil, err
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic encodes the entropy with its checksum into the words, every
+// word carries 11 bits
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if err := checkEntropyBits(bits); err != nil {
+		return "", err
+	}
+
+	checksumBits := uint(bits / 32)
+	checksum := sha256.Sum256(entropy)
+	data := new(big.Int).SetBytes(entropy)
+	data.Lsh(data, checksumBits)
+	data.Or(data, big.NewInt(int64(checksum[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+int(checksumBits))/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		index := new(big.Int).And(data, mask)
+		words[i] = englishWords[index.Int64()]
+		data.Rsh(data, 11)
+	}
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy decodes the mnemonic and verifies its checksum
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words)%3 != 0 || len(words) < 12 || len(words) > 24 {
+		return nil, errors.WithDetailf(ErrInvalidMnemonic, "%d words", len(words))
+	}
+
+	data := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[strings.ToLower(word)]
+		if !ok {
+			return nil, errors.WithDetailf(ErrInvalidMnemonic, "unknown word %s", word)
+		}
+		data.Lsh(data, 11)
+		data.Or(data, big.NewInt(int64(index)))
+	}
+
+	checksumBits := uint(len(words) * 11 / 33)
+	checksum := new(big.Int).And(data, big.NewInt(1<<checksumBits-1))
+	data.Rsh(data, checksumBits)
+
+	entropy := make([]byte, int(checksumBits)*4)
+	dataBytes := data.Bytes()
+	copy(entropy[len(entropy)-len(dataBytes):], dataBytes)
+
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, errors.WithDetail(ErrInvalidMnemonic, "checksum mismatch")
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid check whether the words and the checksum of the mnemonic are valid
+func IsMnemonicValid(mnemonic string) bool {
+	_, err := MnemonicToEntropy(mnemonic)
+	return err == nil
+}
+
+// NewSeed derives the 64 bytes seed from the mnemonic and the passphrase
+func NewSeed(mnemonic string, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(mnemonic)), " ")
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), seedIterations, seedSize, sha512.New), nil
+}
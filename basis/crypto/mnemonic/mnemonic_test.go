@@ -0,0 +1,92 @@
+package mnemonic
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMnemonic(t *testing.T) {
+	cases := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		},
+		{
+			entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+			mnemonic: strings.Repeat("abandon ", 23) + "art",
+		},
+	}
+
+	for i, c := range cases {
+		entropy, _ := hex.DecodeString(c.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mnemonic != c.mnemonic {
+			t.Errorf("case %d: mnemonic %s, want %s", i, mnemonic, c.mnemonic)
+		}
+
+		decoded, err := MnemonicToEntropy(mnemonic)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, entropy) {
+			t.Errorf("case %d: entropy %x, want %s", i, decoded, c.entropy)
+		}
+
+		if c.seed == "" {
+			continue
+		}
+		seed, err := NewSeed(mnemonic, "TREZOR")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(seed) != c.seed {
+			t.Errorf("case %d: seed %x, want %s", i, seed, c.seed)
+		}
+	}
+}
+
+func TestInvalidMnemonic(t *testing.T) {
+	cases := []string{
+		"",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon doslink",
+	}
+
+	for i, c := range cases {
+		if IsMnemonicValid(c) {
+			t.Errorf("case %d: mnemonic %q is valid", i, c)
+		}
+	}
+
+	entropy, err := NewEntropy(DefaultEntropyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := NewMnemonic(entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsMnemonicValid(strings.ToUpper(mnemonic)) {
+		t.Errorf("mnemonic %s is invalid", mnemonic)
+	}
+}
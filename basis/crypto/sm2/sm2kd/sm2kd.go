@@ -0,0 +1,253 @@
+// Package sm2kd implements the hierarchical deterministic keys of the SM2
+// curve, the extended keys have the same layout as the chainkd keys.
+package sm2kd
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"io"
+	"math/big"
+
+	"github.com/doslink/doslink/basis/crypto/sm2"
+)
+
+const (
+	// PublicKeySize is the size of the compressed SM2 public key.
+	PublicKeySize = 33
+	// SignatureSize is the size of the r||s SM2 signature.
+	SignatureSize = 64
+)
+
+// defaultUID is the default user ID of GM/T 0009 to compute the Z value.
+var defaultUID = []byte("1234567812345678")
+
+var curve = sm2.P256Sm2()
+
+type (
+	//XPrv external private key, the private scalar followed by the chain code
+	XPrv [64]byte
+	//XPub external public key, the x coordinate followed by the chain code
+	XPub [64]byte
+)
+
+// NewXPrv takes a source of random bytes and produces a new XPrv.
+// If r is nil, crypto/rand.Reader is used.
+func NewXPrv(r io.Reader) (xprv XPrv, err error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	var entropy [32]byte
+	_, err = io.ReadFull(r, entropy[:])
+	if err != nil {
+		return xprv, err
+	}
+	return RootXPrv(entropy[:]), nil
+}
+
+// RootXPrv takes a seed binary string and produces a new xprv.
+func RootXPrv(seed []byte) (xprv XPrv) {
+	h := hmac.New(sha512.New, []byte("SM2 seed"))
+	h.Write(seed)
+	h.Sum(xprv[:0])
+	setScalar(xprv[:32], toScalar(xprv[:32]))
+	return
+}
+
+// XPub derives an extended public key from a given xprv.
+func (xprv XPrv) XPub() (xpub XPub) {
+	x, _ := curve.ScalarBaseMult(xprv[:32])
+	setScalar(xpub[:32], x)
+	copy(xpub[32:], xprv[32:])
+	return
+}
+
+// Child derives a child xprv based on `selector` string and `hardened` flag.
+// If `hardened` is false, child xpub can be derived independently
+// from the parent xpub without using the parent xprv.
+// If `hardened` is true, child key can only be derived from the parent xprv.
+func (xprv XPrv) Child(sel []byte, hardened bool) (res XPrv) {
+	h := hmac.New(sha512.New, xprv[32:])
+	if hardened {
+		h.Write([]byte{'H'})
+		h.Write(xprv[:32])
+	} else {
+		h.Write([]byte{'N'})
+		h.Write(xprv.XPub().Bytes()[:32])
+	}
+	h.Write(sel)
+	h.Sum(res[:0])
+
+	if hardened {
+		setScalar(res[:32], toScalar(res[:32]))
+		return
+	}
+
+	d := new(big.Int).SetBytes(res[:32])
+	d.Add(d, new(big.Int).SetBytes(xprv[:32]))
+	d.Mod(d, curve.Params().N)
+	setScalar(res[:32], evenScalar(d))
+	return
+}
+
+// Child derives a child xpub based on `selector` string.
+// The corresponding child xprv can be derived from the parent xprv
+// using non-hardened derivation: `parentxprv.Child(sel, false)`.
+// It fails for an xpub which is not a point of the curve.
+func (xpub XPub) Child(sel []byte) (res XPub, err error) {
+	pub, ok := decompress(xpub.PublicKey())
+	if !ok {
+		return res, ErrBadXPub
+	}
+
+	h := hmac.New(sha512.New, xpub[32:])
+	h.Write([]byte{'N'})
+	h.Write(xpub[:32])
+	h.Write(sel)
+	h.Sum(res[:0])
+
+	fx, fy := curve.ScalarBaseMult(res[:32])
+	x, y := curve.Add(pub.X, pub.Y, fx, fy)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return res, ErrBadXPub
+	}
+	setScalar(res[:32], x)
+	return res, nil
+}
+
+// Derive generates a child xprv by recursively deriving
+// non-hardened child xprvs over the list of selectors:
+// `Derive([a,b,c,...]) == Child(a).Child(b).Child(c)...`
+func (xprv XPrv) Derive(path [][]byte) XPrv {
+	res := xprv
+	for _, p := range path {
+		res = res.Child(p, false)
+	}
+	return res
+}
+
+// Derive generates a child xpub by recursively deriving
+// non-hardened child xpubs over the list of selectors:
+// `Derive([a,b,c,...]) == Child(a).Child(b).Child(c)...`
+func (xpub XPub) Derive(path [][]byte) (XPub, error) {
+	res := xpub
+	for _, p := range path {
+		var err error
+		if res, err = res.Child(p); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// Sign creates an SM2 signature of the msg with the private key of the xprv.
+func (xprv XPrv) Sign(msg []byte) ([]byte, error) {
+	priv := &sm2.PrivateKey{D: new(big.Int).SetBytes(xprv[:32])}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(xprv[:32])
+
+	r, s, err := sm2.Sm2Sign(priv, msg, defaultUID)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := make([]byte, SignatureSize)
+	setScalar(sig[:32], r)
+	setScalar(sig[32:], s)
+	return sig, nil
+}
+
+// Verify checks an SM2 signature using public key of the xpub.
+func (xpub XPub) Verify(msg []byte, sig []byte) bool {
+	return Verify(xpub.PublicKey(), msg, sig)
+}
+
+// PublicKey extracts the compressed SM2 public key from an xpub.
+func (xpub XPub) PublicKey() []byte {
+	return append([]byte{2}, xpub[:32]...)
+}
+
+// Verify checks an r||s SM2 signature of the msg with the compressed public
+// key.
+func Verify(pubkey, msg, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+	pub, ok := decompress(pubkey)
+	if !ok {
+		return false
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	return sm2.Sm2Verify(pub, msg, defaultUID, r, s)
+}
+
+// ValidPublicKey checks the pubkey is a compressed point of the SM2 curve.
+func ValidPublicKey(pubkey []byte) bool {
+	_, ok := decompress(pubkey)
+	return ok
+}
+
+// decompress parses the compressed public key, unlike sm2.Decompress it
+// rejects the malformed keys rather than panicking.
+func decompress(pubkey []byte) (*sm2.PublicKey, bool) {
+	if len(pubkey) != PublicKeySize || (pubkey[0] != 2 && pubkey[0] != 3) {
+		return nil, false
+	}
+
+	params := curve.Params()
+	x := new(big.Int).SetBytes(pubkey[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, false
+	}
+
+	// y^2 = x^3 - 3x + b
+	y2 := new(big.Int).Mul(x, x)
+	y2.Mul(y2, x)
+	y2.Sub(y2, new(big.Int).Lsh(x, 1))
+	y2.Sub(y2, x)
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+
+	y := new(big.Int).ModSqrt(y2, params.P)
+	if y == nil {
+		return nil, false
+	}
+	if y.Bit(0) != uint(pubkey[0]&1) {
+		y.Sub(params.P, y)
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, false
+	}
+	return &sm2.PublicKey{Curve: curve, X: x, Y: y}, true
+}
+
+// toScalar reduces the hash output to a nonzero private key of even public
+// key.
+func toScalar(b []byte) *big.Int {
+	n1 := new(big.Int).Sub(curve.Params().N, big.NewInt(1))
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, n1)
+	d.Add(d, big.NewInt(1))
+	return evenScalar(d)
+}
+
+// evenScalar negates the private key of odd public key, so the public key is
+// kept as the x coordinate only.
+func evenScalar(d *big.Int) *big.Int {
+	_, y := curve.ScalarBaseMult(setScalar(make([]byte, 32), d))
+	if y.Bit(0) == 1 {
+		d = new(big.Int).Sub(curve.Params().N, d)
+	}
+	return d
+}
+
+// setScalar writes the 32 bytes big endian form of the n into the buf.
+func setScalar(buf []byte, n *big.Int) []byte {
+	b := n.Bytes()
+	for i := range buf[:32-len(b)] {
+		buf[i] = 0
+	}
+	copy(buf[32-len(b):], b)
+	return buf
+}
@@ -0,0 +1,145 @@
+package sm2kd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDerivation(t *testing.T) {
+	root := RootXPrv([]byte{0x01, 0x02, 0x03})
+	path := [][]byte{[]byte{0x01, 0x02, 0x03}, []byte{}, []byte{0xff}, []byte{0x00, 0x00, 0x00, 0x01}, []byte("sm2")}
+
+	for i := 0; i <= len(path); i++ {
+		xprv := root.Derive(path[:i])
+		xpub, err := root.XPub().Derive(path[:i])
+		if err != nil {
+			t.Fatalf("derive %d: %v", i, err)
+		}
+		if xprv.XPub() != xpub {
+			t.Errorf("derive %d: xprv.XPub() = %x, xpub = %x", i, xprv.XPub(), xpub)
+		}
+		if !ValidPublicKey(xpub.PublicKey()) {
+			t.Errorf("derive %d: invalid public key %x", i, xpub.PublicKey())
+		}
+	}
+
+	hardened := root.Child([]byte{0x01}, true)
+	child, err := root.XPub().Child([]byte{0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hardened.XPub() == child {
+		t.Error("hardened child is the same as non-hardened child")
+	}
+	if child, err = hardened.XPub().Child(nil); err != nil {
+		t.Fatal(err)
+	}
+	if hardened.Child(nil, false).XPub() != child {
+		t.Error("non-hardened child of hardened child mismatch")
+	}
+}
+
+// TestDerivationLevels checks the public derivation of every level agrees
+// with the private one, for the parents derived in both ways
+func TestDerivationLevels(t *testing.T) {
+	for seed := byte(0); seed < 8; seed++ {
+		xprv := RootXPrv([]byte{seed})
+		xpub := xprv.XPub()
+		for level := 0; level < 6; level++ {
+			sel := []byte{seed, byte(level)}
+			if level%3 == 2 {
+				// a hardened level is only derived privately
+				xprv = xprv.Child(sel, true)
+				xpub = xprv.XPub()
+				continue
+			}
+
+			var err error
+			xprv = xprv.Child(sel, false)
+			if xpub, err = xpub.Child(sel); err != nil {
+				t.Fatalf("seed %d level %d: %v", seed, level, err)
+			}
+			if xprv.XPub() != xpub {
+				t.Fatalf("seed %d level %d: xprv.XPub() = %x, xpub = %x", seed, level, xprv.XPub(), xpub)
+			}
+
+			sig, err := xprv.Sign(sel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !xpub.Verify(sel, sig) {
+				t.Errorf("seed %d level %d: signature does not verify", seed, level)
+			}
+		}
+	}
+}
+
+func TestChildBadXPub(t *testing.T) {
+	var xpub XPub
+	copy(xpub[:32], bytes.Repeat([]byte{0xff}, 32))
+	if _, err := xpub.Child([]byte{0x01}); err != ErrBadXPub {
+		t.Errorf("child of bad xpub error = %v, want %v", err, ErrBadXPub)
+	}
+	if _, err := xpub.Derive([][]byte{{0x01}}); err != ErrBadXPub {
+		t.Errorf("derive of bad xpub error = %v, want %v", err, ErrBadXPub)
+	}
+	if _, err := DeriveXPubs([]XPub{xpub}, [][]byte{{0x01}}); err != ErrBadXPub {
+		t.Errorf("derive xpubs of bad xpub error = %v, want %v", err, ErrBadXPub)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	xprv, xpub, err := NewXKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("test message")
+	for i := 0; i < 8; i++ {
+		child := xprv.Derive([][]byte{[]byte{byte(i)}})
+		sig, err := child.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != SignatureSize {
+			t.Fatalf("signature size = %d, want %d", len(sig), SignatureSize)
+		}
+
+		childXPub, err := xpub.Derive([][]byte{[]byte{byte(i)}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !childXPub.Verify(msg, sig) {
+			t.Errorf("child %d: signature does not verify", i)
+		}
+		if childXPub.Verify([]byte("other message"), sig) {
+			t.Errorf("child %d: signature verifies other message", i)
+		}
+		if xpub.Verify(msg, sig) {
+			t.Errorf("child %d: signature verifies with parent key", i)
+		}
+	}
+}
+
+func TestValidPublicKey(t *testing.T) {
+	_, xpub, err := NewXKeys(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		pubkey []byte
+		want   bool
+	}{
+		{xpub.PublicKey(), true},
+		{append([]byte{3}, xpub[:32]...), true},
+		{append([]byte{4}, xpub[:32]...), false},
+		{xpub[:32], false},
+		{append([]byte{2}, bytes.Repeat([]byte{0xff}, 32)...), false},
+	}
+	for i, c := range cases {
+		if got := ValidPublicKey(c.pubkey); got != c.want {
+			t.Errorf("case %d: ValidPublicKey(%x) = %v, want %v", i, c.pubkey, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package sm2kd
+
+import (
+	"io"
+)
+
+// Utility functions
+
+func NewXKeys(r io.Reader) (xprv XPrv, xpub XPub, err error) {
+	xprv, err = NewXPrv(r)
+	if err != nil {
+		return
+	}
+	return xprv, xprv.XPub(), nil
+}
+
+func XPubKeys(xpubs []XPub) [][]byte {
+	res := make([][]byte, 0, len(xpubs))
+	for _, xpub := range xpubs {
+		res = append(res, xpub.PublicKey())
+	}
+	return res
+}
+
+func DeriveXPubs(xpubs []XPub, path [][]byte) ([]XPub, error) {
+	res := make([]XPub, 0, len(xpubs))
+	for _, xpub := range xpubs {
+		d, err := xpub.Derive(path)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+	return res, nil
+}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	jww "github.com/spf13/jwalterweatherman"
@@ -9,6 +10,18 @@ import (
 	"github.com/doslink/doslink/util"
 )
 
+var (
+	tokenScopes    []string
+	tokenAccounts  []string
+	tokenExpiresIn time.Duration
+)
+
+func init() {
+	createAccessTokenCmd.PersistentFlags().StringSliceVar(&tokenScopes, "scopes", nil, "comma delimited route scopes (read-only, wallet, signing, mining, admin), all the routes if empty")
+	createAccessTokenCmd.PersistentFlags().StringSliceVar(&tokenAccounts, "accounts", nil, "comma delimited IDs or aliases of the accounts granted to the token, all the accounts if empty")
+	createAccessTokenCmd.PersistentFlags().DurationVar(&tokenExpiresIn, "expires-in", 0, "lifetime of the token, e.g. 720h, never expires if zero")
+}
+
 var createAccessTokenCmd = &cobra.Command{
 	Use:   "create-access-token <tokenID>",
 	Short: "Create a new access token",
@@ -16,6 +29,12 @@ var createAccessTokenCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		var token accessToken
 		token.ID = args[0]
+		token.Scopes = tokenScopes
+		token.Accounts = tokenAccounts
+		if tokenExpiresIn > 0 {
+			expiresAt := time.Now().Add(tokenExpiresIn)
+			token.ExpiresAt = &expiresAt
+		}
 
 		data, exitCode := util.ClientCall("/create-access-token", &token)
 		if exitCode != util.Success {
@@ -14,6 +14,8 @@ import (
 func init() {
 	createAccountCmd.PersistentFlags().IntVarP(&accountQuorum, "quorom", "q", 1, "quorum must be greater than 0 and less than or equal to the number of signers")
 	createAccountCmd.PersistentFlags().StringVarP(&accountToken, "access", "a", "", "access token")
+	createAccountCmd.PersistentFlags().BoolVar(&accountWatchOnly, "watch-only", false, "watch the xpubs whose keys are held offline")
+	createAccountCmd.PersistentFlags().StringVar(&accountKeyScheme, "key-scheme", "", "key scheme of the xpubs, ed25519 or sm2")
 
 	listAccountsCmd.PersistentFlags().StringVar(&accountID, "id", "", "account ID")
 	listAccountsCmd.PersistentFlags().StringVar(&accountAlias, "alias", "", "account alias")
@@ -26,12 +28,14 @@ func init() {
 }
 
 var (
-	accountID     = ""
-	accountAlias  = ""
-	accountQuorum = 1
-	accountToken  = ""
-	outputID      = ""
-	smartContract = false
+	accountID        = ""
+	accountAlias     = ""
+	accountQuorum    = 1
+	accountToken     = ""
+	accountWatchOnly = false
+	accountKeyScheme = ""
+	outputID         = ""
+	smartContract    = false
 )
 
 var createAccountCmd = &cobra.Command{
@@ -52,6 +56,8 @@ var createAccountCmd = &cobra.Command{
 
 		ins.Quorum = accountQuorum
 		ins.Alias = args[0]
+		ins.KeyScheme = accountKeyScheme
+		ins.WatchOnly = accountWatchOnly
 		ins.AccessToken = accountToken
 
 		data, exitCode := util.ClientCall("/create-account", &ins)
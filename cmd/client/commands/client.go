@@ -125,6 +125,7 @@ func AddCommands() {
 	ClientCmd.AddCommand(updateAssetAliasCmd)
 
 	ClientCmd.AddCommand(getTransactionCmd)
+	ClientCmd.AddCommand(getTransactionReceiptCmd)
 	ClientCmd.AddCommand(listTransactionsCmd)
 
 	ClientCmd.AddCommand(getUnconfirmedTransactionCmd)
@@ -135,6 +136,8 @@ func AddCommands() {
 	ClientCmd.AddCommand(listBalancesCmd)
 
 	ClientCmd.AddCommand(rescanWalletCmd)
+	ClientCmd.AddCommand(abandonTransactionCmd)
+	ClientCmd.AddCommand(rebroadcastTransactionsCmd)
 	ClientCmd.AddCommand(walletInfoCmd)
 
 	ClientCmd.AddCommand(buildTransactionCmd)
@@ -150,6 +153,7 @@ func AddCommands() {
 	ClientCmd.AddCommand(getHashRateCmd)
 
 	ClientCmd.AddCommand(createKeyCmd)
+	ClientCmd.AddCommand(recoverKeyCmd)
 	ClientCmd.AddCommand(deleteKeyCmd)
 	ClientCmd.AddCommand(listKeysCmd)
 	ClientCmd.AddCommand(resetKeyPwdCmd)
@@ -164,6 +168,7 @@ func AddCommands() {
 	ClientCmd.AddCommand(deleteTransactionFeedCmd)
 	ClientCmd.AddCommand(getTransactionFeedCmd)
 	ClientCmd.AddCommand(updateTransactionFeedCmd)
+	ClientCmd.AddCommand(listTransactionFeedTxsCmd)
 
 	ClientCmd.AddCommand(isMiningCmd)
 	ClientCmd.AddCommand(setMiningCmd)
@@ -191,6 +196,7 @@ func AddTemplateFunc() {
 		updateAssetAliasCmd.Name(),
 
 		createKeyCmd.Name(),
+		recoverKeyCmd.Name(),
 		deleteKeyCmd.Name(),
 		listKeysCmd.Name(),
 		resetKeyPwdCmd.Name(),
@@ -205,8 +211,17 @@ func AddTemplateFunc() {
 		listUnspentOutputsCmd.Name(),
 		listBalancesCmd.Name(),
 
+		createTransactionFeedCmd.Name(),
+		listTransactionFeedsCmd.Name(),
+		deleteTransactionFeedCmd.Name(),
+		getTransactionFeedCmd.Name(),
+		updateTransactionFeedCmd.Name(),
+		listTransactionFeedTxsCmd.Name(),
+
 		rescanWalletCmd.Name(),
 		walletInfoCmd.Name(),
+		abandonTransactionCmd.Name(),
+		rebroadcastTransactionsCmd.Name(),
 	}
 
 	cobra.AddTemplateFunc("WalletEnable", func(cmdName string) bool {
@@ -10,15 +10,32 @@ import (
 	"github.com/doslink/doslink/util"
 )
 
+var (
+	withMnemonic  bool
+	keyPassphrase string
+	keyScheme     string
+)
+
+func init() {
+	createKeyCmd.PersistentFlags().BoolVar(&withMnemonic, "mnemonic", false, "create the key from a mnemonic and return it")
+	createKeyCmd.PersistentFlags().StringVar(&keyPassphrase, "passphrase", "", "mnemonic passphrase")
+	createKeyCmd.PersistentFlags().StringVar(&keyScheme, "key-scheme", "", "key scheme, ed25519 or sm2")
+
+	recoverKeyCmd.PersistentFlags().StringVar(&keyPassphrase, "passphrase", "", "mnemonic passphrase")
+}
+
 var createKeyCmd = &cobra.Command{
 	Use:   "create-key <alias> <password>",
 	Short: "Create a key",
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		var key = struct {
-			Alias    string `json:"alias"`
-			Password string `json:"password"`
-		}{Alias: args[0], Password: args[1]}
+			Alias        string `json:"alias"`
+			Password     string `json:"password"`
+			WithMnemonic bool   `json:"with_mnemonic"`
+			Passphrase   string `json:"passphrase"`
+			KeyScheme    string `json:"key_scheme"`
+		}{Alias: args[0], Password: args[1], WithMnemonic: withMnemonic, Passphrase: keyPassphrase, KeyScheme: keyScheme}
 
 		data, exitCode := util.ClientCall("/create-key", &key)
 		if exitCode != util.Success {
@@ -29,6 +46,27 @@ var createKeyCmd = &cobra.Command{
 	},
 }
 
+var recoverKeyCmd = &cobra.Command{
+	Use:   "recover-key <alias> <password> <mnemonic>",
+	Short: "Recover a key from the mnemonic",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		var key = struct {
+			Alias      string `json:"alias"`
+			Password   string `json:"password"`
+			Mnemonic   string `json:"mnemonic"`
+			Passphrase string `json:"passphrase"`
+		}{Alias: args[0], Password: args[1], Mnemonic: args[2], Passphrase: keyPassphrase}
+
+		data, exitCode := util.ClientCall("/recover-key", &key)
+		if exitCode != util.Success {
+			os.Exit(exitCode)
+		}
+
+		printJSON(data)
+	},
+}
+
 var deleteKeyCmd = &cobra.Command{
 	Use:   "delete-key <xpub> <password>",
 	Short: "Delete a key",
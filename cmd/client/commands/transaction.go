@@ -373,6 +373,24 @@ var getTransactionCmd = &cobra.Command{
 	},
 }
 
+var getTransactionReceiptCmd = &cobra.Command{
+	Use:   "get-transaction-receipt <hash>",
+	Short: "get the evm receipt of the transaction in the main chain",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		txInfo := &struct {
+			TxID string `json:"tx_id"`
+		}{TxID: args[0]}
+
+		data, exitCode := util.ClientCall("/get-transaction-receipt", txInfo)
+		if exitCode != util.Success {
+			os.Exit(exitCode)
+		}
+
+		printJSON(data)
+	},
+}
+
 var listTransactionsCmd = &cobra.Command{
 	Use:   "list-transactions",
 	Short: "List the transactions",
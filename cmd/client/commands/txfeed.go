@@ -9,6 +9,16 @@ import (
 	"github.com/doslink/doslink/util"
 )
 
+func init() {
+	listTransactionFeedTxsCmd.PersistentFlags().StringVar(&feedAfter, "after", "", "cursor returned as next by the previous page")
+	listTransactionFeedTxsCmd.PersistentFlags().IntVar(&feedCount, "count", 0, "max number of transactions to list")
+}
+
+var (
+	feedAfter = ""
+	feedCount = 0
+)
+
 var createTransactionFeedCmd = &cobra.Command{
 	Use:   "create-transaction-feed <alias> <filter>",
 	Short: "Create a transaction feed filter",
@@ -88,3 +98,22 @@ var updateTransactionFeedCmd = &cobra.Command{
 		jww.FEEDBACK.Println("Successfully updated transaction feed")
 	},
 }
+
+var listTransactionFeedTxsCmd = &cobra.Command{
+	Use:   "list-transaction-feed-txs <alias>",
+	Short: "List the transactions matched by a transaction feed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var in = struct {
+			Alias string `json:"alias"`
+			After string `json:"after"`
+			Count int    `json:"count"`
+		}{Alias: args[0], After: feedAfter, Count: feedCount}
+
+		data, exitCode := util.ClientCall("/list-transaction-feed-txs", &in)
+		if exitCode != util.Success {
+			os.Exit(exitCode)
+		}
+		printJSON(data)
+	},
+}
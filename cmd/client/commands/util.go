@@ -17,6 +17,8 @@ type accountIns struct {
 	RootXPubs   []chainkd.XPub `json:"root_xpubs"`
 	Quorum      int            `json:"quorum"`
 	Alias       string         `json:"alias"`
+	KeyScheme   string         `json:"key_scheme"`
+	WatchOnly   bool           `json:"watch_only"`
 	AccessToken string         `json:"access_token"`
 }
 
@@ -69,6 +71,10 @@ type accessToken struct {
 	Type    string    `json:"type,omitempty"`
 	Secret  string    `json:"secret,omitempty"`
 	Created time.Time `json:"created_at,omitempty"`
+
+	Scopes    []string   `json:"scopes,omitempty"`
+	Accounts  []string   `json:"accounts,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 func printJSON(data interface{}) {
@@ -34,3 +34,33 @@ var rescanWalletCmd = &cobra.Command{
 		jww.FEEDBACK.Println("Successfully trigger rescanning wallet")
 	},
 }
+
+var abandonTransactionCmd = &cobra.Command{
+	Use:   "abandon-transaction <tx_id>",
+	Short: "Abandon the unconfirmed transaction and release its reserved outputs",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		txInfo := &struct {
+			TxID string `json:"tx_id"`
+		}{TxID: args[0]}
+
+		if _, exitCode := util.ClientCall("/abandon-transaction", txInfo); exitCode != util.Success {
+			os.Exit(exitCode)
+		}
+
+		jww.FEEDBACK.Println("Successfully abandon transaction")
+	},
+}
+
+var rebroadcastTransactionsCmd = &cobra.Command{
+	Use:   "rebroadcast-transactions",
+	Short: "Trigger to rebroadcast the unconfirmed transactions of wallet",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, exitCode := util.ClientCall("/rebroadcast-transactions"); exitCode != util.Success {
+			os.Exit(exitCode)
+		}
+
+		jww.FEEDBACK.Println("Successfully trigger rebroadcasting transactions")
+	},
+}
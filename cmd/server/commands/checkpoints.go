@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	cmn "github.com/tendermint/tmlibs/common"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	cfg "github.com/doslink/doslink/config"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/database/leveldb"
+)
+
+var checkpointsCmd = &cobra.Command{
+	Use:   "checkpoints",
+	Short: "Print the checkpoints of a synced node in the config file format, the node must be stopped",
+	Run:   printCheckpoints,
+}
+
+func init() {
+	checkpointsCmd.Flags().String("chain_id", config.ChainID, "Select [mainnet] or [testnet] or [solonet]")
+	checkpointsCmd.Flags().Uint64("interval", 1000, "Number of blocks between two checkpoints")
+	checkpointsCmd.Flags().Uint64("depth", 100, "Number of blocks under the best block without checkpoints")
+
+	RootCmd.AddCommand(checkpointsCmd)
+}
+
+func printCheckpoints(cmd *cobra.Command, args []string) {
+	interval, _ := cmd.Flags().GetUint64("interval")
+	depth, _ := cmd.Flags().GetUint64("depth")
+	if interval == 0 {
+		cmn.Exit("interval must be greater than 0")
+	}
+
+	store := leveldb.NewStore(dbm.NewDB("core", config.DBBackend, config.DBDir()))
+	storeStatus := store.GetStoreStatus()
+	if storeStatus == nil {
+		cmn.Exit("the chain is not initialized")
+	}
+
+	index, err := store.LoadBlockIndex()
+	if err != nil {
+		cmn.Exit(cmn.Fmt("Failed to load block index: %v", err))
+	}
+
+	checkpoints := []consensus.Checkpoint{}
+	for node := index.GetNode(storeStatus.Hash); node != nil && node.Height > 0; node = node.Parent {
+		if node.Height+depth > storeStatus.Height || node.Height%interval != 0 {
+			continue
+		}
+		checkpoints = append([]consensus.Checkpoint{{Height: node.Height, Hash: node.Hash}}, checkpoints...)
+	}
+
+	fmt.Print(cfg.FormatCheckpoints(config.ChainID, checkpoints))
+}
@@ -25,6 +25,8 @@ func init() {
 	runNodeCmd.Flags().Bool("wallet.disable", config.Wallet.Disable, "Disable wallet")
 	runNodeCmd.Flags().Bool("wallet.rescan", config.Wallet.Rescan, "Rescan wallet")
 	runNodeCmd.Flags().Bool("vault_mode", config.VaultMode, "Run in the offline enviroment")
+	runNodeCmd.Flags().Bool("light_mode", config.LightMode, "Sync headers and the wallet transactions only")
+	runNodeCmd.Flags().Bool("state_sync", config.StateSync, "Sync the contract state of a checkpoint when far behind")
 	runNodeCmd.Flags().Bool("web.closed", config.Web.Closed, "Lanch web browser or not")
 	runNodeCmd.Flags().String("chain_id", config.ChainID, "Select network type")
 
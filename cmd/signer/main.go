@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/doslink/doslink/config"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/remotesigner"
+)
+
+var (
+	keysDir    string
+	listenAddr string
+	authToken  string
+	tlsCert    string
+	tlsKey     string
+)
+
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Serve the keys of the keystore to the nodes with the remote signer enabled",
+	Args:  cobra.NoArgs,
+	RunE:  runSigner,
+}
+
+func init() {
+	signerCmd.Flags().StringVar(&keysDir, "keys_dir", filepath.Join(config.DefaultDataDir(), "keystore"), "Keystore directory")
+	signerCmd.Flags().StringVar(&listenAddr, "laddr", "127.0.0.1:9889", "Signer listen address")
+	signerCmd.Flags().StringVar(&authToken, "auth", "", "Token of the nodes in the form user:secret")
+	signerCmd.Flags().StringVar(&tlsCert, "tls_cert", "", "TLS certificate file")
+	signerCmd.Flags().StringVar(&tlsKey, "tls_key", "", "TLS private key file")
+}
+
+func runSigner(cmd *cobra.Command, args []string) error {
+	hsm, err := pseudohsm.New(keysDir)
+	if err != nil {
+		return err
+	}
+
+	if authToken == "" {
+		log.Warning("the signer accepts the requests without token")
+	}
+	server := &http.Server{Addr: listenAddr, Handler: remotesigner.NewServer(hsm, authToken)}
+
+	log.WithFields(log.Fields{"laddr": listenAddr, "keys_dir": keysDir}).Info("start the remote signer")
+	if tlsCert != "" || tlsKey != "" {
+		return server.ListenAndServeTLS(tlsCert, tlsKey)
+	}
+	return server.ListenAndServe()
+}
+
+func main() {
+	if err := signerCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol/bc"
+)
+
+var (
+	// ErrBadCheckpoint is returned for a checkpoint with a malformed hash
+	ErrBadCheckpoint = errors.New("invalid checkpoint")
+	// ErrConflictCheckpoint is returned for two checkpoints of the same height
+	// with different hashes
+	ErrConflictCheckpoint = errors.New("conflict checkpoint")
+)
+
+// CheckpointConfig is a checkpoint of the config file
+type CheckpointConfig struct {
+	Height uint64 `mapstructure:"height"`
+	Hash   string `mapstructure:"hash"`
+}
+
+// ChainCheckpoints merges the checkpoints of the chain in the config file into
+// the given ones, the result is sorted by height
+func (cfg *Config) ChainCheckpoints(chainID string, checkpoints []consensus.Checkpoint) ([]consensus.Checkpoint, error) {
+	byHeight := make(map[uint64]bc.Hash)
+	for _, checkpoint := range checkpoints {
+		byHeight[checkpoint.Height] = checkpoint.Hash
+	}
+
+	for _, c := range cfg.Checkpoints[chainID] {
+		hash := bc.Hash{}
+		if err := hash.UnmarshalText([]byte(c.Hash)); err != nil {
+			return nil, errors.WithDetailf(ErrBadCheckpoint, "height %d, hash %q", c.Height, c.Hash)
+		}
+
+		if old, ok := byHeight[c.Height]; ok && old != hash {
+			return nil, errors.WithDetailf(ErrConflictCheckpoint, "height %d, hash %x and %x", c.Height, old.Bytes(), hash.Bytes())
+		}
+		byHeight[c.Height] = hash
+	}
+
+	merged := make([]consensus.Checkpoint, 0, len(byHeight))
+	for height, hash := range byHeight {
+		merged = append(merged, consensus.Checkpoint{Height: height, Hash: hash})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Height < merged[j].Height })
+	return merged, nil
+}
+
+// FormatCheckpoints formats the checkpoints as the config file section of the
+// chain, the output can be appended to the config file directly
+func FormatCheckpoints(chainID string, checkpoints []consensus.Checkpoint) string {
+	text := ""
+	for _, checkpoint := range checkpoints {
+		text += fmt.Sprintf("[[checkpoints.%s]]\nheight = %d\nhash = \"%s\"\n\n", chainID, checkpoint.Height, hex.EncodeToString(checkpoint.Hash.Bytes()))
+	}
+	return text
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol/bc"
+)
+
+func TestChainCheckpoints(t *testing.T) {
+	base := []consensus.Checkpoint{
+		{Height: 200, Hash: bc.NewHash([32]byte{2})},
+		{Height: 100, Hash: bc.NewHash([32]byte{1})},
+	}
+	extra := []consensus.Checkpoint{
+		{Height: 100, Hash: bc.NewHash([32]byte{1})},
+		{Height: 150, Hash: bc.NewHash([32]byte{3})},
+	}
+
+	v := viper.New()
+	v.SetConfigType("toml")
+	if err := v.ReadConfig(strings.NewReader(FormatCheckpoints("testnet", extra))); err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultConfig()
+	if err := v.Unmarshal(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := cfg.ChainCheckpoints("testnet", base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []consensus.Checkpoint{base[1], extra[1], base[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkpoints %v, want %v", got, want)
+	}
+
+	if got, err := cfg.ChainCheckpoints("mainnet", base[:1]); err != nil || !reflect.DeepEqual(got, base[:1]) {
+		t.Errorf("checkpoints of other chain %v, %v", got, err)
+	}
+
+	cfg.Checkpoints["testnet"] = append(cfg.Checkpoints["testnet"], CheckpointConfig{Height: 200, Hash: strings.Repeat("ff", 32)})
+	if _, err := cfg.ChainCheckpoints("testnet", base); errors.Root(err) != ErrConflictCheckpoint {
+		t.Errorf("conflict checkpoint error = %v, want %v", err, ErrConflictCheckpoint)
+	}
+
+	cfg.Checkpoints["testnet"] = []CheckpointConfig{{Height: 300, Hash: "00"}}
+	if _, err := cfg.ChainCheckpoints("testnet", base); errors.Root(err) != ErrBadCheckpoint {
+		t.Errorf("bad checkpoint error = %v, want %v", err, ErrBadCheckpoint)
+	}
+}
@@ -83,6 +83,39 @@ type Params struct {
 	// Name defines a human-readable identifier for the network.
 	Name        string
 	Checkpoints []Checkpoint
+
+	// The fork heights below activate the consensus changes of the EVM and
+	// the signature ops, the blocks below them are validated as before the
+	// changes. A height is scheduled past the expected tip of the network at
+	// the time the fork is scheduled, the expected tip is the seconds since
+	// the genesis block divided by TargetSecondsPerBlock. The real tips lag
+	// the expected ones since the networks stall without miners, so the
+	// heights stay ahead of them. The forks scheduled on 2026-10-16 expect
+	// the mainnet tip 19721034 and the testnet tip 19723494, the mainnet
+	// forks are scheduled about 30 days (199384 blocks) after it and the
+	// testnet forks about 14 days (93046 blocks) after it, both rounded up
+	// to the 50000 blocks. Check the tips of synced nodes before a release.
+
+	// BlockContextHeight is the height from which the EVM run by the
+	// contract ops sees the block being validated and its miner, before it
+	// the EVM sees the best block and the sender of the message.
+	BlockContextHeight uint64
+	// GasPriceHeight is the height from which the EVM programs may declare
+	// the gas limit and the gas price of the message, the messages buy their
+	// gas from the gas pool of the block and a failed message keeps the gas
+	// it used instead of failing the transaction.
+	GasPriceHeight uint64
+	// MultiAssetHeight is the height from which the issued assets spent by
+	// the contract ops are credited to the EVM accounts and the asset
+	// precompiled contract is active.
+	MultiAssetHeight uint64
+	// ChainDataHeight is the height from which the issuances record the
+	// definitions of the assets in the EVM state and the precompiled
+	// contracts of the chain data are active.
+	ChainDataHeight uint64
+	// SM2Height is the height from which the signature ops check the SM2
+	// public keys and the version 1 witness programs pay to a public key hash.
+	SM2Height uint64
 }
 
 // ActiveNetParams is ...
@@ -100,6 +133,11 @@ var MainNetParams = Params{
 	Name:        "main",
 	Checkpoints: []Checkpoint{
 	},
+	BlockContextHeight: 19950000,
+	GasPriceHeight:     19950000,
+	MultiAssetHeight:   19950000,
+	ChainDataHeight:    19950000,
+	SM2Height:          19950000,
 }
 
 // TestNetParams is the config for test-net
@@ -107,10 +145,20 @@ var TestNetParams = Params{
 	Name:        "test",
 	Checkpoints: []Checkpoint{
 	},
+	BlockContextHeight: 19850000,
+	GasPriceHeight:     19850000,
+	MultiAssetHeight:   19850000,
+	ChainDataHeight:    19850000,
+	SM2Height:          19850000,
 }
 
 // SoloNetParams is the config for test-net
 var SoloNetParams = Params{
-	Name:        "solo",
-	Checkpoints: []Checkpoint{},
+	Name:               "solo",
+	Checkpoints:        []Checkpoint{},
+	BlockContextHeight: 0,
+	GasPriceHeight:     0,
+	MultiAssetHeight:   0,
+	ChainDataHeight:    0,
+	SM2Height:          0,
 }
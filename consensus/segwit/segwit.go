@@ -42,8 +42,11 @@ func ConvertP2SHProgram(prog []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if insts[0].Op == vm.OP_0 {
+	switch insts[0].Op {
+	case vm.OP_0:
 		return vmutil.P2SHProgram(insts[1].Data)
+	case vm.OP_1:
+		return vmutil.P2PKHSigProgram(insts[1].Data)
 	}
 	return nil, errors.New("unknown P2SHP version number")
 }
@@ -11,8 +11,10 @@ const (
 	SFFastSync
 	// SFSPV indicate peer support spv mode
 	SFSPV
+	// SFStateSync indicate peer support state snapshot sync
+	SFStateSync
 	// DefaultServices is the server that this node support
-	DefaultServices = SFFullNode | SFFastSync | SFSPV
+	DefaultServices = SFFullNode | SFFastSync | SFSPV | SFStateSync
 )
 
 // IsEnable check does the flag support the input flag function
@@ -29,18 +29,85 @@ var (
 	ErrNoMatchID = errors.New("nonexisting access token ID")
 	// ErrInvalidToken is returned when Check is called on invalid token
 	ErrInvalidToken = errors.New("invalid token")
+	// ErrBadScope is returned when Create is called with an unknown scope.
+	ErrBadScope = errors.New("invalid token scope")
+	// ErrExpiredToken is returned when Check is called on an expired token.
+	ErrExpiredToken = errors.New("expired token")
 
 	// validIDRegexp checks that all characters are alphumeric, _ or -.
 	// It also must have a length of at least 1.
 	validIDRegexp = regexp.MustCompile(`^[\w-]+$`)
 )
 
+// The route scopes of the access tokens, every scope grants the read-only
+// routes and the admin scope grants all the routes.
+const (
+	ScopeReadOnly = "read-only"
+	ScopeWallet   = "wallet"
+	ScopeSigning  = "signing"
+	ScopeMining   = "mining"
+	ScopeAdmin    = "admin"
+)
+
+var validScopes = map[string]bool{
+	ScopeReadOnly: true,
+	ScopeWallet:   true,
+	ScopeSigning:  true,
+	ScopeMining:   true,
+	ScopeAdmin:    true,
+}
+
+// Policy restricts the access token to the route scopes and the accounts until
+// it expires. A token without scopes is granted all the routes, the same as
+// the tokens created before the scopes.
+type Policy struct {
+	Scopes    []string   `json:"scopes,omitempty"`
+	Accounts  []string   `json:"accounts,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
 // Token describe the access token.
 type Token struct {
 	ID      string    `json:"id"`
 	Token   string    `json:"token,omitempty"`
 	Type    string    `json:"type,omitempty"`
 	Created time.Time `json:"created_at"`
+	Policy
+	LastUsed *time.Time `json:"last_used_at,omitempty"`
+}
+
+// HasScope check whether the token is granted the routes of the scope
+func (t *Token) HasScope(scope string) bool {
+	if len(t.Scopes) == 0 || scope == ScopeReadOnly {
+		return true
+	}
+
+	for _, s := range t.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAccount check whether the token is granted the account of the id or the
+// alias, a token without accounts is granted all the accounts
+func (t *Token) HasAccount(id, alias string) bool {
+	if len(t.Accounts) == 0 {
+		return true
+	}
+
+	for _, account := range t.Accounts {
+		if account == id || (alias != "" && account == alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired check whether the token is expired at the time
+func (t *Token) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
 }
 
 // CredentialStore store user access credential.
@@ -55,12 +122,22 @@ func NewStore(db dbm.DB) *CredentialStore {
 	}
 }
 
-// Create generates a new access token with the given ID.
-func (cs *CredentialStore) Create(id, typ string) (*Token, error) {
+// Create generates a new access token with the given ID, the policy is
+// optional.
+func (cs *CredentialStore) Create(id, typ string, policy *Policy) (*Token, error) {
 	if !validIDRegexp.MatchString(id) {
 		return nil, errors.WithDetailf(ErrBadID, "invalid id %q", id)
 	}
 
+	if policy == nil {
+		policy = &Policy{}
+	}
+	for _, scope := range policy.Scopes {
+		if !validScopes[scope] {
+			return nil, errors.WithDetailf(ErrBadScope, "invalid scope %q", scope)
+		}
+	}
+
 	key := []byte(id)
 	if cs.DB.Get(key) != nil {
 		return nil, errors.WithDetailf(ErrDuplicateID, "id %q already in use", id)
@@ -79,6 +156,7 @@ func (cs *CredentialStore) Create(id, typ string) (*Token, error) {
 		Token:   fmt.Sprintf("%s:%x", id, hashedSecret),
 		Type:    typ,
 		Created: time.Now(),
+		Policy:  *policy,
 	}
 
 	value, err := json.Marshal(token)
@@ -90,27 +168,54 @@ func (cs *CredentialStore) Create(id, typ string) (*Token, error) {
 	return token, nil
 }
 
-// Check returns whether or not an id-secret pair is a valid access token.
-func (cs *CredentialStore) Check(id string, secret string) error {
-	if !validIDRegexp.MatchString(id) {
-		return errors.WithDetailf(ErrBadID, "invalid id %q", id)
+// Check returns the access token of the id-secret pair if it is valid and
+// not expired.
+func (cs *CredentialStore) Check(id string, secret string) (*Token, error) {
+	token, err := cs.get(id)
+	if err != nil {
+		return nil, err
 	}
 
-	var value []byte
-	token := &Token{}
+	if strings.Split(token.Token, ":")[1] != secret {
+		return nil, ErrInvalidToken
+	}
+	if token.Expired(time.Now()) {
+		return nil, errors.WithDetailf(ErrExpiredToken, "token %q expired at %v", id, token.ExpiresAt)
+	}
+	return token, nil
+}
 
-	if value = cs.DB.Get([]byte(id)); value == nil {
-		return errors.WithDetailf(ErrNoMatchID, "check id %q nonexisting", id)
+// MarkUsed records the time the access token of the id is last used.
+func (cs *CredentialStore) MarkUsed(id string, now time.Time) error {
+	token, err := cs.get(id)
+	if err != nil {
+		return err
 	}
-	if err := json.Unmarshal(value, token); err != nil {
+
+	token.LastUsed = &now
+	value, err := json.Marshal(token)
+	if err != nil {
 		return err
 	}
+	cs.DB.Set([]byte(id), value)
+	return nil
+}
+
+func (cs *CredentialStore) get(id string) (*Token, error) {
+	if !validIDRegexp.MatchString(id) {
+		return nil, errors.WithDetailf(ErrBadID, "invalid id %q", id)
+	}
 
-	if strings.Split(token.Token, ":")[1] == secret {
-		return nil
+	value := cs.DB.Get([]byte(id))
+	if value == nil {
+		return nil, errors.WithDetailf(ErrNoMatchID, "check id %q nonexisting", id)
 	}
 
-	return ErrInvalidToken
+	token := &Token{}
+	if err := json.Unmarshal(value, token); err != nil {
+		return nil, err
+	}
+	return token, nil
 }
 
 // List lists all access tokens.
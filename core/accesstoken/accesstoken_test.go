@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	dbm "github.com/tendermint/tmlibs/db"
 
@@ -28,7 +29,7 @@ func TestCreate(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		_, err := cs.Create(c.id, c.typ)
+		_, err := cs.Create(c.id, c.typ, nil)
 		if errors.Root(err) != c.want {
 			t.Errorf("Create(%s, %s) error = %s want %s", c.id, c.typ, err, c.want)
 		}
@@ -71,13 +72,68 @@ func TestCheck(t *testing.T) {
 	token := mustCreateToken(ctx, t, cs, "x", "client")
 	tokenParts := strings.Split(token.Token, ":")
 
-	if err := cs.Check(tokenParts[0], tokenParts[1]); err != nil {
+	if _, err := cs.Check(tokenParts[0], tokenParts[1]); err != nil {
 		t.Fatal(err)
 	}
 
-	if err := cs.Check("x", "badsecret"); err != ErrInvalidToken {
+	if _, err := cs.Check("x", "badsecret"); err != ErrInvalidToken {
 		t.Fatal("invalid token check passed")
 	}
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired, err := cs.Create("expired", "client", &Policy{ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.Check("expired", strings.Split(expired.Token, ":")[1]); errors.Root(err) != ErrExpiredToken {
+		t.Fatalf("check expired token error = %v, want %v", err, ErrExpiredToken)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+	cs := NewStore(testDB)
+
+	if _, err := cs.Create("bad", "client", &Policy{Scopes: []string{"root"}}); errors.Root(err) != ErrBadScope {
+		t.Fatalf("create token with bad scope error = %v, want %v", err, ErrBadScope)
+	}
+
+	token, err := cs.Create("wallet", "client", &Policy{Scopes: []string{ScopeWallet}, Accounts: []string{"alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		scope string
+		want  bool
+	}{
+		{ScopeReadOnly, true},
+		{ScopeWallet, true},
+		{ScopeSigning, false},
+		{ScopeAdmin, false},
+	}
+	for _, c := range cases {
+		if got := token.HasScope(c.scope); got != c.want {
+			t.Errorf("HasScope(%s) = %v, want %v", c.scope, got, c.want)
+		}
+	}
+
+	if !token.HasAccount("0ID", "alice") || token.HasAccount("0ID", "bob") {
+		t.Error("account restriction of the token is not applied")
+	}
+
+	now := time.Now()
+	if err := cs.MarkUsed("wallet", now); err != nil {
+		t.Fatal(err)
+	}
+	got, err := cs.Check("wallet", strings.Split(token.Token, ":")[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastUsed == nil || !got.LastUsed.Equal(now) || got.Scopes[0] != ScopeWallet {
+		t.Fatalf("token after mark used %v", got)
+	}
 }
 
 func TestDelete(t *testing.T) {
@@ -112,7 +168,7 @@ func TestDeleteWithInvalidId(t *testing.T) {
 }
 
 func mustCreateToken(ctx context.Context, t *testing.T, cs *CredentialStore, id, typ string) *Token {
-	token, err := cs.Create(id, typ)
+	token, err := cs.Create(id, typ, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -22,12 +22,38 @@ func aliasKey(name string) []byte {
 // Account is structure of Chain account
 type Account struct {
 	*signers.Signer
-	ID    string `json:"id"`
-	Alias string `json:"alias"`
+	ID        string `json:"id"`
+	Alias     string `json:"alias"`
+	WatchOnly bool   `json:"watch_only,omitempty"`
 }
 
 // Create creates a new Account.
 func (m *Manager) Create(xpubs []chainkd.XPub, quorum int, alias string) (*Account, error) {
+	return m.CreateWithScheme(xpubs, quorum, alias, "", false)
+}
+
+// CreateWatchOnly creates an account of the xpubs whose keys are held offline,
+// the addresses of the AddressGap are derived ahead to track the funds sent to
+// the addresses derived by the offline wallet.
+func (m *Manager) CreateWatchOnly(xpubs []chainkd.XPub, quorum int, alias string) (*Account, error) {
+	return m.CreateWithScheme(xpubs, quorum, alias, "", true)
+}
+
+// CreateWithScheme creates a new Account of the xpubs of the key scheme, the
+// empty scheme is ed25519.
+func (m *Manager) CreateWithScheme(xpubs []chainkd.XPub, quorum int, alias string, keyScheme string, watchOnly bool) (*Account, error) {
+	account, err := m.create(xpubs, quorum, alias, keyScheme, watchOnly)
+	if err != nil || !watchOnly {
+		return account, err
+	}
+
+	if _, err := m.ExtendAddressGap(account.ID, 0); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (m *Manager) create(xpubs []chainkd.XPub, quorum int, alias string, keyScheme string, watchOnly bool) (*Account, error) {
 	m.accountMu.Lock()
 	defer m.accountMu.Unlock()
 
@@ -36,13 +62,13 @@ func (m *Manager) Create(xpubs []chainkd.XPub, quorum int, alias string) (*Accou
 		return nil, ErrDuplicateAlias
 	}
 
-	signer, err := signers.Create("account", xpubs, quorum, m.getNextAccountIndex())
+	signer, err := signers.CreateWithScheme("account", keyScheme, xpubs, quorum, m.getNextAccountIndex())
 	id := signers.IDGenerate()
 	if err != nil {
 		return nil, errors.Wrap(err)
 	}
 
-	account := &Account{Signer: signer, ID: id, Alias: normalizedAlias}
+	account := &Account{Signer: signer, ID: id, Alias: normalizedAlias, WatchOnly: watchOnly}
 	rawAccount, err := json.Marshal(account)
 	if err != nil {
 		return nil, ErrMarshalAccount
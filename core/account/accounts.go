@@ -28,11 +28,12 @@ var (
 
 // pre-define errors for supporting errorFormatter
 var (
-	ErrDuplicateAlias  = errors.New("duplicate account alias")
-	ErrFindAccount     = errors.New("fail to find account")
-	ErrMarshalAccount  = errors.New("failed marshal account")
-	ErrInvalidAddress  = errors.New("invalid address")
-	ErrFindCtrlProgram = errors.New("fail to find account control program")
+	ErrDuplicateAlias   = errors.New("duplicate account alias")
+	ErrFindAccount      = errors.New("fail to find account")
+	ErrMarshalAccount   = errors.New("failed marshal account")
+	ErrInvalidAddress   = errors.New("invalid address")
+	ErrFindCtrlProgram  = errors.New("fail to find account control program")
+	ErrGasPriceInactive = errors.New("gas price of contract message is not active")
 )
 
 // Manager stores accounts and their associated control programs.
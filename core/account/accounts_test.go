@@ -8,10 +8,14 @@ import (
 
 	dbm "github.com/tendermint/tmlibs/db"
 
+	"github.com/doslink/doslink/basis/crypto"
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/database/leveldb"
 	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/signers"
 	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/vmutil"
 	"github.com/doslink/doslink/testutil"
 )
 
@@ -139,6 +143,116 @@ func TestFindByAlias(t *testing.T) {
 	}
 }
 
+func TestExtendAddressGap(t *testing.T) {
+	m := mockAccountManager(t)
+	account, err := m.Create([]chainkd.XPub{testutil.TestXPub}, 1, "gap-alias")
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+
+	cases := []struct {
+		usedIndex uint64
+		extended  bool
+		lastIndex uint64
+	}{
+		{usedIndex: 0, extended: true, lastIndex: AddressGap},
+		{usedIndex: 0, extended: false, lastIndex: AddressGap},
+		{usedIndex: 5, extended: true, lastIndex: AddressGap + 5},
+		{usedIndex: 3, extended: false, lastIndex: AddressGap + 5},
+	}
+
+	for i, c := range cases {
+		extended, err := m.ExtendAddressGap(account.ID, c.usedIndex)
+		if err != nil {
+			testutil.FatalErr(t, err)
+		}
+		if extended != c.extended {
+			t.Errorf("case %d: extended %v, want %v", i, extended, c.extended)
+		}
+		if lastIndex := m.lastContractIndex(account.ID); lastIndex != c.lastIndex {
+			t.Errorf("case %d: last index %d, want %d", i, lastIndex, c.lastIndex)
+		}
+	}
+
+	cps, err := m.ListControlProgram()
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if len(cps) != AddressGap+5 {
+		t.Errorf("got %d control programs, want %d", len(cps), AddressGap+5)
+	}
+}
+
+func TestCreateWatchOnly(t *testing.T) {
+	m := mockAccountManager(t)
+	xpubs := []chainkd.XPub{testutil.TestXPub, chainkd.RootXPrv([]byte("offline")).XPub()}
+	account, err := m.CreateWatchOnly(xpubs, 2, "watch-alias")
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if !account.WatchOnly || account.Quorum != 2 {
+		t.Fatalf("watch-only account %v", account)
+	}
+
+	found, err := m.FindByID(account.ID)
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if !testutil.DeepEqual(account, found) {
+		t.Errorf("expected found account to be %v, instead found %v", account, found)
+	}
+
+	cps, err := m.ListControlProgram()
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if len(cps) != AddressGap {
+		t.Errorf("got %d control programs, want %d", len(cps), AddressGap)
+	}
+
+	if _, err := m.CreateWatchOnly(xpubs, 3, "bad-quorum"); errors.Root(err) != signers.ErrBadQuorum {
+		t.Errorf("create watch-only account error = %v, want %v", err, signers.ErrBadQuorum)
+	}
+}
+
+func TestCreateSM2Account(t *testing.T) {
+	m := mockAccountManager(t)
+	xpub := chainkd.XPub(sm2kd.RootXPrv([]byte("sm2")).XPub())
+	account, err := m.CreateWithScheme([]chainkd.XPub{xpub}, 1, "sm2-alias", signers.KeySchemeSM2, false)
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if !account.IsSM2() {
+		t.Fatalf("account key scheme %s, want %s", account.KeyScheme, signers.KeySchemeSM2)
+	}
+
+	cp, err := m.createAddress(account, false)
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+
+	path := signers.Path(account.Signer, signers.AccountKeySpace, cp.KeyIndex)
+	derivedXPub, err := sm2kd.XPub(xpub).Derive(path)
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	script, err := vmutil.P2SPSM2MultiSigProgram([][]byte{derivedXPub.PublicKey()}, 1)
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	program, err := vmutil.P2WSHProgram(crypto.Ripemd160(script))
+	if err != nil {
+		testutil.FatalErr(t, err)
+	}
+	if !testutil.DeepEqual(cp.ControlProgram, program) {
+		t.Errorf("control program %x, want %x", cp.ControlProgram, program)
+	}
+
+	if _, err := m.CreateWithScheme([]chainkd.XPub{testutil.TestXPub}, 1, "bad-scheme", "rsa", false); errors.Root(err) != signers.ErrBadKeyScheme {
+		t.Errorf("create account error = %v, want %v", err, signers.ErrBadKeyScheme)
+	}
+}
+
 func mockAccountManager(t *testing.T) *Manager {
 	dirPath, err := ioutil.TempDir(".", "")
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"github.com/doslink/doslink/basis/crypto"
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
 	"github.com/doslink/doslink/basis/crypto/sha3pool"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/protocol/vmutil"
 	"encoding/hex"
 )
@@ -20,6 +21,10 @@ func ContractKey(hash common.Hash) []byte {
 	return append(contractPrefix, hash[:]...)
 }
 
+// AddressGap is the count of the addresses derived ahead of the last used one,
+// the wallet of a recovered key finds the funds sent to the derived addresses
+const AddressGap = 20
+
 //CtrlProgram is structure of account control program
 type CtrlProgram struct {
 	AccountID      string
@@ -50,13 +55,35 @@ func (m *Manager) GetContractIndex(accountID string) uint64 {
 	return index
 }
 
+// ExtendAddressGap derives the addresses of the account until AddressGap
+// addresses follow the used key index, it returns whether any is derived
+func (m *Manager) ExtendAddressGap(accountID string, usedIndex uint64) (bool, error) {
+	account, err := m.FindByID(accountID)
+	if err != nil {
+		return false, err
+	}
+
+	extended := false
+	for m.lastContractIndex(accountID) < usedIndex+AddressGap {
+		if _, err := m.createAddress(account, false); err != nil {
+			return extended, err
+		}
+		extended = true
+	}
+	return extended, nil
+}
+
 // GetLocalCtrlProgramByAddress return CtrlProgram by given address
 func (m *Manager) GetLocalCtrlProgramByAddress(address string) (*CtrlProgram, error) {
 	program, err := m.GetProgramByAddress(address)
 	if err != nil {
 		return nil, err
 	}
+	return m.GetLocalCtrlProgram(program)
+}
 
+// GetLocalCtrlProgram return the local control program of the program
+func (m *Manager) GetLocalCtrlProgram(program []byte) (*CtrlProgram, error) {
 	var hash [32]byte
 	sha3pool.Sum256(hash[:], program)
 	rawProgram := m.db.Get(ContractKey(hash))
@@ -104,13 +131,11 @@ func (m *Manager) createAddress(account *Account, change bool) (cp *CtrlProgram,
 func (m *Manager) createP2SH(account *Account, change bool) (*CtrlProgram, error) {
 	idx := m.getNextContractIndex(account.ID)
 	path := signers.Path(account.Signer, signers.AccountKeySpace, idx)
-	derivedXPubs := chainkd.DeriveXPubs(account.XPubs, path)
-	derivedPKs := chainkd.XPubKeys(derivedXPubs)
-	signScript, err := vmutil.P2SPMultiSigProgram(derivedPKs, account.Quorum)
+	script, err := signScript(account.Signer, path)
 	if err != nil {
 		return nil, err
 	}
-	scriptHash := crypto.Ripemd160(signScript)
+	scriptHash := crypto.Ripemd160(script)
 
 	address := common.BytesToAddress(scriptHash)
 
@@ -128,6 +153,36 @@ func (m *Manager) createP2SH(account *Account, change bool) (*CtrlProgram, error
 	}, nil
 }
 
+// signScript builds the multisig script of the signer keys derived by the
+// path, the keys of the SM2 signer are derived by sm2kd.
+func signScript(signer *signers.Signer, path [][]byte) ([]byte, error) {
+	if signer.IsSM2() {
+		xpubs := make([]sm2kd.XPub, 0, len(signer.XPubs))
+		for _, xpub := range signer.XPubs {
+			xpubs = append(xpubs, sm2kd.XPub(xpub))
+		}
+		derivedXPubs, err := sm2kd.DeriveXPubs(xpubs, path)
+		if err != nil {
+			return nil, err
+		}
+		return vmutil.P2SPSM2MultiSigProgram(sm2kd.XPubKeys(derivedXPubs), signer.Quorum)
+	}
+
+	derivedXPubs := chainkd.DeriveXPubs(signer.XPubs, path)
+	derivedPKs := chainkd.XPubKeys(derivedXPubs)
+	return vmutil.P2SPMultiSigProgram(derivedPKs, signer.Quorum)
+}
+
+func (m *Manager) lastContractIndex(accountID string) uint64 {
+	m.accIndexMu.Lock()
+	defer m.accIndexMu.Unlock()
+
+	if rawIndexBytes := m.db.Get(contractIndexKey(accountID)); rawIndexBytes != nil {
+		return common.BytesToUnit64(rawIndexBytes)
+	}
+	return 0
+}
+
 func (m *Manager) getNextContractIndex(accountID string) uint64 {
 	m.accIndexMu.Lock()
 	defer m.accIndexMu.Unlock()
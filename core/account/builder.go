@@ -6,10 +6,10 @@ import (
 	"encoding/json"
 	"math/big"
 
-	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
 	chainjson "github.com/doslink/doslink/basis/encoding/json"
 	"github.com/doslink/doslink/basis/errors"
 	"github.com/doslink/doslink/config"
+	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/core/signers"
 	"github.com/doslink/doslink/core/txbuilder"
 	"github.com/doslink/doslink/protocol/bc"
@@ -184,9 +184,7 @@ func SigningInstruction(signer *signers.Signer, keyIndex uint64, addr string) (*
 	{
 		sigInst.AddRawWitnessKeys(signer.XPubs, path, signer.Quorum)
 		path := signers.Path(signer, signers.AccountKeySpace, keyIndex)
-		derivedXPubs := chainkd.DeriveXPubs(signer.XPubs, path)
-		derivedPKs := chainkd.XPubKeys(derivedXPubs)
-		script, err := vmutil.P2SPMultiSigProgram(derivedPKs, signer.Quorum)
+		script, err := signScript(signer, path)
 		if err != nil {
 			return nil, err
 		}
@@ -239,6 +237,7 @@ type createContractAction struct {
 	Creator   string             `json:"from"`
 	Nonce     chainjson.HexBytes `json:"nonce"`
 	VM        int64              `json:"vm"`
+	evmGas
 }
 
 func (a *createContractAction) Build(ctx context.Context, b *txbuilder.TemplateBuilder) error {
@@ -280,7 +279,12 @@ func (a *createContractAction) Build(ctx context.Context, b *txbuilder.TemplateB
 		nonce = new(big.Int).SetBytes(a.Nonce.Bytes()).Uint64()
 	}
 
-	createContractProgram, err := vmutil.CreateContractProgram(a.Contract)
+	gas, err := estimateContractGas(b, address, nil, a.Contract)
+	if err != nil {
+		return err
+	}
+
+	createContractProgram, err := a.program(b, vm.OP_CREATE, a.Contract, gas, vmutil.CreateContractProgram)
 	if err != nil {
 		return err
 	}
@@ -316,6 +320,49 @@ func (a *createContractAction) Build(ctx context.Context, b *txbuilder.TemplateB
 	return nil
 }
 
+// estimateContractGas estimates the gas of the contract call or creation on
+// the best block state, a reverted message fails the build with the reason.
+// The message carries no value since the assets reach the contract through
+// the outputs of the tx.
+func estimateContractGas(b *txbuilder.TemplateBuilder, sender, contract, input []byte) (uint64, error) {
+	if b.Chain() == nil {
+		return 0, nil
+	}
+
+	gas, err := b.Chain().EstimateGas(sender, contract, 0, input)
+	if err != nil {
+		return 0, errors.Wrap(err, "estimate contract gas")
+	}
+	return gas, nil
+}
+
+// evmGas is the gas of the EVM message of the contract actions. The message
+// buys its gas at the gas price from the native asset balance of the sender
+// once the price is set, otherwise the gas is paid by the fee of the tx.
+type evmGas struct {
+	GasLimit uint64 `json:"gas_limit"`
+	GasPrice uint64 `json:"gas_price"`
+}
+
+// program builds the EVM program of the op, the estimated gas is the default
+// gas limit of the message with a gas price
+func (g *evmGas) program(b *txbuilder.TemplateBuilder, op vm.Op, input []byte, estimatedGas uint64, feeProgram func([]byte) ([]byte, error)) ([]byte, error) {
+	if g.GasPrice == 0 {
+		b.AddEstimatedGas(estimatedGas)
+		return feeProgram(input)
+	}
+
+	if c := b.Chain(); c != nil && c.BestBlockHeight()+1 < consensus.ActiveNetParams.GasPriceHeight {
+		return nil, errors.WithDetailf(ErrGasPriceInactive, "active from height %d", consensus.ActiveNetParams.GasPriceHeight)
+	}
+
+	gasLimit := g.GasLimit
+	if gasLimit == 0 {
+		gasLimit = estimatedGas
+	}
+	return vmutil.EVMGasProgram(op, input, gasLimit, g.GasPrice)
+}
+
 func getSender(accounts *Manager, accountID, senderAddress string) (sender *CtrlProgram, err error) {
 	var cp *CtrlProgram
 	if senderAddress == "" {
@@ -348,6 +395,7 @@ type sendToContractAction struct {
 	Sender    string             `json:"from"`
 	Nonce     chainjson.HexBytes `json:"nonce"`
 	VM        int64              `json:"vm"`
+	evmGas
 }
 
 func (a *sendToContractAction) Build(ctx context.Context, b *txbuilder.TemplateBuilder) error {
@@ -389,7 +437,12 @@ func (a *sendToContractAction) Build(ctx context.Context, b *txbuilder.TemplateB
 		nonce = new(big.Int).SetBytes(a.Nonce.Bytes()).Uint64()
 	}
 
-	callContractProgram, err := vmutil.CallContractProgram(a.Input)
+	gas, err := estimateContractGas(b, address, a.Contract, a.Input)
+	if err != nil {
+		return err
+	}
+
+	callContractProgram, err := a.program(b, vm.OP_CALL, a.Input, gas, vmutil.CallContractProgram)
 	if err != nil {
 		return err
 	}
@@ -444,6 +497,7 @@ type contractAction struct {
 	To        chainjson.HexBytes `json:"to"`
 	Input     chainjson.HexBytes `json:"input"`
 	VM        int64              `json:"vm"`
+	evmGas
 }
 
 func (a *contractAction) Build(ctx context.Context, b *txbuilder.TemplateBuilder) (err error) {
@@ -485,7 +539,15 @@ func (a *contractAction) Build(ctx context.Context, b *txbuilder.TemplateBuilder
 		nonce = new(big.Int).SetBytes(a.Nonce.Bytes()).Uint64()
 	}
 
-	contractProgram, err := vmutil.ContractProgram(a.Input)
+	// the message is estimated only for the default gas limit of a gas price
+	gas := uint64(0)
+	if a.GasPrice > 0 && a.GasLimit == 0 {
+		if gas, err = estimateContractGas(b, address, a.To, a.Input); err != nil {
+			return err
+		}
+	}
+
+	contractProgram, err := a.program(b, vm.OP_CONTRACT, a.Input, gas, vmutil.ContractProgram)
 	if err != nil {
 		return err
 	}
@@ -27,10 +27,12 @@ func ContractUTXOKey(id bc.Hash) []byte {
 //Annotated init an annotated account object
 func Annotated(a *Account) *query.AnnotatedAccount {
 	return &query.AnnotatedAccount{
-		ID:       a.ID,
-		Alias:    a.Alias,
-		Quorum:   a.Quorum,
-		XPubs:    a.XPubs,
-		KeyIndex: a.KeyIndex,
+		ID:        a.ID,
+		Alias:     a.Alias,
+		KeyScheme: a.KeyScheme,
+		Quorum:    a.Quorum,
+		XPubs:     a.XPubs,
+		KeyIndex:  a.KeyIndex,
+		WatchOnly: a.WatchOnly,
 	}
 }
@@ -10,6 +10,11 @@ func (m *Manager) AddUnconfirmedUtxo(utxos []*UTXO) {
 	m.utxoKeeper.AddUnconfirmedUtxo(utxos)
 }
 
+// FindUtxo return the wallet utxo of the output, including the unconfirmed one
+func (m *Manager) FindUtxo(outHash bc.Hash) (*UTXO, error) {
+	return m.utxoKeeper.findUtxo(outHash, true)
+}
+
 func (m *Manager) ListUnconfirmedUtxo(isSmartContract bool) []*UTXO {
 	utxos := m.utxoKeeper.ListUnconfirmed()
 	result := []*UTXO{}
@@ -21,6 +26,11 @@ func (m *Manager) ListUnconfirmedUtxo(isSmartContract bool) []*UTXO {
 	return result
 }
 
+// ReleaseUtxos cancels the reservations of the utxos in the utxoKeeper
+func (m *Manager) ReleaseUtxos(hashes []bc.Hash) {
+	m.utxoKeeper.ReleaseUtxos(hashes)
+}
+
 // RemoveUnconfirmedUtxo remove utxos from the utxoKeeper
 func (m *Manager) RemoveUnconfirmedUtxo(hashes []*bc.Hash) {
 	m.utxoKeeper.RemoveUnconfirmedUtxo(hashes)
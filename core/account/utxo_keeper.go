@@ -104,6 +104,18 @@ func (uk *utxoKeeper) ListUnconfirmed() []*UTXO {
 	return utxos
 }
 
+// ReleaseUtxos cancels the reservations holding any of the utxos
+func (uk *utxoKeeper) ReleaseUtxos(hashes []bc.Hash) {
+	uk.mtx.Lock()
+	defer uk.mtx.Unlock()
+
+	for _, hash := range hashes {
+		if rid, ok := uk.reserved[hash]; ok {
+			uk.cancel(rid)
+		}
+	}
+}
+
 func (uk *utxoKeeper) RemoveUnconfirmedUtxo(hashes []*bc.Hash) {
 	uk.mtx.Lock()
 	defer uk.mtx.Unlock()
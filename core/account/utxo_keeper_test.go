@@ -211,6 +211,37 @@ func TestCancel(t *testing.T) {
 	}
 }
 
+func TestReleaseUtxos(t *testing.T) {
+	uk := &utxoKeeper{
+		reserved: map[bc.Hash]uint64{
+			bc.NewHash([32]byte{0x01}): 1,
+			bc.NewHash([32]byte{0x02}): 1,
+			bc.NewHash([32]byte{0x03}): 2,
+		},
+		reservations: map[uint64]*reservation{
+			1: &reservation{
+				id: 1,
+				utxos: []*UTXO{
+					&UTXO{OutputID: bc.NewHash([32]byte{0x01})},
+					&UTXO{OutputID: bc.NewHash([32]byte{0x02})},
+				},
+			},
+			2: &reservation{
+				id:    2,
+				utxos: []*UTXO{&UTXO{OutputID: bc.NewHash([32]byte{0x03})}},
+			},
+		},
+	}
+
+	uk.ReleaseUtxos([]bc.Hash{bc.NewHash([32]byte{0x02}), bc.NewHash([32]byte{0x04})})
+	if len(uk.reserved) != 1 || uk.reserved[bc.NewHash([32]byte{0x03})] != 2 {
+		t.Errorf("reserved utxos %v", uk.reserved)
+	}
+	if _, ok := uk.reservations[2]; len(uk.reservations) != 1 || !ok {
+		t.Errorf("reservations %v", uk.reservations)
+	}
+}
+
 func TestRemoveUnconfirmedUtxo(t *testing.T) {
 	cases := []struct {
 		before      utxoKeeper
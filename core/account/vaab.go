@@ -99,7 +99,7 @@ func (a *withdrawAction) Build(ctx context.Context, b *txbuilder.TemplateBuilder
 	}
 	address, _ := hex.DecodeString(sender.Address)
 
-	can, err := b.Chain().CanTransfer(address, new(big.Int).SetUint64(a.Amount))
+	can, err := b.Chain().CanTransfer(address, a.AssetId, new(big.Int).SetUint64(a.Amount))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,284 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/doslink/doslink/basis/errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var bigType = reflect.TypeOf(&big.Int{})
+
+// Event is a log decoded with the event ABI of the emitting contract
+type Event struct {
+	Name   string                 `json:"event"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Pack encodes the call of the method, args is the JSON array of the method
+// arguments in declaration order.
+func (c *Contract) Pack(method string, args json.RawMessage) ([]byte, error) {
+	m, ok := c.abi.Methods[method]
+	if !ok {
+		return nil, errors.WithDetailf(ErrFindMethod, "method %s", method)
+	}
+
+	var rawArgs []json.RawMessage
+	if len(args) > 0 && string(args) != "null" {
+		if err := json.Unmarshal(args, &rawArgs); err != nil {
+			return nil, errors.WithDetail(ErrBadArgument, "arguments must be a JSON array")
+		}
+	}
+	if len(rawArgs) != len(m.Inputs) {
+		return nil, errors.WithDetailf(ErrBadArgument, "method %s takes %d arguments, got %d", method, len(m.Inputs), len(rawArgs))
+	}
+
+	values := make([]interface{}, len(rawArgs))
+	for i, input := range m.Inputs {
+		v, err := jsonToValue(input.Type, rawArgs[i])
+		if err != nil {
+			return nil, errors.WithDetailf(ErrBadArgument, "argument %d (%s %s): %v", i, input.Type, input.Name, err)
+		}
+		values[i] = v.Interface()
+	}
+
+	data, err := c.abi.Pack(method, values...)
+	if err != nil {
+		return nil, errors.WithDetail(ErrBadArgument, err.Error())
+	}
+	return data, nil
+}
+
+// UnpackOutput decodes the return data of the method, the unnamed outputs
+// are keyed by their position.
+func (c *Contract) UnpackOutput(method string, ret []byte) (map[string]interface{}, error) {
+	m, ok := c.abi.Methods[method]
+	if !ok {
+		return nil, errors.WithDetailf(ErrFindMethod, "method %s", method)
+	}
+	if len(m.Outputs) == 0 {
+		return map[string]interface{}{}, nil
+	}
+
+	values, err := m.Outputs.UnpackValues(ret)
+	if err != nil {
+		return nil, errors.WithDetail(ErrUnpack, err.Error())
+	}
+	return namedValues(m.Outputs, values), nil
+}
+
+// DecodeLog decodes the log emitted by the contract, the indexed arguments
+// of a dynamic type are returned as the hash held by the topic.
+func (c *Contract) DecodeLog(topics [][]byte, data []byte) (*Event, error) {
+	if len(topics) == 0 {
+		return nil, errors.WithDetail(ErrUnpack, "anonymous log")
+	}
+
+	for _, event := range c.abi.Events {
+		if event.Anonymous || !bytes.Equal(event.Id().Bytes(), topics[0]) {
+			continue
+		}
+
+		fields := make(map[string]interface{})
+		topicIndex := 1
+		for i, input := range event.Inputs {
+			if !input.Indexed {
+				continue
+			}
+			if topicIndex >= len(topics) {
+				return nil, errors.WithDetailf(ErrUnpack, "event %s misses topic %d", event.Name, topicIndex)
+			}
+
+			topic := topics[topicIndex]
+			topicIndex++
+			switch input.Type.T {
+			case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+				fields[argName(input.Name, i)] = hex.EncodeToString(topic)
+				continue
+			}
+
+			unindexed := input
+			unindexed.Indexed = false
+			values, err := abi.Arguments{unindexed}.UnpackValues(topic)
+			if err != nil {
+				return nil, errors.WithDetail(ErrUnpack, err.Error())
+			}
+			fields[argName(input.Name, i)] = formatValue(reflect.ValueOf(values[0]))
+		}
+
+		nonIndexed := event.Inputs.NonIndexed()
+		if len(nonIndexed) > 0 {
+			values, err := nonIndexed.UnpackValues(data)
+			if err != nil {
+				return nil, errors.WithDetail(ErrUnpack, err.Error())
+			}
+			for k, v := range namedValues(nonIndexed, values) {
+				fields[k] = v
+			}
+		}
+		return &Event{Name: event.Name, Fields: fields}, nil
+	}
+	return nil, errors.WithDetailf(ErrUnpack, "no event matches topic %x", topics[0])
+}
+
+func argName(name string, index int) string {
+	if name == "" {
+		return strconv.Itoa(index)
+	}
+	return name
+}
+
+func namedValues(args abi.Arguments, values []interface{}) map[string]interface{} {
+	named := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		named[argName(args[i].Name, i)] = formatValue(reflect.ValueOf(v))
+	}
+	return named
+}
+
+// formatValue converts the unpacked value to its JSON form, integers wider
+// than 64 bits are decimal strings and the byte arrays are hex strings.
+func formatValue(v reflect.Value) interface{} {
+	switch {
+	case v.Type() == bigType:
+		return v.Interface().(*big.Int).String()
+	case v.Type() == reflect.TypeOf(common.Address{}):
+		return v.Interface().(common.Address).Hex()
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.Uint8:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hex.EncodeToString(b)
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = formatValue(v.Index(i))
+		}
+		return list
+	}
+	return v.Interface()
+}
+
+// jsonToValue converts the JSON argument to the go value packed for the ABI
+// type. Integers are JSON numbers or decimal and 0x prefixed hex strings,
+// addresses and byte arrays are hex strings.
+func jsonToValue(t abi.Type, raw json.RawMessage) (reflect.Value, error) {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := jsonToInt(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return reflect.Value{}, fmt.Errorf("%s out of range", n)
+		}
+		if t.T == abi.IntTy && !fitsInt(n, t.Size) {
+			return reflect.Value{}, fmt.Errorf("%s out of range", n)
+		}
+
+		if t.Type == bigType {
+			return reflect.ValueOf(n), nil
+		}
+		v := reflect.New(t.Type).Elem()
+		if t.T == abi.UintTy {
+			v.SetUint(n.Uint64())
+		} else {
+			v.SetInt(n.Int64())
+		}
+		return v, nil
+
+	case abi.BoolTy:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.StringTy:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s), nil
+
+	case abi.AddressTy, abi.FixedBytesTy, abi.FunctionTy:
+		b, err := jsonToBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(b) != t.Size {
+			return reflect.Value{}, fmt.Errorf("want %d bytes, got %d", t.Size, len(b))
+		}
+		v := reflect.New(t.Type).Elem()
+		reflect.Copy(v, reflect.ValueOf(b))
+		return v, nil
+
+	case abi.BytesTy:
+		b, err := jsonToBytes(raw)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return reflect.Value{}, err
+		}
+
+		var v reflect.Value
+		if t.T == abi.SliceTy {
+			v = reflect.MakeSlice(t.Type, len(elems), len(elems))
+		} else {
+			if len(elems) != t.Size {
+				return reflect.Value{}, fmt.Errorf("want %d elements, got %d", t.Size, len(elems))
+			}
+			v = reflect.New(t.Type).Elem()
+		}
+		for i, elem := range elems {
+			ev, err := jsonToValue(*t.Elem, elem)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+			v.Index(i).Set(ev)
+		}
+		return v, nil
+	}
+	return reflect.Value{}, fmt.Errorf("unsupported type %s", t)
+}
+
+func jsonToInt(raw json.RawMessage) (*big.Int, error) {
+	text := string(raw)
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		text = s
+	}
+
+	n, ok := new(big.Int).SetString(strings.TrimSpace(text), 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %s", raw)
+	}
+	return n, nil
+}
+
+func fitsInt(n *big.Int, size int) bool {
+	max := new(big.Int).Lsh(big.NewInt(1), uint(size-1))
+	min := new(big.Int).Neg(max)
+	return n.Cmp(min) >= 0 && n.Cmp(max) < 0
+}
+
+func jsonToBytes(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
@@ -0,0 +1,164 @@
+package contract
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/errors"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const tokenABI = `[
+	{"type":"function","name":"balanceOf","constant":true,"inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"balance","type":"uint256"}]},
+	{"type":"function","name":"transfer","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"outputs":[{"name":"","type":"bool"}]},
+	{"type":"function","name":"batch","inputs":[{"name":"ids","type":"uint64[]"},{"name":"tag","type":"bytes4"},{"name":"memo","type":"string"}],"outputs":[]},
+	{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+var (
+	tokenAddress = mustDecodeHex("00000000000000000000000000000000000000aa")
+	ownerWord    = "000000000000000000000000000000000000000000000000000000000000abcd"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestRegistry(t *testing.T) {
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+
+	registry := NewRegistry(testDB)
+	if _, err := registry.Register(tokenAddress[:19], "token", json.RawMessage(tokenABI)); errors.Root(err) != ErrBadAddress {
+		t.Fatalf("register short address error = %v, want %v", err, ErrBadAddress)
+	}
+	if _, err := registry.Register(tokenAddress, "token", json.RawMessage(`{}`)); errors.Root(err) != ErrBadABI {
+		t.Fatalf("register bad abi error = %v, want %v", err, ErrBadABI)
+	}
+	if _, err := registry.Register(tokenAddress, "token", json.RawMessage(tokenABI)); err != nil {
+		t.Fatal(err)
+	}
+
+	other := mustDecodeHex("00000000000000000000000000000000000000bb")
+	if _, err := registry.Register(other, "token", json.RawMessage(tokenABI)); errors.Root(err) != ErrDuplicateAlias {
+		t.Fatalf("register duplicate alias error = %v, want %v", err, ErrDuplicateAlias)
+	}
+	if _, err := registry.Find(other); errors.Root(err) != ErrFindContract {
+		t.Fatalf("find unknown contract error = %v, want %v", err, ErrFindContract)
+	}
+
+	reloaded := NewRegistry(testDB)
+	contracts := reloaded.List()
+	if len(contracts) != 1 || contracts[0].Alias != "token" {
+		t.Fatalf("reloaded contracts %v", contracts)
+	}
+	if _, err := contracts[0].Pack("balanceOf", json.RawMessage(`["0x000000000000000000000000000000000000abcd"]`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPackAndUnpack(t *testing.T) {
+	c := &Contract{Address: tokenAddress, ABI: json.RawMessage(tokenABI)}
+	if err := json.Unmarshal(c.ABI, &c.abi); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := c.Pack("balanceOf", json.RawMessage(`["000000000000000000000000000000000000abcd"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(data), "70a08231"+ownerWord; got != want {
+		t.Errorf("balanceOf calldata %s, want %s", got, want)
+	}
+
+	data, err = c.Pack("batch", json.RawMessage(`[[1, "0x02"], "0x01020304", "memo"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hex.EncodeToString(data), hex.EncodeToString(crypto.Keccak256([]byte("batch(uint64[],bytes4,string)"))[:4])) {
+		t.Errorf("batch calldata %x", data)
+	}
+
+	cases := []struct {
+		method string
+		args   string
+		err    error
+	}{
+		{"mint", `[]`, ErrFindMethod},
+		{"balanceOf", `[]`, ErrBadArgument},
+		{"balanceOf", `["0xabcd"]`, ErrBadArgument},
+		{"transfer", `["000000000000000000000000000000000000abcd", -1]`, ErrBadArgument},
+		{"batch", `[[1], "0x0102", "memo"]`, ErrBadArgument},
+		{"batch", `[["18446744073709551616"], "0x01020304", "memo"]`, ErrBadArgument},
+	}
+	for i, c2 := range cases {
+		if _, err := c.Pack(c2.method, json.RawMessage(c2.args)); errors.Root(err) != c2.err {
+			t.Errorf("case %d: pack error = %v, want %v", i, err, c2.err)
+		}
+	}
+
+	ret := mustDecodeHex("00000000000000000000000000000000000000000000000000000000000003e8")
+	output, err := c.UnpackOutput("balanceOf", ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(output, map[string]interface{}{"balance": "1000"}) {
+		t.Errorf("balanceOf output %v", output)
+	}
+
+	output, err = c.UnpackOutput("transfer", mustDecodeHex("0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(output, map[string]interface{}{"0": true}) {
+		t.Errorf("transfer output %v", output)
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	c := &Contract{Address: tokenAddress, ABI: json.RawMessage(tokenABI)}
+	if err := json.Unmarshal(c.ABI, &c.abi); err != nil {
+		t.Fatal(err)
+	}
+
+	topics := [][]byte{
+		crypto.Keccak256([]byte("Transfer(address,address,uint256)")),
+		mustDecodeHex("000000000000000000000000000000000000000000000000000000000000aaaa"),
+		mustDecodeHex(ownerWord),
+	}
+	data := mustDecodeHex("00000000000000000000000000000000000000000000000000000000000003e8")
+
+	event, err := c.DecodeLog(topics, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Event{
+		Name: "Transfer",
+		Fields: map[string]interface{}{
+			"from":  "0x000000000000000000000000000000000000aaaa",
+			"to":    "0x000000000000000000000000000000000000ABcD",
+			"value": "1000",
+		},
+	}
+	if !reflect.DeepEqual(event, want) {
+		t.Errorf("decoded event %v, want %v", event, want)
+	}
+
+	if _, err := c.DecodeLog(topics[1:], data); errors.Root(err) != ErrUnpack {
+		t.Errorf("decode unknown event error = %v, want %v", err, ErrUnpack)
+	}
+	if _, err := c.DecodeLog(topics[:2], data); errors.Root(err) != ErrUnpack {
+		t.Errorf("decode short topics error = %v, want %v", err, ErrUnpack)
+	}
+}
@@ -0,0 +1,156 @@
+// Package contract keeps the ABI of the EVM contracts the wallet interacts
+// with, so that calls can be built from method names and JSON arguments and
+// the return data and the event logs can be decoded into named fields.
+package contract
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/basis/errors"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+const (
+	// AddressLength is the byte length of a contract address
+	AddressLength = 20
+
+	contractPrefix = "ContractABI:"
+)
+
+// pre-define errors for supporting errorFormatter
+var (
+	// ErrBadAddress is returned when the contract address is malformed.
+	ErrBadAddress = errors.New("invalid contract address")
+	// ErrBadABI is returned when the ABI definition can't be parsed.
+	ErrBadABI = errors.New("invalid contract abi")
+	// ErrDuplicateAlias is returned when another contract holds the alias.
+	ErrDuplicateAlias = errors.New("duplicate contract alias")
+	// ErrFindContract is returned when no ABI is registered for the address.
+	ErrFindContract = errors.New("fail to find contract")
+	// ErrFindMethod is returned when the ABI has no such method.
+	ErrFindMethod = errors.New("fail to find contract method")
+	// ErrBadArgument is returned when a JSON argument doesn't fit its ABI type.
+	ErrBadArgument = errors.New("invalid contract method argument")
+	// ErrUnpack is returned when the return data doesn't match the ABI.
+	ErrUnpack = errors.New("fail to unpack contract data")
+)
+
+// Contract is a contract address together with its ABI.
+type Contract struct {
+	Address chainjson.HexBytes `json:"address"`
+	Alias   string             `json:"alias,omitempty"`
+	ABI     json.RawMessage    `json:"abi"`
+
+	abi abi.ABI
+}
+
+// Registry stores the contract ABIs keyed by the contract address.
+type Registry struct {
+	db dbm.DB
+
+	mu        sync.RWMutex
+	contracts map[string]*Contract
+}
+
+func contractKey(address []byte) []byte {
+	return []byte(contractPrefix + hex.EncodeToString(address))
+}
+
+// NewRegistry create a contract registry and load the stored ABIs.
+func NewRegistry(db dbm.DB) *Registry {
+	r := &Registry{
+		db:        db,
+		contracts: make(map[string]*Contract),
+	}
+
+	iter := db.IteratorPrefix([]byte(contractPrefix))
+	defer iter.Release()
+	for iter.Next() {
+		c := &Contract{}
+		if err := json.Unmarshal(iter.Value(), c); err != nil {
+			log.WithField("err", err).Error("fail on load contract")
+			continue
+		}
+
+		if err := json.Unmarshal(c.ABI, &c.abi); err != nil {
+			log.WithFields(log.Fields{"err": err, "address": hex.EncodeToString(c.Address)}).Error("fail on parse contract abi")
+			continue
+		}
+		r.contracts[string(c.Address)] = c
+	}
+	return r
+}
+
+// Register stores the ABI of the contract, the ABI of an already registered
+// address is replaced.
+func (r *Registry) Register(address []byte, alias string, rawABI json.RawMessage) (*Contract, error) {
+	if len(address) != AddressLength {
+		return nil, errors.WithDetailf(ErrBadAddress, "address length %d", len(address))
+	}
+
+	c := &Contract{
+		Address: append([]byte{}, address...),
+		Alias:   strings.TrimSpace(alias),
+		ABI:     rawABI,
+	}
+	if err := json.Unmarshal(rawABI, &c.abi); err != nil {
+		return nil, errors.WithDetail(ErrBadABI, err.Error())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.Alias != "" {
+		for _, other := range r.contracts {
+			if other.Alias == c.Alias && !bytes.Equal(other.Address, c.Address) {
+				return nil, errors.WithDetailf(ErrDuplicateAlias, "alias %q already in use", c.Alias)
+			}
+		}
+	}
+
+	rawContract, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	r.db.Set(contractKey(c.Address), rawContract)
+	r.contracts[string(c.Address)] = c
+	return c, nil
+}
+
+// Find returns the contract registered for the address.
+func (r *Registry) Find(address []byte) (*Contract, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.contracts[string(address)]
+	if !ok {
+		return nil, errors.WithDetailf(ErrFindContract, "address %x", address)
+	}
+	return c, nil
+}
+
+// List returns all the registered contracts sorted by address.
+func (r *Registry) List() []*Contract {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	contracts := make([]*Contract, 0, len(r.contracts))
+	for _, c := range r.contracts {
+		contracts = append(contracts, c)
+	}
+	sort.Slice(contracts, func(i, j int) bool {
+		return bytes.Compare(contracts[i].Address, contracts[j].Address) < 0
+	})
+	return contracts
+}
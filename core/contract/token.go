@@ -0,0 +1,33 @@
+package contract
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// transferTopic is the topic of the ERC-20 Transfer(address,address,uint256) event
+var transferTopic = crypto.Keccak256([]byte("Transfer(address,address,uint256)"))
+
+// IsToken reports whether the ABI declares the ERC-20 Transfer event, the
+// wallet tracks the balances of such contracts.
+func (c *Contract) IsToken() bool {
+	event, ok := c.abi.Events["Transfer"]
+	if !ok || event.Anonymous || !bytes.Equal(event.Id().Bytes(), transferTopic) {
+		return false
+	}
+	return event.Inputs[0].Indexed && event.Inputs[1].Indexed && !event.Inputs[2].Indexed
+}
+
+// DecodeTransfer decodes the ERC-20 Transfer log, ok is false for any other
+// log including the ERC-721 Transfer which indexes the token id.
+func DecodeTransfer(topics [][]byte, data []byte) (from, to []byte, amount *big.Int, ok bool) {
+	if len(topics) != 3 || !bytes.Equal(topics[0], transferTopic) || len(data) != 32 {
+		return nil, nil, nil, false
+	}
+	if len(topics[1]) != 32 || len(topics[2]) != 32 {
+		return nil, nil, nil, false
+	}
+	return topics[1][12:], topics[2][12:], new(big.Int).SetBytes(data), true
+}
@@ -12,8 +12,9 @@ import (
 )
 
 const (
-	version = 1
-	keytype = "chain_kd"
+	version    = 1
+	keytype    = "chain_kd"
+	sm2KeyType = "sm2_kd"
 )
 
 // XKey struct type for keystore file
@@ -191,6 +191,7 @@ func (kc *keyCache) scan() ([]XPub, error) {
 		buf     = new(bufio.Reader)
 		keys    []XPub
 		keyJSON struct {
+			Type  string       `json:"type"`
 			Alias string       `json:"alias"`
 			XPub  chainkd.XPub `json:"xpub"`
 		}
@@ -210,7 +211,7 @@ func (kc *keyCache) scan() ([]XPub, error) {
 		}
 		buf.Reset(fd)
 		// Parse the address.
-		keyJSON.Alias = ""
+		keyJSON.Type, keyJSON.Alias = "", ""
 		err = json.NewDecoder(buf).Decode(&keyJSON)
 		switch {
 		case err != nil:
@@ -218,7 +219,7 @@ func (kc *keyCache) scan() ([]XPub, error) {
 		case (keyJSON.Alias == ""):
 			log.WithField("can't decode key, key path:", path).Warn("missing or void alias")
 		default:
-			keys = append(keys, XPub{XPub: keyJSON.XPub, Alias: keyJSON.Alias, File: path})
+			keys = append(keys, XPub{XPub: keyJSON.XPub, Alias: keyJSON.Alias, KeyScheme: keyScheme(keyJSON.Type), File: path})
 		}
 		fd.Close()
 	}
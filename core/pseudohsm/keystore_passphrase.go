@@ -19,6 +19,7 @@ import (
 	"github.com/doslink/doslink/basis/crypto"
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
 	"github.com/doslink/doslink/basis/crypto/randentropy"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/pborman/uuid"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
@@ -151,6 +152,9 @@ func DecryptKey(keyjson []byte, auth string) (*XKey, error) {
 	var xprv chainkd.XPrv
 	copy(xprv[:], keyBytes[:])
 	xpub := xprv.XPub()
+	if k.Type == sm2KeyType {
+		xpub = chainkd.XPub(sm2kd.XPrv(xprv).XPub())
+	}
 
 	//key := crypto.ToECDSA(keyBytes)
 	return &XKey{
@@ -167,7 +171,7 @@ func decryptKey(keyProtected *encryptedKeyJSON, auth string) (keyBytes []byte, k
 		return nil, nil, fmt.Errorf("Version not supported: %v", keyProtected.Version)
 	}
 
-	if keyProtected.Type != keytype {
+	if keyProtected.Type != keytype && keyProtected.Type != sm2KeyType {
 		return nil, nil, fmt.Errorf("Key type not supported: %v", keyProtected.Type)
 	}
 
@@ -8,7 +8,10 @@ import (
 	"sync"
 
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/basis/crypto/mnemonic"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/signers"
 	"github.com/pborman/uuid"
 )
 
@@ -32,9 +35,10 @@ type HSM struct {
 
 // XPub type for pubkey for anyone can see
 type XPub struct {
-	Alias string       `json:"alias"`
-	XPub  chainkd.XPub `json:"xpub"`
-	File  string       `json:"file"`
+	Alias     string       `json:"alias"`
+	XPub      chainkd.XPub `json:"xpub"`
+	KeyScheme string       `json:"key_scheme,omitempty"`
+	File      string       `json:"file"`
 }
 
 // New method for HSM struct
@@ -65,24 +69,117 @@ func (h *HSM) XCreate(alias string, auth string) (*XPub, error) {
 	return xpub, err
 }
 
+// XCreateSM2 produces a new random SM2 xprv and stores it in the db.
+func (h *HSM) XCreateSM2(alias string, auth string) (*XPub, error) {
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+
+	normalizedAlias := strings.ToLower(strings.TrimSpace(alias))
+	if ok := h.cache.hasAlias(normalizedAlias); ok {
+		return nil, ErrDuplicateKeyAlias
+	}
+
+	xprv, xpub, err := sm2kd.NewXKeys(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	xpb, err := h.storeKey(sm2KeyType, chainkd.XPrv(xprv), chainkd.XPub(xpub), auth, normalizedAlias)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.add(*xpb)
+	return xpb, nil
+}
+
+// XCreateMnemonic produces a new xprv from the seed of a random mnemonic, the
+// key is recovered with the mnemonic and the passphrase by RecoverKey.
+func (h *HSM) XCreateMnemonic(alias string, auth string, passphrase string) (*XPub, string, error) {
+	entropy, err := mnemonic.NewEntropy(mnemonic.DefaultEntropyBits)
+	if err != nil {
+		return nil, "", err
+	}
+
+	words, err := mnemonic.NewMnemonic(entropy)
+	if err != nil {
+		return nil, "", err
+	}
+
+	xpub, err := h.RecoverKey(alias, auth, words, passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+	return xpub, words, nil
+}
+
+// RecoverKey derives the root xprv from the mnemonic and the passphrase and
+// stores it in the db.
+func (h *HSM) RecoverKey(alias string, auth string, words string, passphrase string) (*XPub, error) {
+	seed, err := mnemonic.NewSeed(words, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	xprv := chainkd.RootXPrv(seed)
+
+	h.cacheMu.Lock()
+	defer h.cacheMu.Unlock()
+
+	normalizedAlias := strings.ToLower(strings.TrimSpace(alias))
+	if ok := h.cache.hasAlias(normalizedAlias); ok {
+		return nil, ErrDuplicateKeyAlias
+	}
+	if ok := h.cache.hasKey(xprv.XPub()); ok {
+		return nil, ErrDuplicateKey
+	}
+
+	xpub, err := h.storeChainKDKey(xprv, auth, normalizedAlias)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.add(*xpub)
+	return xpub, nil
+}
+
 func (h *HSM) createChainKDKey(auth string, alias string, get bool) (*XPub, bool, error) {
-	xprv, xpub, err := chainkd.NewXKeys(nil)
+	xprv, _, err := chainkd.NewXKeys(nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	xpub, err := h.storeChainKDKey(xprv, auth, alias)
 	if err != nil {
 		return nil, false, err
 	}
+	return xpub, true, nil
+}
+
+func (h *HSM) storeChainKDKey(xprv chainkd.XPrv, auth string, alias string) (*XPub, error) {
+	return h.storeKey(keytype, xprv, xprv.XPub(), auth, alias)
+}
+
+func (h *HSM) storeKey(keyType string, xprv chainkd.XPrv, xpub chainkd.XPub, auth string, alias string) (*XPub, error) {
 	id := uuid.NewRandom()
 	key := &XKey{
 		ID:      id,
-		KeyType: keytype,
+		KeyType: keyType,
 		XPub:    xpub,
 		XPrv:    xprv,
 		Alias:   alias,
 	}
 	file := h.keyStore.JoinPath(keyFileName(key.ID.String()))
 	if err := h.keyStore.StoreKey(file, key, auth); err != nil {
-		return nil, false, errors.Wrap(err, "storing keys")
+		return nil, errors.Wrap(err, "storing keys")
 	}
-	return &XPub{XPub: xpub, Alias: alias, File: file}, true, nil
+	return &XPub{XPub: xpub, Alias: alias, KeyScheme: keyScheme(keyType), File: file}, nil
+}
+
+// keyScheme returns the signers key scheme of the key type, it is empty for
+// the chainkd keys.
+func keyScheme(keyType string) string {
+	if keyType == sm2KeyType {
+		return signers.KeySchemeSM2
+	}
+	return ""
 }
 
 // ListKeys returns a list of all xpubs from the store
@@ -95,10 +192,20 @@ func (h *HSM) ListKeys() []XPub {
 // xprv with the given path (but does not store the new xprv), and
 // signs the given msg.
 func (h *HSM) XSign(xpub chainkd.XPub, path [][]byte, msg []byte, auth string) ([]byte, error) {
-	xprv, err := h.LoadChainKDKey(xpub, auth)
+	xkey, err := h.loadKey(xpub, auth)
 	if err != nil {
 		return nil, err
 	}
+
+	if xkey.KeyType == sm2KeyType {
+		xprv := sm2kd.XPrv(xkey.XPrv)
+		if len(path) > 0 {
+			xprv = xprv.Derive(path)
+		}
+		return xprv.Sign(msg)
+	}
+
+	xprv := xkey.XPrv
 	if len(path) > 0 {
 		xprv = xprv.Derive(path)
 	}
@@ -107,6 +214,14 @@ func (h *HSM) XSign(xpub chainkd.XPub, path [][]byte, msg []byte, auth string) (
 
 //LoadChainKDKey get xprv from xpub
 func (h *HSM) LoadChainKDKey(xpub chainkd.XPub, auth string) (xprv chainkd.XPrv, err error) {
+	xkey, err := h.loadKey(xpub, auth)
+	if err != nil {
+		return xprv, err
+	}
+	return xkey.XPrv, nil
+}
+
+func (h *HSM) loadKey(xpub chainkd.XPub, auth string) (*XKey, error) {
 	h.cacheMu.Lock()
 	defer h.cacheMu.Unlock()
 
@@ -116,10 +231,10 @@ func (h *HSM) LoadChainKDKey(xpub chainkd.XPub, auth string) (xprv chainkd.XPrv,
 
 	_, xkey, err := h.loadDecryptedKey(xpub, auth)
 	if err != nil {
-		return xprv, ErrLoadKey
+		return nil, ErrLoadKey
 	}
 	//h.kdCache[xpb.XPub] = xkey.XPrv
-	return xkey.XPrv, nil
+	return xkey, nil
 }
 
 // XDelete deletes the key matched by xpub if the passphrase is correct.
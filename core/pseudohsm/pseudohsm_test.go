@@ -6,7 +6,9 @@ import (
 	"testing"
 
 	"github.com/doslink/doslink/basis/crypto/ed25519"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/signers"
 )
 
 const dirPath = "testdata/pseudo"
@@ -174,3 +176,85 @@ func BenchmarkSign(b *testing.B) {
 		b.Fatal(err)
 	}
 }
+
+func TestRecoverKey(t *testing.T) {
+	hsm, _ := New(dirPath)
+
+	xpub, words, err := hsm.XCreateMnemonic("mnemonic", "password", "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := hsm.RecoverKey("recovered", "password", words, "passphrase"); errors.Root(err) != ErrDuplicateKey {
+		t.Fatalf("recover existing key error = %v, want %v", err, ErrDuplicateKey)
+	}
+
+	if err := hsm.XDelete(xpub.XPub, "password"); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered, err := hsm.RecoverKey("recovered", "password", words, "passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recovered.XPub != xpub.XPub {
+		t.Fatalf("recovered xpub %x, want %x", recovered.XPub, xpub.XPub)
+	}
+
+	other, err := hsm.RecoverKey("other", "password", words, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.XPub == xpub.XPub {
+		t.Fatal("the key of another passphrase is the same")
+	}
+
+	for _, key := range []*XPub{recovered, other} {
+		if err := hsm.XDelete(key.XPub, "password"); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestPseudoHSMSM2Keys(t *testing.T) {
+	hsm, _ := New(dirPath)
+	xpub, err := hsm.XCreateSM2("sm2", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xpub.KeyScheme != signers.KeySchemeSM2 {
+		t.Fatalf("key scheme = %s, want %s", xpub.KeyScheme, signers.KeySchemeSM2)
+	}
+
+	msg := []byte("In the face of ignorance and resistance I wrote financial systems into existence")
+	path := [][]byte{{3, 2, 6, 3, 8, 2, 7}}
+	sig, err := hsm.XSign(xpub.XPub, path, msg, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	derivedXPub, err := sm2kd.XPub(xpub.XPub).Derive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !derivedXPub.Verify(msg, sig) {
+		t.Error("expected verify with derived pubkey of sig from derived privkey to succeed")
+	}
+	if sm2kd.XPub(xpub.XPub).Verify(msg, sig) {
+		t.Error("expected verify with underived pubkey of sig from derived privkey to fail")
+	}
+
+	reloaded, _ := New(dirPath)
+	found := false
+	for _, key := range reloaded.ListKeys() {
+		if key.XPub == xpub.XPub {
+			found = key.KeyScheme == signers.KeySchemeSM2
+		}
+	}
+	if !found {
+		t.Error("expected the reloaded key to be a sm2 key")
+	}
+
+	if err := hsm.XDelete(xpub.XPub, "password"); err != nil {
+		t.Fatal(err)
+	}
+}
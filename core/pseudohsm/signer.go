@@ -0,0 +1,13 @@
+package pseudohsm
+
+import (
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+)
+
+// Signer holds the private keys and signs with them, it is the local HSM or
+// the client of a remote signer
+type Signer interface {
+	XCreate(alias string, auth string) (*XPub, error)
+	ListKeys() []XPub
+	XSign(xpub chainkd.XPub, path [][]byte, msg []byte, auth string) ([]byte, error)
+}
@@ -19,8 +19,11 @@ type AnnotatedTx struct {
 	Inputs                 []*AnnotatedInput  `json:"inputs"`
 	Outputs                []*AnnotatedOutput `json:"outputs"`
 	StatusFail             bool               `json:"status_fail"`
+	RevertData             chainjson.HexBytes `json:"revert_data,omitempty"`
+	RevertReason           string             `json:"revert_reason,omitempty"`
 	Size                   uint64             `json:"size"`
 	Logs                   []*AnnotatedLog    `json:"logs"`
+	TokenTransfers         []*TokenTransfer   `json:"token_transfers,omitempty"`
 	ReferenceData          *json.RawMessage   `json:"reference_data"`
 }
 
@@ -64,15 +67,34 @@ type AnnotatedLog struct {
 	Address chainjson.HexBytes   `json:"address,omitempty"`
 	Topics  []chainjson.HexBytes `json:"topics,omitempty"`
 	Data    chainjson.HexBytes   `json:"data,omitempty"`
+
+	Event  string                 `json:"event,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+//TokenTransfer means a token transfer from or to a local account.
+type TokenTransfer struct {
+	Contract         chainjson.HexBytes `json:"contract"`
+	TokenAlias       string             `json:"token_alias,omitempty"`
+	LogIndex         uint32             `json:"log_index"`
+	From             chainjson.HexBytes `json:"from"`
+	FromAccountID    string             `json:"from_account_id,omitempty"`
+	FromAccountAlias string             `json:"from_account_alias,omitempty"`
+	To               chainjson.HexBytes `json:"to"`
+	ToAccountID      string             `json:"to_account_id,omitempty"`
+	ToAccountAlias   string             `json:"to_account_alias,omitempty"`
+	Amount           string             `json:"amount"`
 }
 
 //AnnotatedAccount means an annotated account.
 type AnnotatedAccount struct {
-	ID       string         `json:"id"`
-	Alias    string         `json:"alias,omitempty"`
-	XPubs    []chainkd.XPub `json:"xpubs"`
-	Quorum   int            `json:"quorum"`
-	KeyIndex uint64         `json:"key_index"`
+	ID        string         `json:"id"`
+	Alias     string         `json:"alias,omitempty"`
+	KeyScheme string         `json:"key_scheme,omitempty"`
+	XPubs     []chainkd.XPub `json:"xpubs"`
+	Quorum    int            `json:"quorum"`
+	KeyIndex  uint64         `json:"key_index"`
+	WatchOnly bool           `json:"watch_only"`
 }
 
 //AnnotatedAsset means an annotated asset.
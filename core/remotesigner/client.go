@@ -0,0 +1,99 @@
+package remotesigner
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/rpc"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Client signs with the keys of a remote signer, it implements pseudohsm.Signer
+type Client struct {
+	rpc *rpc.Client
+}
+
+// NewClient creates the client of the signer url, the user info of the url is
+// sent as the token of the signer
+func NewClient(signerURL string) (*Client, error) {
+	u, err := url.Parse(signerURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse remote signer url")
+	}
+
+	token := ""
+	if u.User != nil {
+		password, _ := u.User.Password()
+		token = u.User.Username() + ":" + password
+		u.User = nil
+	}
+
+	return &Client{rpc: &rpc.Client{
+		BaseURL:     u.String(),
+		AccessToken: token,
+		Client:      &http.Client{Timeout: requestTimeout},
+	}}, nil
+}
+
+// XCreate creates a key on the remote signer
+func (c *Client) XCreate(alias string, auth string) (*pseudohsm.XPub, error) {
+	xpub := &pseudohsm.XPub{}
+	if err := c.call("/create-key", &createKeyReq{Alias: alias, Password: auth}, xpub); err != nil {
+		return nil, err
+	}
+	return xpub, nil
+}
+
+// ListKeys returns the keys of the remote signer, it returns nil once the
+// signer is unreachable
+func (c *Client) ListKeys() []pseudohsm.XPub {
+	xpubs := []pseudohsm.XPub{}
+	if err := c.call("/list-keys", nil, &xpubs); err != nil {
+		log.WithField("err", err).Error("fail on list keys of remote signer")
+		return nil
+	}
+	return xpubs
+}
+
+// XSign signs the msg with the key of the remote signer
+func (c *Client) XSign(xpub chainkd.XPub, path [][]byte, msg []byte, auth string) ([]byte, error) {
+	req := &signReq{XPub: xpub, Message: msg, Password: auth}
+	for _, p := range path {
+		req.Path = append(req.Path, chainjson.HexBytes(p))
+	}
+
+	resp := &signResp{}
+	if err := c.call("/sign", req, resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// call maps the error codes of the signer back to the errors of pseudohsm, the
+// callers handle them the same as the errors of the local HSM
+func (c *Client) call(path string, req, resp interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	err := c.rpc.Call(ctx, path, req, resp)
+	statusErr, ok := errors.Root(err).(rpc.ErrStatusCode)
+	if !ok || statusErr.ErrorData == nil {
+		return err
+	}
+
+	for rootErr, info := range errorInfos {
+		if info.ChainCode == statusErr.ErrorData.ChainCode {
+			return errors.WithDetail(rootErr, statusErr.ErrorData.Detail)
+		}
+	}
+	return err
+}
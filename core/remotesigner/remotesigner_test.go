@@ -0,0 +1,68 @@
+package remotesigner
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/pseudohsm"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	dirPath, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	hsm, err := pseudohsm.New(dirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewServer(hsm, "signer:secret"))
+	defer server.Close()
+
+	unauthorized, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := unauthorized.XCreate("key", "password"); errors.Root(err) != errNotAuthenticated {
+		t.Fatalf("create key without token error = %v, want %v", err, errNotAuthenticated)
+	}
+
+	client, err := NewClient(strings.Replace(server.URL, "://", "://signer:secret@", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var signer pseudohsm.Signer = client
+	xpub, err := signer.XCreate("key", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signer.XCreate("key", "password"); errors.Root(err) != pseudohsm.ErrDuplicateKeyAlias {
+		t.Fatalf("create duplicate key error = %v, want %v", err, pseudohsm.ErrDuplicateKeyAlias)
+	}
+
+	if keys := signer.ListKeys(); len(keys) != 1 || keys[0].XPub != xpub.XPub {
+		t.Fatalf("remote keys %v", keys)
+	}
+
+	msg := []byte("message")
+	path := [][]byte{{1, 2}, {3}}
+	sig, err := signer.XSign(xpub.XPub, path, msg, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !xpub.XPub.Derive(path).Verify(msg, sig) {
+		t.Fatal("remote signature is invalid")
+	}
+
+	if _, err := signer.XSign(xpub.XPub, path, msg, "wrong"); errors.Root(err) != pseudohsm.ErrLoadKey {
+		t.Fatalf("sign with wrong password error = %v, want %v", err, pseudohsm.ErrLoadKey)
+	}
+}
@@ -0,0 +1,111 @@
+// Package remotesigner serves the keys of a pseudohsm over HTTP, the client
+// signs with the keys from another host so that they never leave the signer.
+package remotesigner
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/net/http/httperror"
+	"github.com/doslink/doslink/net/http/httpjson"
+)
+
+var errNotAuthenticated = errors.New("signer request is not authenticated")
+
+// errorInfos is shared by the server and the client, the client maps the codes
+// of the responses back to the errors
+var errorInfos = map[error]httperror.Info{
+	httpjson.ErrBadRequest:         {HTTPStatus: 400, ChainCode: "002", Message: "Invalid request body"},
+	pseudohsm.ErrDuplicateKeyAlias: {HTTPStatus: 400, ChainCode: "800", Message: "Key Alias already exists"},
+	pseudohsm.ErrLoadKey:           {HTTPStatus: 400, ChainCode: "802", Message: "Key not found or wrong password"},
+	pseudohsm.ErrDecrypt:           {HTTPStatus: 400, ChainCode: "804", Message: "Could not decrypt key with given passphrase"},
+	errNotAuthenticated:            {HTTPStatus: 401, ChainCode: "860", Message: "Request could not be authenticated"},
+}
+
+var errorFormatter = httperror.Formatter{
+	Default:     httperror.Info{HTTPStatus: 500, ChainCode: "000", Message: "Remote Signer Error"},
+	IsTemporary: func(httperror.Info, error) bool { return false },
+	Errors:      errorInfos,
+}
+
+type createKeyReq struct {
+	Alias    string `json:"alias"`
+	Password string `json:"password"`
+}
+
+type signReq struct {
+	XPub     chainkd.XPub         `json:"xpub"`
+	Path     []chainjson.HexBytes `json:"path"`
+	Message  chainjson.HexBytes   `json:"message"`
+	Password string               `json:"password"`
+}
+
+type signResp struct {
+	Signature chainjson.HexBytes `json:"signature"`
+}
+
+// Server serves the keys of the HSM, the requests must carry the token as the
+// basic auth credentials once it is set
+type Server struct {
+	hsm   *pseudohsm.HSM
+	token string
+	mux   *http.ServeMux
+}
+
+// NewServer creates the signer server of the HSM
+func NewServer(hsm *pseudohsm.HSM, token string) *Server {
+	s := &Server{hsm: hsm, token: token, mux: http.NewServeMux()}
+	s.mux.Handle("/create-key", jsonHandler(s.createKey))
+	s.mux.Handle("/list-keys", jsonHandler(s.listKeys))
+	s.mux.Handle("/sign", jsonHandler(s.sign))
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if s.token != "" {
+		username, password, _ := req.BasicAuth()
+		if subtle.ConstantTimeCompare([]byte(username+":"+password), []byte(s.token)) != 1 {
+			errorFormatter.Write(req.Context(), w, errNotAuthenticated)
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, req)
+}
+
+func (s *Server) createKey(ctx context.Context, in createKeyReq) (*pseudohsm.XPub, error) {
+	return s.hsm.XCreate(in.Alias, in.Password)
+}
+
+func (s *Server) listKeys(ctx context.Context) []pseudohsm.XPub {
+	return s.hsm.ListKeys()
+}
+
+func (s *Server) sign(ctx context.Context, in signReq) (*signResp, error) {
+	path := make([][]byte, len(in.Path))
+	for i, p := range in.Path {
+		path[i] = p
+	}
+
+	sig, err := s.hsm.XSign(in.XPub, path, in.Message, in.Password)
+	if err != nil {
+		log.WithField("err", err).Warning("remote signer fail on sign")
+		return nil, err
+	}
+	return &signResp{Signature: sig}, nil
+}
+
+func jsonHandler(f interface{}) http.Handler {
+	h, err := httpjson.Handler(f, errorFormatter.Write)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
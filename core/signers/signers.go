@@ -7,6 +7,7 @@ import (
 	"sort"
 
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/basis/errors"
 )
 
@@ -17,6 +18,12 @@ const (
 	AccountKeySpace keySpace = 1
 )
 
+// The key schemes of the signer xpubs, the empty scheme is ed25519.
+const (
+	KeySchemeEd25519 = "ed25519"
+	KeySchemeSM2     = "sm2"
+)
+
 var (
 	// ErrBadQuorum is returned by Create when the quorum
 	// provided is less than 1 or greater than the number
@@ -38,16 +45,26 @@ var (
 	// ErrDupeXPub is returned by create when the same xpub
 	// appears twice in a single call.
 	ErrDupeXPub = errors.New("xpubs cannot contain the same key more than once")
+
+	// ErrBadKeyScheme is returned by create when the key scheme
+	// is not supported.
+	ErrBadKeyScheme = errors.New("unsupported key scheme")
 )
 
 // Signer is the abstract concept of a signer,
 // which is composed of a set of keys as well as
 // the amount of signatures needed for quorum.
 type Signer struct {
-	Type     string         `json:"type"`
-	XPubs    []chainkd.XPub `json:"xpubs"`
-	Quorum   int            `json:"quorum"`
-	KeyIndex uint64         `json:"key_index"`
+	Type      string         `json:"type"`
+	KeyScheme string         `json:"key_scheme,omitempty"`
+	XPubs     []chainkd.XPub `json:"xpubs"`
+	Quorum    int            `json:"quorum"`
+	KeyIndex  uint64         `json:"key_index"`
+}
+
+// IsSM2 returns whether the xpubs of the signer are the SM2 keys
+func (s *Signer) IsSM2() bool {
+	return s.KeyScheme == KeySchemeSM2
 }
 
 // Path returns the complete path for derived keys
@@ -66,10 +83,28 @@ func Path(s *Signer, ks keySpace, itemIndexes ...uint64) [][]byte {
 
 // Create creates and stores a Signer in the database
 func Create(signerType string, xpubs []chainkd.XPub, quorum int, keyIndex uint64) (*Signer, error) {
+	return CreateWithScheme(signerType, "", xpubs, quorum, keyIndex)
+}
+
+// CreateWithScheme creates a Signer of the xpubs of the key scheme
+func CreateWithScheme(signerType, keyScheme string, xpubs []chainkd.XPub, quorum int, keyIndex uint64) (*Signer, error) {
 	if len(xpubs) == 0 {
 		return nil, errors.Wrap(ErrNoXPubs)
 	}
 
+	switch keyScheme {
+	case "", KeySchemeEd25519:
+		keyScheme = ""
+	case KeySchemeSM2:
+		for _, xpub := range xpubs {
+			if !sm2kd.ValidPublicKey(sm2kd.XPub(xpub).PublicKey()) {
+				return nil, errors.WithDetailf(ErrBadXPub, "invalid sm2 key=%x", xpub)
+			}
+		}
+	default:
+		return nil, errors.WithDetailf(ErrBadKeyScheme, "key scheme=%s", keyScheme)
+	}
+
 	sort.Sort(sortKeys(xpubs)) // this transforms the input slice
 	for i := 1; i < len(xpubs); i++ {
 		if bytes.Equal(xpubs[i][:], xpubs[i-1][:]) {
@@ -82,10 +117,11 @@ func Create(signerType string, xpubs []chainkd.XPub, quorum int, keyIndex uint64
 	}
 
 	return &Signer{
-		Type:     signerType,
-		XPubs:    xpubs,
-		Quorum:   quorum,
-		KeyIndex: keyIndex,
+		Type:      signerType,
+		KeyScheme: keyScheme,
+		XPubs:     xpubs,
+		Quorum:    quorum,
+		KeyIndex:  keyIndex,
 	}, nil
 }
 
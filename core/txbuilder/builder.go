@@ -26,6 +26,7 @@ type TemplateBuilder struct {
 	maxTime             time.Time
 	timeRange           uint64
 	referenceData       []byte
+	estimatedGas        uint64
 	rollbacks           []func()
 	callbacks           []func() error
 }
@@ -49,6 +50,11 @@ func (b *TemplateBuilder) AddOutput(o *types.TxOutput) error {
 	return nil
 }
 
+// AddEstimatedGas add the estimated gas of a contract call or creation
+func (b *TemplateBuilder) AddEstimatedGas(gas uint64) {
+	b.estimatedGas += gas
+}
+
 // RestrictMinTime set minTime
 func (b *TemplateBuilder) RestrictMinTime(t time.Time) {
 	if t.After(b.minTime) {
@@ -112,7 +118,7 @@ func (b *TemplateBuilder) Build() (*Template, *types.TxData, error) {
 		}
 	}
 
-	tpl := &Template{}
+	tpl := &Template{EstimatedGas: b.estimatedGas}
 	tx := b.base
 	if tx == nil {
 		tx = &types.TxData{
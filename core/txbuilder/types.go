@@ -21,6 +21,10 @@ type Template struct {
 	// ones cannot be changed. When false, signatures commit to the tx
 	// as a whole, and any change to the tx invalidates the signature.
 	AllowAdditional bool `json:"allow_additional_actions"`
+
+	// EstimatedGas is the gas the contract calls and creations of the tx
+	// are estimated to use on the best block state.
+	EstimatedGas uint64 `json:"estimated_gas,omitempty"`
 }
 
 // Hash return sign hash
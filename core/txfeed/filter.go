@@ -0,0 +1,514 @@
+package txfeed
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/protocol/vm"
+)
+
+// ErrBadFilter is returned when a feed filter expression can't be parsed.
+var ErrBadFilter = errors.New("invalid transaction feed filter")
+
+// A filter expression is a boolean combination of comparisons over the
+// fields of an annotated transaction, for example:
+//
+//	asset_id='ab..cd' AND outputs(account_alias='alice' AND amount>=100)
+//
+// The grammar is:
+//
+//	expr    = and { "OR" and }
+//	and     = unary { "AND" unary }
+//	unary   = "NOT" unary | primary
+//	primary = "(" expr ")" | scope "(" expr ")" | field op value
+//	scope   = "inputs" | "outputs"
+//	op      = "=" | "!=" | "<" | "<=" | ">" | ">="
+//
+// Inside inputs(...) or outputs(...) every comparison must hold for the
+// same input or output. An input/output field used outside of a scope
+// matches when any input or output of the transaction satisfies it.
+
+type fieldKind int
+
+const (
+	stringField fieldKind = iota
+	numberField
+	boolField
+)
+
+// txFields are the transaction level fields a filter can refer to.
+var txFields = map[string]fieldKind{
+	"tx_id":        stringField,
+	"block_hash":   stringField,
+	"block_height": numberField,
+	"status_fail":  boolField,
+}
+
+// ioFields are the fields shared by annotated inputs and outputs.
+var ioFields = map[string]fieldKind{
+	"type":            stringField,
+	"asset_id":        stringField,
+	"asset_alias":     stringField,
+	"amount":          numberField,
+	"account_id":      stringField,
+	"account_alias":   stringField,
+	"control_program": stringField,
+	"address":         stringField,
+	"contract":        stringField,
+}
+
+// record is the value a predicate is evaluated against: either the
+// transaction itself or a single input or output of it.
+type record interface {
+	field(name string) (interface{}, bool)
+}
+
+type txRecord struct{ tx *query.AnnotatedTx }
+
+func (r txRecord) field(name string) (interface{}, bool) {
+	switch name {
+	case "tx_id":
+		return r.tx.ID.String(), true
+	case "block_hash":
+		return r.tx.BlockID.String(), true
+	case "block_height":
+		return r.tx.BlockHeight, true
+	case "status_fail":
+		return r.tx.StatusFail, true
+	}
+	return nil, false
+}
+
+type inputRecord struct{ in *query.AnnotatedInput }
+
+func (r inputRecord) field(name string) (interface{}, bool) {
+	switch name {
+	case "type":
+		return r.in.Type, true
+	case "asset_id":
+		return r.in.AssetID.String(), true
+	case "asset_alias":
+		return r.in.AssetAlias, true
+	case "amount":
+		return r.in.Amount, true
+	case "account_id":
+		return r.in.AccountID, true
+	case "account_alias":
+		return r.in.AccountAlias, true
+	case "control_program":
+		return hex.EncodeToString(r.in.ControlProgram), true
+	case "address":
+		return r.in.Address, true
+	case "contract":
+		return hex.EncodeToString(r.in.Contract), true
+	}
+	return nil, false
+}
+
+type outputRecord struct{ out *query.AnnotatedOutput }
+
+func (r outputRecord) field(name string) (interface{}, bool) {
+	switch name {
+	case "type":
+		return r.out.Type, true
+	case "asset_id":
+		return r.out.AssetID.String(), true
+	case "asset_alias":
+		return r.out.AssetAlias, true
+	case "amount":
+		return r.out.Amount, true
+	case "account_id":
+		return r.out.AccountID, true
+	case "account_alias":
+		return r.out.AccountAlias, true
+	case "control_program":
+		return hex.EncodeToString(r.out.ControlProgram), true
+	case "address":
+		return r.out.Address, true
+	case "contract":
+		return hex.EncodeToString(outputContract(r.out.ControlProgram)), true
+	}
+	return nil, false
+}
+
+// outputContract returns the contract address of the vmutil.P2ContractProgram
+// the output pays to, it is empty for the other control programs.
+func outputContract(prog []byte) []byte {
+	insts, err := vm.ParseProgram(prog)
+	if err != nil || len(insts) != 3 || insts[0].Op != vm.OP_FAIL {
+		return nil
+	}
+	if addr := insts[2].Data; insts[2].Op == vm.OP_DATA_20 && len(addr) == 20 {
+		return addr
+	}
+	return nil
+}
+
+// predicate is a parsed filter expression.
+type predicate interface {
+	eval(tx *query.AnnotatedTx, r record) bool
+}
+
+type orPredicate struct{ left, right predicate }
+
+func (p orPredicate) eval(tx *query.AnnotatedTx, r record) bool {
+	return p.left.eval(tx, r) || p.right.eval(tx, r)
+}
+
+type andPredicate struct{ left, right predicate }
+
+func (p andPredicate) eval(tx *query.AnnotatedTx, r record) bool {
+	return p.left.eval(tx, r) && p.right.eval(tx, r)
+}
+
+type notPredicate struct{ inner predicate }
+
+func (p notPredicate) eval(tx *query.AnnotatedTx, r record) bool {
+	return !p.inner.eval(tx, r)
+}
+
+// scopePredicate holds when a single input (or output) satisfies inner.
+type scopePredicate struct {
+	inputs bool
+	inner  predicate
+}
+
+func (p scopePredicate) eval(tx *query.AnnotatedTx, _ record) bool {
+	if p.inputs {
+		for _, in := range tx.Inputs {
+			if p.inner.eval(tx, inputRecord{in}) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, out := range tx.Outputs {
+		if p.inner.eval(tx, outputRecord{out}) {
+			return true
+		}
+	}
+	return false
+}
+
+type comparison struct {
+	field string
+	op    string
+	kind  fieldKind
+	str   string
+	num   uint64
+	flag  bool
+}
+
+func (c comparison) eval(tx *query.AnnotatedTx, r record) bool {
+	if r != nil {
+		if v, ok := r.field(c.field); ok {
+			return c.compare(v)
+		}
+	}
+	if v, ok := (txRecord{tx}).field(c.field); ok {
+		return c.compare(v)
+	}
+
+	// an input/output field outside of any scope matches any of them
+	for _, in := range tx.Inputs {
+		if v, ok := (inputRecord{in}).field(c.field); ok && c.compare(v) {
+			return true
+		}
+	}
+	for _, out := range tx.Outputs {
+		if v, ok := (outputRecord{out}).field(c.field); ok && c.compare(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparison) compare(v interface{}) bool {
+	switch c.kind {
+	case stringField:
+		eq := strings.EqualFold(v.(string), c.str)
+		if c.op == "!=" {
+			return !eq
+		}
+		return eq
+
+	case boolField:
+		if c.op == "!=" {
+			return v.(bool) != c.flag
+		}
+		return v.(bool) == c.flag
+
+	default:
+		n := v.(uint64)
+		switch c.op {
+		case "=":
+			return n == c.num
+		case "!=":
+			return n != c.num
+		case "<":
+			return n < c.num
+		case "<=":
+			return n <= c.num
+		case ">":
+			return n > c.num
+		case ">=":
+			return n >= c.num
+		}
+	}
+	return false
+}
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	typ tokenType
+	val string
+	pos int
+}
+
+func lex(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := rune(s[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.WithDetailf(ErrBadFilter, "unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokString, s[i+1 : i+1+end], i})
+			i += end + 2
+
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if c != '=' && i+1 < len(s) && s[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, errors.WithDetailf(ErrBadFilter, "unexpected '!' at position %d", i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+
+		case unicode.IsDigit(c):
+			start := i
+			for i < len(s) && unicode.IsDigit(rune(s[i])) {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, s[start:i], start})
+
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(s) && (unicode.IsLetter(rune(s[i])) || unicode.IsDigit(rune(s[i])) || s[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, s[start:i], start})
+
+		default:
+			return nil, errors.WithDetailf(ErrBadFilter, "unexpected %q at position %d", c, i)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(s)}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	// scoped reports whether the parser is inside inputs(...) or outputs(...)
+	scoped bool
+}
+
+// parseFilter compiles a filter expression into a predicate.
+func parseFilter(s string) (predicate, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, errors.WithDetail(ErrBadFilter, "empty filter")
+	}
+
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	pred, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.typ != tokEOF {
+		return nil, errors.WithDetailf(ErrBadFilter, "unexpected %q at position %d", tok.val, tok.pos)
+	}
+	return pred, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.typ != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.typ == tokIdent && strings.EqualFold(tok.val, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(typ tokenType, what string) error {
+	if tok := p.next(); tok.typ != typ {
+		return errors.WithDetailf(ErrBadFilter, "expected %s at position %d", what, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (predicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orPredicate{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (predicate, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andPredicate{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (predicate, error) {
+	if p.keyword("NOT") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notPredicate{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (predicate, error) {
+	tok := p.next()
+	switch tok.typ {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(tokRParen, "')'")
+
+	case tokIdent:
+		name := strings.ToLower(tok.val)
+		if name == "inputs" || name == "outputs" {
+			return p.parseScope(name == "inputs", tok)
+		}
+		return p.parseComparison(name, tok)
+	}
+	return nil, errors.WithDetailf(ErrBadFilter, "unexpected %q at position %d", tok.val, tok.pos)
+}
+
+func (p *parser) parseScope(inputs bool, tok token) (predicate, error) {
+	if p.scoped {
+		return nil, errors.WithDetailf(ErrBadFilter, "nested %s(...) at position %d", tok.val, tok.pos)
+	}
+	if err := p.expect(tokLParen, "'('"); err != nil {
+		return nil, err
+	}
+
+	p.scoped = true
+	inner, err := p.parseOr()
+	p.scoped = false
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokRParen, "')'"); err != nil {
+		return nil, err
+	}
+	return scopePredicate{inputs: inputs, inner: inner}, nil
+}
+
+func (p *parser) parseComparison(name string, tok token) (predicate, error) {
+	kind, ok := ioFields[name]
+	if !ok {
+		if kind, ok = txFields[name]; !ok || p.scoped {
+			return nil, errors.WithDetailf(ErrBadFilter, "unknown field %q at position %d", tok.val, tok.pos)
+		}
+	}
+
+	opTok := p.next()
+	if opTok.typ != tokOp {
+		return nil, errors.WithDetailf(ErrBadFilter, "expected operator at position %d", opTok.pos)
+	}
+	c := comparison{field: name, op: opTok.val, kind: kind}
+	if kind != numberField && c.op != "=" && c.op != "!=" {
+		return nil, errors.WithDetailf(ErrBadFilter, "operator %s not supported on %s", c.op, name)
+	}
+
+	valTok := p.next()
+	switch {
+	case kind == stringField && valTok.typ == tokString:
+		c.str = valTok.val
+
+	case kind == numberField && valTok.typ == tokNumber:
+		num, err := strconv.ParseUint(valTok.val, 10, 64)
+		if err != nil {
+			return nil, errors.WithDetailf(ErrBadFilter, "invalid number %s at position %d", valTok.val, valTok.pos)
+		}
+		c.num = num
+
+	case kind == boolField && valTok.typ == tokIdent && (strings.EqualFold(valTok.val, "true") || strings.EqualFold(valTok.val, "false")):
+		c.flag = strings.EqualFold(valTok.val, "true")
+
+	default:
+		return nil, errors.WithDetailf(ErrBadFilter, "invalid value for %s at position %d", name, valTok.pos)
+	}
+	return c, nil
+}
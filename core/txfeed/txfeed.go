@@ -0,0 +1,320 @@
+// Package txfeed maintains user defined transaction feeds: named filters
+// that are evaluated against every block the wallet attaches, together with
+// the list of transactions each filter has matched so far.
+package txfeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/core/signers"
+)
+
+const (
+	// FeedNumMax is the max number of transaction feeds a wallet can hold.
+	FeedNumMax = 1024
+
+	defaultPageSize = 100
+
+	feedPrefix   = "TXF:"
+	feedTxPrefix = "TFT:"
+)
+
+// pre-define errors for supporting errorFormatter
+var (
+	// ErrDuplicateAlias is returned when a feed with the same alias exists.
+	ErrDuplicateAlias = errors.New("duplicate transaction feed alias")
+	// ErrEmptyAlias is returned when a feed is created without alias.
+	ErrEmptyAlias = errors.New("empty transaction feed alias")
+	// ErrFindTxFeed is returned when no feed matches the given alias.
+	ErrFindTxFeed = errors.New("fail to find transaction feed")
+	// ErrNumExceedLimit is returned when the feed number reaches FeedNumMax.
+	ErrNumExceedLimit = errors.New("transaction feed number exceeds limit")
+	// ErrBadCursor is returned when the after cursor is malformed.
+	ErrBadCursor = errors.New("invalid transaction feed cursor")
+)
+
+// TxFeed describes a transaction feed.
+type TxFeed struct {
+	ID     string `json:"id"`
+	Alias  string `json:"alias"`
+	Filter string `json:"filter"`
+
+	pred predicate
+}
+
+// Match reports whether the annotated transaction satisfies the feed filter.
+func (f *TxFeed) Match(tx *query.AnnotatedTx) bool {
+	return f.pred.eval(tx, nil)
+}
+
+// Tracker stores the transaction feeds and the transactions they matched.
+type Tracker struct {
+	db dbm.DB
+
+	mu    sync.RWMutex
+	feeds map[string]*TxFeed
+}
+
+func feedKey(alias string) []byte {
+	return []byte(feedPrefix + alias)
+}
+
+func feedTxPrefixKey(feedID string) []byte {
+	return []byte(feedTxPrefix + feedID + ":")
+}
+
+// cursor is the position of a transaction in the chain, it is used both as
+// the suffix of the feed transaction key and as the paging cursor.
+func cursor(blockHeight uint64, position uint32) string {
+	return fmt.Sprintf("%016x%08x", blockHeight, position)
+}
+
+func feedTxKey(feedID string, blockHeight uint64, position uint32) []byte {
+	return []byte(feedTxPrefix + feedID + ":" + cursor(blockHeight, position))
+}
+
+func feedBlockKey(feedID string, blockHeight uint64) []byte {
+	return []byte(fmt.Sprintf("%s%s:%016x", feedTxPrefix, feedID, blockHeight))
+}
+
+// NewTracker create a transaction feed tracker and load the stored feeds.
+func NewTracker(db dbm.DB) *Tracker {
+	t := &Tracker{
+		db:    db,
+		feeds: make(map[string]*TxFeed),
+	}
+
+	iter := db.IteratorPrefix([]byte(feedPrefix))
+	defer iter.Release()
+	for iter.Next() {
+		feed := &TxFeed{}
+		if err := json.Unmarshal(iter.Value(), feed); err != nil {
+			log.WithField("err", err).Error("fail on load transaction feed")
+			continue
+		}
+
+		pred, err := parseFilter(feed.Filter)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err, "alias": feed.Alias}).Error("fail on parse transaction feed filter")
+			continue
+		}
+		feed.pred = pred
+		t.feeds[feed.Alias] = feed
+	}
+	return t
+}
+
+// Create create and store a new transaction feed.
+func (t *Tracker) Create(alias, filter string) (*TxFeed, error) {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return nil, ErrEmptyAlias
+	}
+
+	pred, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.feeds[alias]; ok {
+		return nil, errors.WithDetailf(ErrDuplicateAlias, "alias %q already in use", alias)
+	}
+	if len(t.feeds) >= FeedNumMax {
+		return nil, ErrNumExceedLimit
+	}
+
+	feed := &TxFeed{
+		ID:     signers.IDGenerate(),
+		Alias:  alias,
+		Filter: filter,
+		pred:   pred,
+	}
+	if err := t.saveFeed(feed); err != nil {
+		return nil, err
+	}
+
+	t.feeds[alias] = feed
+	return feed, nil
+}
+
+// Update replaces the filter of an existing transaction feed, the
+// transactions already matched by the feed are kept.
+func (t *Tracker) Update(alias, filter string) (*TxFeed, error) {
+	pred, err := parseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	feed, ok := t.feeds[alias]
+	if !ok {
+		return nil, errors.WithDetailf(ErrFindTxFeed, "alias %q", alias)
+	}
+
+	updated := &TxFeed{ID: feed.ID, Alias: alias, Filter: filter, pred: pred}
+	if err := t.saveFeed(updated); err != nil {
+		return nil, err
+	}
+
+	t.feeds[alias] = updated
+	return updated, nil
+}
+
+func (t *Tracker) saveFeed(feed *TxFeed) error {
+	rawFeed, err := json.Marshal(feed)
+	if err != nil {
+		return err
+	}
+
+	t.db.Set(feedKey(feed.Alias), rawFeed)
+	return nil
+}
+
+// Delete removes the transaction feed and all the transactions it matched.
+func (t *Tracker) Delete(alias string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	feed, ok := t.feeds[alias]
+	if !ok {
+		return errors.WithDetailf(ErrFindTxFeed, "alias %q", alias)
+	}
+
+	storeBatch := t.db.NewBatch()
+	iter := t.db.IteratorPrefix(feedTxPrefixKey(feed.ID))
+	defer iter.Release()
+	for iter.Next() {
+		storeBatch.Delete(iter.Key())
+	}
+	storeBatch.Delete(feedKey(alias))
+	storeBatch.Write()
+
+	delete(t.feeds, alias)
+	return nil
+}
+
+// Get returns the transaction feed with the given alias.
+func (t *Tracker) Get(alias string) (*TxFeed, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	feed, ok := t.feeds[alias]
+	if !ok {
+		return nil, errors.WithDetailf(ErrFindTxFeed, "alias %q", alias)
+	}
+	return feed, nil
+}
+
+// List returns all the transaction feeds sorted by alias.
+func (t *Tracker) List() []*TxFeed {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	feeds := make([]*TxFeed, 0, len(t.feeds))
+	iter := t.db.IteratorPrefix([]byte(feedPrefix))
+	defer iter.Release()
+	for iter.Next() {
+		if feed, ok := t.feeds[string(iter.Key()[len(feedPrefix):])]; ok {
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds
+}
+
+// IndexTransactions records the transactions of the block at blockHeight
+// that match each feed, the writes are added to the given batch so that
+// they are committed together with the wallet status.
+func (t *Tracker) IndexTransactions(batch dbm.Batch, blockHeight uint64, txs []*query.AnnotatedTx) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, feed := range t.feeds {
+		for _, tx := range txs {
+			if !feed.Match(tx) {
+				continue
+			}
+
+			rawTx, err := json.Marshal(tx)
+			if err != nil {
+				return err
+			}
+			batch.Set(feedTxKey(feed.ID, blockHeight, tx.Position), rawTx)
+		}
+	}
+	return nil
+}
+
+// DeleteTransactions removes the transactions every feed matched at
+// blockHeight, it is called when the block is detached on reorganize.
+func (t *Tracker) DeleteTransactions(batch dbm.Batch, blockHeight uint64) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, feed := range t.feeds {
+		iter := t.db.IteratorPrefix(feedBlockKey(feed.ID, blockHeight))
+		for iter.Next() {
+			batch.Delete(iter.Key())
+		}
+		iter.Release()
+	}
+}
+
+// HasFeeds reports whether any transaction feed is defined.
+func (t *Tracker) HasFeeds() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.feeds) > 0
+}
+
+// ListTransactions returns at most limit transactions matched by the feed
+// in chain order, starting right after the after cursor. The returned
+// cursor is the position of the last transaction in the page and should be
+// passed as after to fetch the following page.
+func (t *Tracker) ListTransactions(alias, after string, limit int) ([]*query.AnnotatedTx, string, error) {
+	if after != "" && len(after) != len(cursor(0, 0)) {
+		return nil, "", errors.WithDetailf(ErrBadCursor, "after %q", after)
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	feed, err := t.Get(alias)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prefix := feedTxPrefixKey(feed.ID)
+	txs := []*query.AnnotatedTx{}
+	next := after
+
+	iter := t.db.IteratorPrefix(prefix)
+	defer iter.Release()
+	for iter.Next() && len(txs) < limit {
+		pos := string(iter.Key()[len(prefix):])
+		if pos <= after {
+			continue
+		}
+
+		tx := &query.AnnotatedTx{}
+		if err := json.Unmarshal(iter.Value(), tx); err != nil {
+			return nil, "", err
+		}
+		txs = append(txs, tx)
+		next = pos
+	}
+	return txs, next, nil
+}
@@ -0,0 +1,156 @@
+package txfeed
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/vm"
+	"github.com/doslink/doslink/protocol/vmutil"
+)
+
+func mockAnnotatedTx(height uint64, position uint32, accountAlias string, amount uint64) *query.AnnotatedTx {
+	return &query.AnnotatedTx{
+		ID:          bc.NewHash([32]byte{byte(height), byte(position)}),
+		BlockHeight: height,
+		Position:    position,
+		Inputs: []*query.AnnotatedInput{
+			{Type: "spend", AssetID: *consensus.NativeAssetID, Amount: amount + 1, AccountAlias: "bob"},
+		},
+		Outputs: []*query.AnnotatedOutput{
+			{Type: "control", AssetID: *consensus.NativeAssetID, Amount: amount, AccountAlias: accountAlias},
+		},
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tx := mockAnnotatedTx(10, 1, "alice", 100)
+
+	cases := []struct {
+		filter string
+		match  bool
+		err    error
+	}{
+		{filter: "asset_id='" + consensus.NativeAssetID.String() + "'", match: true},
+		{filter: "account_alias='alice'", match: true},
+		{filter: "account_alias='carol'", match: false},
+		{filter: "outputs(account_alias='alice' AND amount>=100)", match: true},
+		{filter: "outputs(account_alias='bob')", match: false},
+		{filter: "inputs(account_alias='bob' AND amount=101)", match: true},
+		{filter: "inputs(account_alias='bob' AND amount=100)", match: false},
+		{filter: "block_height>10 OR (status_fail=false AND type='control')", match: true},
+		{filter: "NOT block_height=10", match: false},
+		{filter: "", err: ErrBadFilter},
+		{filter: "unknown='a'", err: ErrBadFilter},
+		{filter: "outputs(block_height=1)", err: ErrBadFilter},
+		{filter: "account_alias>'a'", err: ErrBadFilter},
+		{filter: "account_alias='a", err: ErrBadFilter},
+		{filter: "(amount=1", err: ErrBadFilter},
+	}
+
+	for i, c := range cases {
+		pred, err := parseFilter(c.filter)
+		if errors.Root(err) != c.err {
+			t.Errorf("case %d: parseFilter(%q) error = %v, want %v", i, c.filter, err, c.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := pred.eval(tx, nil); got != c.match {
+			t.Errorf("case %d: filter %q match = %v, want %v", i, c.filter, got, c.match)
+		}
+	}
+}
+
+func TestFilterContract(t *testing.T) {
+	contract := bytes.Repeat([]byte{0xab}, 20)
+	prog, err := vmutil.P2ContractProgram(int64(vm.VM_EVM), contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := mockAnnotatedTx(10, 1, "alice", 100)
+	tx.Outputs = append(tx.Outputs, &query.AnnotatedOutput{Type: "control", AssetID: *consensus.NativeAssetID, Amount: 1, ControlProgram: prog})
+
+	cases := []struct {
+		filter string
+		match  bool
+	}{
+		{filter: "outputs(contract='" + hex.EncodeToString(contract) + "')", match: true},
+		{filter: "outputs(contract='" + hex.EncodeToString(contract) + "' AND amount=1)", match: true},
+		{filter: "outputs(contract='" + hex.EncodeToString(contract) + "' AND amount=100)", match: false},
+		{filter: "contract='" + hex.EncodeToString(contract) + "'", match: true},
+		{filter: "outputs(contract='cdcd')", match: false},
+	}
+
+	for i, c := range cases {
+		pred, err := parseFilter(c.filter)
+		if err != nil {
+			t.Fatalf("case %d: parseFilter(%q) error = %v", i, c.filter, err)
+		}
+		if got := pred.eval(tx, nil); got != c.match {
+			t.Errorf("case %d: filter %q match = %v, want %v", i, c.filter, got, c.match)
+		}
+	}
+}
+
+func TestTrackerIndexAndDetach(t *testing.T) {
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+
+	tracker := NewTracker(testDB)
+	if _, err := tracker.Create("alice", "outputs(account_alias='alice')"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tracker.Create("alice", "amount>0"); errors.Root(err) != ErrDuplicateAlias {
+		t.Fatalf("create duplicate feed error = %v, want %v", err, ErrDuplicateAlias)
+	}
+
+	for height := uint64(1); height <= 3; height++ {
+		batch := testDB.NewBatch()
+		txs := []*query.AnnotatedTx{
+			mockAnnotatedTx(height, 0, "alice", 1),
+			mockAnnotatedTx(height, 1, "carol", 1),
+		}
+		if err := tracker.IndexTransactions(batch, height, txs); err != nil {
+			t.Fatal(err)
+		}
+		batch.Write()
+	}
+
+	txs, next, err := tracker.ListTransactions("alice", "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 || txs[0].BlockHeight != 1 || txs[1].BlockHeight != 2 {
+		t.Fatalf("first page got %d transactions", len(txs))
+	}
+
+	batch := testDB.NewBatch()
+	tracker.DeleteTransactions(batch, 3)
+	batch.Write()
+
+	// the feed definitions survive a restart
+	tracker = NewTracker(testDB)
+	if txs, _, err = tracker.ListTransactions("alice", next, 10); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 0 {
+		t.Fatalf("detached block transactions still listed: %d", len(txs))
+	}
+
+	if err := tracker.Delete("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tracker.Get("alice"); errors.Root(err) != ErrFindTxFeed {
+		t.Fatalf("get deleted feed error = %v, want %v", err, ErrFindTxFeed)
+	}
+}
@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"encoding/json"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/basis/crypto/sha3pool"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+// extendAddressGap keeps account.AddressGap addresses derived ahead of the
+// addresses used by the block outputs, the block is checked again once new
+// addresses are derived since they may be used by the block as well
+func (w *Wallet) extendAddressGap(block *types.Block) error {
+	for {
+		usedIndexes := make(map[string]uint64)
+		for _, tx := range block.Transactions {
+			for _, output := range tx.Outputs {
+				cp := w.getLocalCtrlProgram(output.ControlProgram)
+				if cp != nil && cp.KeyIndex >= usedIndexes[cp.AccountID] {
+					usedIndexes[cp.AccountID] = cp.KeyIndex
+				}
+			}
+		}
+
+		extended := false
+		for accountID, usedIndex := range usedIndexes {
+			ok, err := w.AccountMgr.ExtendAddressGap(accountID, usedIndex)
+			if err != nil {
+				return err
+			}
+			extended = extended || ok
+		}
+
+		if !extended {
+			return nil
+		}
+	}
+}
+
+// extendAccountsAddressGap derives the first addresses of every account before
+// a rescan, the accounts of a recovered key have no address to match yet
+func (w *Wallet) extendAccountsAddressGap() {
+	accounts, err := w.AccountMgr.ListAccounts("")
+	if err != nil {
+		log.WithField("err", err).Error("extendAccountsAddressGap fail on list accounts")
+		return
+	}
+
+	for _, acc := range accounts {
+		if _, err := w.AccountMgr.ExtendAddressGap(acc.ID, 0); err != nil {
+			log.WithField("err", err).Error("extendAccountsAddressGap fail on extend address gap")
+		}
+	}
+}
+
+func (w *Wallet) getLocalCtrlProgram(program []byte) *account.CtrlProgram {
+	var hash [32]byte
+	sha3pool.Sum256(hash[:], program)
+	data := w.DB.Get(account.ContractKey(hash))
+	if data == nil {
+		return nil
+	}
+
+	cp := &account.CtrlProgram{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		log.WithField("err", err).Error("getLocalCtrlProgram fail on unmarshal control program")
+		return nil
+	}
+	return cp
+}
@@ -0,0 +1,85 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/database/leveldb"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+func TestExtendAddressGap(t *testing.T) {
+	dirPath, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+
+	store := leveldb.NewStore(testDB)
+	chain, err := protocol.NewChain(store, protocol.NewTxPool(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountManager := account.NewManager(testDB, chain)
+	hsm, err := pseudohsm.New(dirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := hsm.XCreate("test_pub", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAccount, err := accountManager.Create([]chainkd.XPub{xpub.XPub}, 1, "testAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := mockWallet(testDB, accountManager, nil, chain)
+	w.extendAccountsAddressGap()
+
+	cps, err := accountManager.ListControlProgram()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cps) != account.AddressGap {
+		t.Fatalf("got %d control programs before the block, want %d", len(cps), account.AddressGap)
+	}
+
+	var lastCP *account.CtrlProgram
+	for _, cp := range cps {
+		if cp.KeyIndex == account.AddressGap {
+			lastCP = cp
+		}
+	}
+
+	tx := types.NewTx(types.TxData{
+		Outputs: []*types.TxOutput{types.NewTxOutput(bc.AssetID{}, 100, lastCP.ControlProgram)},
+	})
+	if err := w.extendAddressGap(mockSingleBlock(tx)); err != nil {
+		t.Fatal(err)
+	}
+
+	if cps, err = accountManager.ListControlProgram(); err != nil {
+		t.Fatal(err)
+	}
+	if len(cps) != 2*account.AddressGap {
+		t.Fatalf("got %d control programs after the block, want %d", len(cps), 2*account.AddressGap)
+	}
+	for _, cp := range cps {
+		if cp.AccountID != testAccount.ID {
+			t.Fatalf("control program of account %s, want %s", cp.AccountID, testAccount.ID)
+		}
+	}
+}
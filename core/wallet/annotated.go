@@ -19,6 +19,7 @@ import (
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/vm"
+	"github.com/doslink/doslink/protocol/vm/state"
 	"github.com/doslink/doslink/protocol/vmutil"
 )
 
@@ -36,6 +37,26 @@ func annotateTxsAsset(w *Wallet, txs []*query.AnnotatedTx) {
 	}
 }
 
+// annotateTxsContract decodes the logs of the contracts with a registered ABI
+func annotateTxsContract(w *Wallet, txs []*query.AnnotatedTx) {
+	for _, tx := range txs {
+		for _, l := range tx.Logs {
+			c, err := w.ContractReg.Find(l.Address)
+			if err != nil {
+				continue
+			}
+
+			topics := make([][]byte, 0, len(l.Topics))
+			for _, topic := range l.Topics {
+				topics = append(topics, topic)
+			}
+			if event, err := c.DecodeLog(topics, l.Data); err == nil {
+				l.Event, l.Fields = event.Name, event.Fields
+			}
+		}
+	}
+}
+
 func (w *Wallet) getExternalDefinition(assetID *bc.AssetID) json.RawMessage {
 	definitionByte := w.DB.Get(asset.ExtAssetKey(assetID))
 	if definitionByte == nil {
@@ -193,6 +214,10 @@ func (w *Wallet) buildAnnotatedTransaction(orig *types.Tx, b *types.Block, txSta
 		tx.Outputs = append(tx.Outputs, w.BuildAnnotatedOutput(orig, i))
 	}
 	tx.StatusFail, _ = txStatus.GetStatus(indexInBlock)
+	if result, err := txStatus.GetVerifyResult(indexInBlock); err == nil && len(result.RevertData) > 0 {
+		tx.RevertData = result.RevertData
+		tx.RevertReason, _ = state.UnpackRevert(result.RevertData)
+	}
 	logs, _ := txStatus.GetLogs(indexInBlock)
 	for _, log := range logs {
 		var topics []chainjson.HexBytes
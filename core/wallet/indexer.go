@@ -88,10 +88,11 @@ type Summary struct {
 
 // TxSummary is the struct of transaction summary
 type TxSummary struct {
-	ID        bc.Hash   `json:"tx_id"`
-	Timestamp uint64    `json:"block_time"`
-	Inputs    []Summary `json:"inputs"`
-	Outputs   []Summary `json:"outputs"`
+	ID             bc.Hash                `json:"tx_id"`
+	Timestamp      uint64                 `json:"block_time"`
+	Inputs         []Summary              `json:"inputs"`
+	Outputs        []Summary              `json:"outputs"`
+	TokenTransfers []*query.TokenTransfer `json:"token_transfers,omitempty"`
 }
 
 // indexTransactions saves all annotated transactions to the database.
@@ -99,6 +100,10 @@ func (w *Wallet) indexTransactions(batch db.Batch, b *types.Block, txStatus *bc.
 	annotatedTxs := w.filterAccountTxs(b, txStatus)
 	saveExternalAssetDefinition(b, w.DB)
 	annotateTxsAccount(annotatedTxs, w.DB)
+	for _, tx := range annotatedTxs {
+		tx.TokenTransfers = w.tokenTransfers(tx)
+	}
+	w.attachTokenTransfers(batch, b.Height, annotatedTxs)
 
 	for _, tx := range annotatedTxs {
 		rawTx, err := json.Marshal(tx)
@@ -112,10 +117,29 @@ func (w *Wallet) indexTransactions(batch db.Batch, b *types.Block, txStatus *bc.
 
 		// delete unconfirmed transaction
 		batch.Delete(calcUnconfirmedTxKey(tx.ID.String()))
+		batch.Delete(calcUnconfirmedRawTxKey(tx.ID.String()))
 	}
 	return nil
 }
 
+// indexTxFeeds annotates every transaction of the block and saves the ones
+// matched by the transaction feeds.
+func (w *Wallet) indexTxFeeds(batch db.Batch, b *types.Block, txStatus *bc.TransactionStatus) error {
+	if !w.TxFeedTracker.HasFeeds() {
+		return nil
+	}
+
+	annotatedTxs := make([]*query.AnnotatedTx, 0, len(b.Transactions))
+	for pos, tx := range b.Transactions {
+		annotatedTxs = append(annotatedTxs, w.buildAnnotatedTransaction(tx, b, txStatus, pos))
+	}
+	annotateTxsAccount(annotatedTxs, w.DB)
+	annotateTxsAsset(w, annotatedTxs)
+	annotateTxsContract(w, annotatedTxs)
+
+	return w.TxFeedTracker.IndexTransactions(batch, b.Height, annotatedTxs)
+}
+
 // filterAccountTxs related and build the fully annotated transactions.
 func (w *Wallet) filterAccountTxs(b *types.Block, txStatus *bc.TransactionStatus) []*query.AnnotatedTx {
 	annotatedTxs := make([]*query.AnnotatedTx, 0, len(b.Transactions))
@@ -141,6 +165,13 @@ transactionLoop:
 				continue transactionLoop
 			}
 		}
+
+		if logs, _ := txStatus.GetLogs(pos); len(logs) > 0 {
+			annotatedTx := w.buildAnnotatedTransaction(tx, b, txStatus, pos)
+			if len(w.tokenTransfers(annotatedTx)) > 0 {
+				annotatedTxs = append(annotatedTxs, annotatedTx)
+			}
+		}
 	}
 
 	return annotatedTxs
@@ -159,6 +190,7 @@ func (w *Wallet) GetTransactionByTxID(txID string) (*query.AnnotatedTx, error) {
 		return nil, err
 	}
 	annotateTxsAsset(w, []*query.AnnotatedTx{annotatedTx})
+	annotateTxsContract(w, []*query.AnnotatedTx{annotatedTx})
 
 	return annotatedTx, nil
 }
@@ -171,8 +203,9 @@ func (w *Wallet) GetTransactionsSummary(transactions []*query.AnnotatedTx) []TxS
 		tmpTxSummary := TxSummary{
 			Inputs:    make([]Summary, len(annotatedTx.Inputs)),
 			Outputs:   make([]Summary, len(annotatedTx.Outputs)),
-			ID:        annotatedTx.ID,
-			Timestamp: annotatedTx.Timestamp,
+			ID:             annotatedTx.ID,
+			Timestamp:      annotatedTx.Timestamp,
+			TokenTransfers: annotatedTx.TokenTransfers,
 		}
 
 		for i, input := range annotatedTx.Inputs {
@@ -212,6 +245,12 @@ func findTransactionsByAccount(annotatedTx *query.AnnotatedTx, accountID string)
 		}
 	}
 
+	for _, transfer := range annotatedTx.TokenTransfers {
+		if transfer.FromAccountID == accountID || transfer.ToAccountID == accountID {
+			return true
+		}
+	}
+
 	return false
 }
 
@@ -229,6 +268,7 @@ func (w *Wallet) GetTransactions(accountID string) ([]*query.AnnotatedTx, error)
 
 		if accountID == "" || findTransactionsByAccount(annotatedTx, accountID) {
 			annotateTxsAsset(w, []*query.AnnotatedTx{annotatedTx})
+			annotateTxsContract(w, []*query.AnnotatedTx{annotatedTx})
 			annotatedTxs = append([]*query.AnnotatedTx{annotatedTx}, annotatedTxs...)
 		}
 	}
@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+// RebroadcastPeriod is the period of resubmitting the unconfirmed transactions
+const RebroadcastPeriod = 10 * time.Minute
+
+// TxBroadcaster relays a transaction to the peers, including the ones which
+// are already announced of it
+type TxBroadcaster interface {
+	RebroadcastTx(*types.Tx) error
+}
+
+// SetTxBroadcaster set the broadcaster of the rebroadcasted transactions, the
+// transactions are only resubmitted to the local pool without it
+func (w *Wallet) SetTxBroadcaster(broadcaster TxBroadcaster) {
+	w.broadcaster = broadcaster
+}
+
+func (w *Wallet) getUnconfirmedRawTxs() ([]*types.Tx, error) {
+	txs := []*types.Tx{}
+	txIter := w.DB.IteratorPrefix([]byte(UnconfirmedRawTxPrefix))
+	defer txIter.Release()
+
+	for txIter.Next() {
+		tx := &types.Tx{}
+		if err := tx.UnmarshalText(txIter.Value()); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// RebroadcastTxs relays the unconfirmed transactions in the pool again, the
+// ones dropped from the pool are resubmitted if they are still valid
+func (w *Wallet) RebroadcastTxs() error {
+	txs, err := w.getUnconfirmedRawTxs()
+	if err != nil {
+		return err
+	}
+
+	txPool := w.chain.GetTxPool()
+	for _, tx := range txs {
+		if txPool.IsTransactionInPool(&tx.ID) {
+			if w.broadcaster != nil {
+				if err := w.broadcaster.RebroadcastTx(tx); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		// a new pool transaction is broadcasted by the pool listener
+		acceptable, height, gasStatus, err := w.chain.ValidateTx(tx)
+		if !acceptable {
+			log.WithFields(log.Fields{"tx_id": tx.ID.String(), "err": err}).Debug("skip rebroadcast of invalid tx")
+			continue
+		}
+
+		if _, err := w.chain.ProcessTransaction(tx, err != nil, height, gasStatus.AssetValue); err != nil {
+			log.WithFields(log.Fields{"tx_id": tx.ID.String(), "err": err}).Debug("fail on resubmit unconfirmed tx")
+		}
+	}
+	return nil
+}
+
+// rebroadcastUnconfirmedTxs periodically rebroadcast the unconfirmed txs
+func (w *Wallet) rebroadcastUnconfirmedTxs() {
+	ticker := time.NewTicker(RebroadcastPeriod)
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+		if err := w.RebroadcastTxs(); err != nil {
+			log.WithField("err", err).Error("wallet fail on rebroadcastUnconfirmedTxs")
+		}
+	}
+}
+
+// AbandonTransaction forgets the unconfirmed transaction, it is removed from
+// the local pool and the utxos reserved for it are released. The transaction
+// can still be confirmed if the peers keep it.
+func (w *Wallet) AbandonTransaction(txID string) error {
+	rawTx := w.DB.Get(calcUnconfirmedRawTxKey(txID))
+	if rawTx == nil {
+		return fmt.Errorf("No unconfirmed transaction(tx_id=%s) in wallet", txID)
+	}
+
+	tx := &types.Tx{}
+	if err := tx.UnmarshalText(rawTx); err != nil {
+		return err
+	}
+
+	w.chain.GetTxPool().RemoveTransaction(&tx.ID)
+	w.AccountMgr.RemoveUnconfirmedUtxo(tx.ResultIds)
+
+	spent := []bc.Hash{}
+	for _, input := range tx.Inputs {
+		if outputID, err := input.SpentOutputID(); err == nil {
+			spent = append(spent, outputID)
+		}
+	}
+	w.AccountMgr.ReleaseUtxos(spent)
+
+	batch := w.DB.NewBatch()
+	batch.Delete(calcUnconfirmedTxKey(txID))
+	batch.Delete(calcUnconfirmedRawTxKey(txID))
+	batch.Write()
+	return nil
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/database/leveldb"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+func TestAbandonTransaction(t *testing.T) {
+	dirPath, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+
+	store := leveldb.NewStore(testDB)
+	chain, err := protocol.NewChain(store, protocol.NewTxPool(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountManager := account.NewManager(testDB, chain)
+	hsm, err := pseudohsm.New(dirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := hsm.XCreate("test_pub", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAccount, err := accountManager.Create([]chainkd.XPub{xpub.XPub}, 1, "testAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	controlProg, err := accountManager.CreateAddress(testAccount.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := mockWallet(testDB, accountManager, nil, chain)
+	_, txData, err := mockTxData([]*account.UTXO{mockUTXO(controlProg, consensus.NativeAssetID)}, testAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testTx := types.NewTx(*txData)
+	if err := w.saveUnconfirmedTx(testTx); err != nil {
+		t.Fatal(err)
+	}
+
+	txs, err := w.getUnconfirmedRawTxs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].ID != testTx.ID {
+		t.Fatalf("unconfirmed raw txs %v", txs)
+	}
+
+	if err := w.AbandonTransaction(testTx.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.GetUnconfirmedTxByTxID(testTx.ID.String()); err == nil {
+		t.Error("get the abandoned transaction")
+	}
+	if txs, err := w.getUnconfirmedRawTxs(); err != nil || len(txs) != 0 {
+		t.Errorf("unconfirmed raw txs after abandon %v, %v", txs, err)
+	}
+	if err := w.AbandonTransaction(testTx.ID.String()); err == nil {
+		t.Error("abandon the transaction twice")
+	}
+}
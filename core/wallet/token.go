@@ -0,0 +1,220 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tendermint/tmlibs/db"
+
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/core/contract"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/protocol/vmutil"
+)
+
+const (
+	//TokenBalancePrefix is wallet database token balances prefix
+	TokenBalancePrefix = "TKB:"
+	//TokenTransferPrefix is wallet database token transfers prefix
+	TokenTransferPrefix = "TKT:"
+)
+
+func tokenBalanceKey(accountID string, contract []byte) []byte {
+	return []byte(TokenBalancePrefix + accountID + ":" + hex.EncodeToString(contract))
+}
+
+func tokenTransferKey(blockHeight uint64, position uint32, logIndex uint32) []byte {
+	return []byte(fmt.Sprintf("%s%s%08x", TokenTransferPrefix, formatKey(blockHeight, position), logIndex))
+}
+
+func tokenTransferBlockKey(blockHeight uint64) []byte {
+	return []byte(fmt.Sprintf("%s%016x", TokenTransferPrefix, blockHeight))
+}
+
+// TokenBalance is the balance of a token contract held by an account
+type TokenBalance struct {
+	AccountID  string             `json:"account_id"`
+	Alias      string             `json:"account_alias"`
+	Contract   chainjson.HexBytes `json:"contract"`
+	TokenAlias string             `json:"token_alias,omitempty"`
+	Amount     string             `json:"amount"`
+}
+
+// tokenTransfers decodes the Transfer events of the registered token
+// contracts which move tokens from or to a local account
+func (w *Wallet) tokenTransfers(tx *query.AnnotatedTx) []*query.TokenTransfer {
+	if tx.StatusFail {
+		return nil
+	}
+
+	var transfers []*query.TokenTransfer
+	for i, l := range tx.Logs {
+		c, err := w.ContractReg.Find(l.Address)
+		if err != nil || !c.IsToken() {
+			continue
+		}
+
+		topics := make([][]byte, 0, len(l.Topics))
+		for _, topic := range l.Topics {
+			topics = append(topics, topic)
+		}
+		from, to, amount, ok := contract.DecodeTransfer(topics, l.Data)
+		if !ok {
+			continue
+		}
+
+		transfer := &query.TokenTransfer{
+			Contract:   l.Address,
+			TokenAlias: c.Alias,
+			LogIndex:   uint32(i),
+			From:       from,
+			To:         to,
+			Amount:     amount.String(),
+		}
+		if fromAccount := getAccountFromAddress(from, w.DB); fromAccount != nil {
+			transfer.FromAccountID, transfer.FromAccountAlias = fromAccount.ID, fromAccount.Alias
+		}
+		if toAccount := getAccountFromAddress(to, w.DB); toAccount != nil {
+			transfer.ToAccountID, transfer.ToAccountAlias = toAccount.ID, toAccount.Alias
+		}
+		if transfer.FromAccountID != "" || transfer.ToAccountID != "" {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers
+}
+
+func getAccountFromAddress(address []byte, walletDB db.DB) *account.Account {
+	program, err := vmutil.P2WSHProgram(address)
+	if err != nil {
+		return nil
+	}
+
+	localAccount, err := getAccountFromACP(program, walletDB)
+	if err != nil {
+		return nil
+	}
+	return localAccount
+}
+
+// attachTokenTransfers saves the token transfers of the block and applies
+// them to the token balances, the transfers saved by a former scan of the
+// block are skipped so that a rescan doesn't count them twice
+func (w *Wallet) attachTokenTransfers(batch db.Batch, blockHeight uint64, txs []*query.AnnotatedTx) {
+	deltas := make(map[string]*big.Int)
+	for _, tx := range txs {
+		for _, transfer := range tx.TokenTransfers {
+			key := tokenTransferKey(blockHeight, tx.Position, transfer.LogIndex)
+			if w.DB.Get(key) != nil {
+				continue
+			}
+
+			rawTransfer, err := json.Marshal(transfer)
+			if err != nil {
+				log.WithField("err", err).Error("attachTokenTransfers fail on marshal token transfer")
+				continue
+			}
+			batch.Set(key, rawTransfer)
+			addTokenDelta(deltas, transfer, false)
+		}
+	}
+	w.applyTokenDeltas(batch, deltas)
+}
+
+// detachTokenTransfers deletes the token transfers of the block and reverts
+// them from the token balances
+func (w *Wallet) detachTokenTransfers(batch db.Batch, blockHeight uint64) {
+	deltas := make(map[string]*big.Int)
+	iter := w.DB.IteratorPrefix(tokenTransferBlockKey(blockHeight))
+	defer iter.Release()
+	for iter.Next() {
+		transfer := &query.TokenTransfer{}
+		if err := json.Unmarshal(iter.Value(), transfer); err == nil {
+			addTokenDelta(deltas, transfer, true)
+		}
+		batch.Delete(iter.Key())
+	}
+	w.applyTokenDeltas(batch, deltas)
+}
+
+func addTokenDelta(deltas map[string]*big.Int, transfer *query.TokenTransfer, revert bool) {
+	amount, ok := new(big.Int).SetString(transfer.Amount, 10)
+	if !ok {
+		return
+	}
+	if revert {
+		amount.Neg(amount)
+	}
+
+	add := func(accountID string, amount *big.Int) {
+		key := string(tokenBalanceKey(accountID, transfer.Contract))
+		if _, ok := deltas[key]; !ok {
+			deltas[key] = new(big.Int)
+		}
+		deltas[key].Add(deltas[key], amount)
+	}
+	if transfer.FromAccountID != "" {
+		add(transfer.FromAccountID, new(big.Int).Neg(amount))
+	}
+	if transfer.ToAccountID != "" {
+		add(transfer.ToAccountID, amount)
+	}
+}
+
+func (w *Wallet) applyTokenDeltas(batch db.Batch, deltas map[string]*big.Int) {
+	for key, delta := range deltas {
+		balance := new(big.Int)
+		if rawBalance := w.DB.Get([]byte(key)); rawBalance != nil {
+			balance.SetString(string(rawBalance), 10)
+		}
+
+		balance.Add(balance, delta)
+		if balance.Sign() == 0 {
+			batch.Delete([]byte(key))
+			continue
+		}
+		batch.Set([]byte(key), []byte(balance.String()))
+	}
+}
+
+// GetTokenBalances return the token balances of the account, all the local
+// accounts are returned if the id is empty
+func (w *Wallet) GetTokenBalances(id string) []TokenBalance {
+	prefix := TokenBalancePrefix
+	if id != "" {
+		prefix += id + ":"
+	}
+
+	balances := []TokenBalance{}
+	iter := w.DB.IteratorPrefix([]byte(prefix))
+	defer iter.Release()
+	for iter.Next() {
+		key := string(iter.Key()[len(TokenBalancePrefix):])
+		sep := strings.LastIndex(key, ":")
+		if sep < 0 {
+			continue
+		}
+
+		address, err := hex.DecodeString(key[sep+1:])
+		if err != nil {
+			continue
+		}
+
+		balance := TokenBalance{
+			AccountID: key[:sep],
+			Alias:     w.AccountMgr.GetAliasByID(key[:sep]),
+			Contract:  address,
+			Amount:    string(iter.Value()),
+		}
+		if c, err := w.ContractReg.Find(address); err == nil {
+			balance.TokenAlias = c.Alias
+		}
+		balances = append(balances, balance)
+	}
+	return balances
+}
@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
+	chainjson "github.com/doslink/doslink/basis/encoding/json"
+	"github.com/doslink/doslink/core/account"
+	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/query"
+	"github.com/doslink/doslink/database/leveldb"
+	"github.com/doslink/doslink/protocol"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testTokenABI = `[{"type":"event","name":"Transfer","inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}]`
+
+func mockTransferLog(token, from, to []byte, amount int64) *query.AnnotatedLog {
+	return &query.AnnotatedLog{
+		Address: token,
+		Topics: []chainjson.HexBytes{
+			crypto.Keccak256([]byte("Transfer(address,address,uint256)")),
+			common.LeftPadBytes(from, 32),
+			common.LeftPadBytes(to, 32),
+		},
+		Data: common.LeftPadBytes(big.NewInt(amount).Bytes(), 32),
+	}
+}
+
+func TestTokenTransfers(t *testing.T) {
+	dirPath, err := ioutil.TempDir(".", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dirPath)
+
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	defer os.RemoveAll("temp")
+
+	store := leveldb.NewStore(testDB)
+	chain, err := protocol.NewChain(store, protocol.NewTxPool(store))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountManager := account.NewManager(testDB, chain)
+	hsm, err := pseudohsm.New(dirPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xpub, err := hsm.XCreate("test_pub", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAccount, err := accountManager.Create([]chainkd.XPub{xpub.XPub}, 1, "testAccount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	controlProg, err := accountManager.CreateAddress(testAccount.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := mockWallet(testDB, accountManager, nil, chain)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa").Bytes()
+	if _, err := w.ContractReg.Register(token, "TOKEN", json.RawMessage(testTokenABI)); err != nil {
+		t.Fatal(err)
+	}
+
+	local := common.HexToAddress(controlProg.Address).Bytes()
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb").Bytes()
+	unknown := common.HexToAddress("0x00000000000000000000000000000000000000cc").Bytes()
+	tx := &query.AnnotatedTx{
+		BlockHeight: 1,
+		Position:    1,
+		Logs: []*query.AnnotatedLog{
+			mockTransferLog(token, other, local, 1000),
+			mockTransferLog(token, other, unknown, 50),
+			mockTransferLog(unknown, other, local, 70),
+			mockTransferLog(token, local, other, 400),
+		},
+	}
+
+	tx.TokenTransfers = w.tokenTransfers(tx)
+	if len(tx.TokenTransfers) != 2 || tx.TokenTransfers[0].ToAccountID != testAccount.ID || tx.TokenTransfers[1].FromAccountID != testAccount.ID {
+		t.Fatalf("token transfers %v", tx.TokenTransfers)
+	}
+
+	// the second attach is a rescan of the block and must be skipped
+	for i := 0; i < 2; i++ {
+		batch := testDB.NewBatch()
+		w.attachTokenTransfers(batch, 1, []*query.AnnotatedTx{tx})
+		batch.Write()
+	}
+
+	balances := w.GetTokenBalances(testAccount.ID)
+	if len(balances) != 1 || balances[0].Amount != "600" || balances[0].TokenAlias != "TOKEN" || balances[0].Alias != "testaccount" {
+		t.Fatalf("token balances %v", balances)
+	}
+
+	batch := testDB.NewBatch()
+	w.detachTokenTransfers(batch, 1)
+	batch.Write()
+	if balances := w.GetTokenBalances(""); len(balances) != 0 {
+		t.Fatalf("token balances after detach %v", balances)
+	}
+}
@@ -20,12 +20,18 @@ const (
 	UnconfirmedTxPrefix      = "UTXS:"
 	UnconfirmedTxCheckPeriod = 30 * time.Minute
 	MaxUnconfirmedTxDuration = 24 * time.Hour
+	//UnconfirmedRawTxPrefix is the raw unconfirmed transactions prefix, they are rebroadcasted
+	UnconfirmedRawTxPrefix = "URTX:"
 )
 
 func calcUnconfirmedTxKey(formatKey string) []byte {
 	return []byte(UnconfirmedTxPrefix + formatKey)
 }
 
+func calcUnconfirmedRawTxKey(formatKey string) []byte {
+	return []byte(UnconfirmedRawTxPrefix + formatKey)
+}
+
 // SortByTimestamp implements sort.Interface for AnnotatedTx slices
 type SortByTimestamp []*query.AnnotatedTx
 
@@ -149,7 +155,15 @@ func (w *Wallet) saveUnconfirmedTx(tx *types.Tx) error {
 		return err
 	}
 
-	w.DB.Set(calcUnconfirmedTxKey(tx.ID.String()), rawTx)
+	txData, err := tx.MarshalText()
+	if err != nil {
+		return err
+	}
+
+	batch := w.DB.NewBatch()
+	batch.Set(calcUnconfirmedTxKey(tx.ID.String()), rawTx)
+	batch.Set(calcUnconfirmedRawTxKey(tx.ID.String()), txData)
+	batch.Write()
 	return nil
 }
 
@@ -161,6 +175,7 @@ func (w *Wallet) delExpiredTxs() error {
 	for _, tx := range AnnotatedTx {
 		if time.Now().After(time.Unix(int64(tx.Timestamp), 0).Add(MaxUnconfirmedTxDuration)) {
 			w.DB.Delete(calcUnconfirmedTxKey(tx.ID.String()))
+			w.DB.Delete(calcUnconfirmedRawTxKey(tx.ID.String()))
 		}
 	}
 	return nil
@@ -9,7 +9,9 @@ import (
 
 	"github.com/doslink/doslink/core/account"
 	"github.com/doslink/doslink/core/asset"
+	"github.com/doslink/doslink/core/contract"
 	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/txfeed"
 	"github.com/doslink/doslink/protocol"
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
@@ -32,36 +34,63 @@ type StatusInfo struct {
 
 //Wallet is related to storing account unspent outputs
 type Wallet struct {
-	DB         db.DB
-	rw         sync.RWMutex
-	status     StatusInfo
-	AccountMgr *account.Manager
-	AssetReg   *asset.Registry
-	Hsm        *pseudohsm.HSM
-	chain      *protocol.Chain
-	rescanCh   chan struct{}
+	DB            db.DB
+	rw            sync.RWMutex
+	status        StatusInfo
+	AccountMgr    *account.Manager
+	AssetReg      *asset.Registry
+	TxFeedTracker *txfeed.Tracker
+	ContractReg   *contract.Registry
+	Hsm           pseudohsm.Signer
+	chain         *protocol.Chain
+	rescanCh      chan struct{}
+	broadcaster   TxBroadcaster
 }
 
 //NewWallet return a new wallet instance
-func NewWallet(walletDB db.DB, account *account.Manager, asset *asset.Registry, hsm *pseudohsm.HSM, chain *protocol.Chain) (*Wallet, error) {
-	w := &Wallet{
-		DB:         walletDB,
-		AccountMgr: account,
-		AssetReg:   asset,
-		chain:      chain,
-		Hsm:        hsm,
-		rescanCh:   make(chan struct{}, 1),
+func NewWallet(walletDB db.DB, account *account.Manager, asset *asset.Registry, hsm pseudohsm.Signer, chain *protocol.Chain) (*Wallet, error) {
+	w, err := newWallet(walletDB, account, asset, hsm, chain)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := w.loadWalletInfo(); err != nil {
+	go w.walletUpdater()
+	go w.delUnconfirmedTx()
+	go w.rebroadcastUnconfirmedTxs()
+	return w, nil
+}
+
+// NewLightWallet return a wallet instance of the light mode, which is fed with
+// the merkle blocks of the light mode sync instead of following the chain
+func NewLightWallet(walletDB db.DB, account *account.Manager, asset *asset.Registry, hsm pseudohsm.Signer, chain *protocol.Chain) (*Wallet, error) {
+	w, err := newWallet(walletDB, account, asset, hsm, chain)
+	if err != nil {
 		return nil, err
 	}
 
-	go w.walletUpdater()
+	go w.lightWalletUpdater()
 	go w.delUnconfirmedTx()
 	return w, nil
 }
 
+func newWallet(walletDB db.DB, account *account.Manager, asset *asset.Registry, hsm pseudohsm.Signer, chain *protocol.Chain) (*Wallet, error) {
+	w := &Wallet{
+		DB:            walletDB,
+		AccountMgr:    account,
+		AssetReg:      asset,
+		TxFeedTracker: txfeed.NewTracker(walletDB),
+		ContractReg:   contract.NewRegistry(walletDB),
+		chain:         chain,
+		Hsm:           hsm,
+		rescanCh:      make(chan struct{}, 1),
+	}
+
+	if err := w.loadWalletInfo(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
 //GetWalletInfo return stored wallet info and nil,if error,
 //return initial wallet info and err
 func (w *Wallet) loadWalletInfo() error {
@@ -103,9 +132,32 @@ func (w *Wallet) AttachBlock(block *types.Block) error {
 	if err != nil {
 		return err
 	}
+	return w.attachBlock(block, txStatus)
+}
+
+// AttachMerkleBlock attach a block of the light mode sync, the block holds the
+// proven transactions of the wallet only and the txStatus holds their statuses
+func (w *Wallet) AttachMerkleBlock(block *types.Block, txStatus *bc.TransactionStatus) error {
+	w.rw.Lock()
+	defer w.rw.Unlock()
+
+	if block.PreviousBlockHash != w.status.WorkHash {
+		log.Warn("wallet skip attachMerkleBlock due to status hash not equal to previous hash")
+		return nil
+	}
+	return w.attachBlock(block, txStatus)
+}
+
+func (w *Wallet) attachBlock(block *types.Block, txStatus *bc.TransactionStatus) error {
+	if err := w.extendAddressGap(block); err != nil {
+		return err
+	}
 
 	storeBatch := w.DB.NewBatch()
 	w.indexTransactions(storeBatch, block, txStatus)
+	if err := w.indexTxFeeds(storeBatch, block, txStatus); err != nil {
+		return err
+	}
 	w.attachUtxos(storeBatch, block, txStatus)
 
 	w.status.WorkHeight = block.Height
@@ -131,6 +183,8 @@ func (w *Wallet) DetachBlock(block *types.Block) error {
 	storeBatch := w.DB.NewBatch()
 	w.detachUtxos(storeBatch, block, txStatus)
 	w.deleteTransactions(storeBatch, w.status.BestHeight)
+	w.detachTokenTransfers(storeBatch, block.Height)
+	w.TxFeedTracker.DeleteTransactions(storeBatch, block.Height)
 
 	w.status.BestHeight = block.Height - 1
 	w.status.BestHash = block.PreviousBlockHash
@@ -173,6 +227,14 @@ func (w *Wallet) walletUpdater() {
 	}
 }
 
+// lightWalletUpdater serves the rescan requests of the light mode wallet, the
+// blocks are attached by the light mode sync
+func (w *Wallet) lightWalletUpdater() {
+	for range w.rescanCh {
+		w.setRescanStatus()
+	}
+}
+
 //RescanBlocks provide a trigger to rescan blocks
 func (w *Wallet) RescanBlocks() {
 	select {
@@ -192,6 +254,7 @@ func (w *Wallet) getRescanNotification() {
 }
 
 func (w *Wallet) setRescanStatus() {
+	w.extendAccountsAddressGap()
 	block, _ := w.chain.GetBlockByHeight(0)
 	w.status.WorkHash = bc.Hash{}
 	w.AttachBlock(block)
@@ -212,3 +275,18 @@ func (w *Wallet) GetWalletStatusInfo() StatusInfo {
 
 	return w.status
 }
+
+// ControlPrograms return the control programs of the local accounts, the light
+// mode sync loads them into the filters of the remote peers
+func (w *Wallet) ControlPrograms() ([][]byte, error) {
+	cps, err := w.AccountMgr.ListControlProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	programs := make([][]byte, 0, len(cps))
+	for _, cp := range cps {
+		programs = append(programs, cp.ControlProgram)
+	}
+	return programs, nil
+}
@@ -10,8 +10,10 @@ import (
 
 	"github.com/doslink/doslink/core/account"
 	"github.com/doslink/doslink/core/asset"
+	"github.com/doslink/doslink/core/contract"
 	"github.com/doslink/doslink/core/pseudohsm"
 	"github.com/doslink/doslink/core/txbuilder"
+	"github.com/doslink/doslink/core/txfeed"
 	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/basis/crypto/ed25519/chainkd"
 	"github.com/doslink/doslink/database/leveldb"
@@ -85,11 +87,19 @@ func TestWalletUpdate(t *testing.T) {
 	store.SaveBlock(block, txStatus)
 
 	w := mockWallet(testDB, accountManager, reg, chain)
+	if _, err := w.TxFeedTracker.Create("testFeed", "asset_alias='TESTASSET'"); err != nil {
+		t.Fatal(err)
+	}
+
 	err = w.AttachBlock(block)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if feedTxs, _, err := w.TxFeedTracker.ListTransactions("testFeed", "", 0); err != nil || len(feedTxs) != 1 {
+		t.Fatalf("transaction feed got %d transactions, err %v", len(feedTxs), err)
+	}
+
 	if _, err := w.GetTransactionByTxID(tx.ID.String()); err != nil {
 		t.Fatal(err)
 	}
@@ -138,10 +148,12 @@ func mockTxData(utxos []*account.UTXO, testAccount *account.Account) (*txbuilder
 
 func mockWallet(walletDB dbm.DB, account *account.Manager, asset *asset.Registry, chain *protocol.Chain) *Wallet {
 	wallet := &Wallet{
-		DB:         walletDB,
-		AccountMgr: account,
-		AssetReg:   asset,
-		chain:      chain,
+		DB:            walletDB,
+		AccountMgr:    account,
+		AssetReg:      asset,
+		TxFeedTracker: txfeed.NewTracker(walletDB),
+		ContractReg:   contract.NewRegistry(walletDB),
+		chain:         chain,
 	}
 	return wallet
 }
@@ -0,0 +1,122 @@
+package leveldb
+
+import (
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/tendermint/tmlibs/common"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/state"
+)
+
+// The log index maps every contract address and topic that appears in the
+// logs of a main chain block to the block height and hash. A block joins the
+// index when it is attached to the main chain and leaves it when it is
+// detached, in the batch of the chain status.
+const (
+	logAddressPrefix = "LA:"
+	logTopicPrefix   = "LT:"
+)
+
+var logIndexStoreKey = []byte("logIndexStore")
+
+func loadLogIndexStateJSON(db dbm.DB) *protocol.LogIndexState {
+	bytes := db.Get(logIndexStoreKey)
+	if bytes == nil {
+		return nil
+	}
+	lis := &protocol.LogIndexState{}
+	if err := json.Unmarshal(bytes, lis); err != nil {
+		common.PanicCrisis(common.Fmt("Could not unmarshal bytes: %X", bytes))
+	}
+	return lis
+}
+
+func calcLogIndexPrefix(prefix string, index []byte) []byte {
+	return append([]byte(prefix), index...)
+}
+
+func calcLogIndexKey(prefix string, index []byte, height uint64, blockHash *bc.Hash) []byte {
+	buf := [8]byte{}
+	binary.BigEndian.PutUint64(buf[:], height)
+	key := append(calcLogIndexPrefix(prefix, index), buf[:]...)
+	return append(key, blockHash.Bytes()...)
+}
+
+// logIndexKeys return the index keys of all the logs in the block
+func logIndexKeys(node *state.BlockNode, ts *bc.TransactionStatus) [][]byte {
+	seen := map[string]bool{}
+	keys := [][]byte{}
+	add := func(prefix string, index []byte) {
+		key := calcLogIndexKey(prefix, index, node.Height, &node.Hash)
+		if !seen[string(key)] {
+			seen[string(key)] = true
+			keys = append(keys, key)
+		}
+	}
+
+	for _, result := range ts.VerifyStatus {
+		for _, log := range result.Logs {
+			add(logAddressPrefix, log.Address)
+			for _, topic := range log.Topics {
+				add(logTopicPrefix, topic)
+			}
+		}
+	}
+	return keys
+}
+
+func saveLogIndex(batch dbm.Batch, node *state.BlockNode, ts *bc.TransactionStatus) {
+	for _, key := range logIndexKeys(node, ts) {
+		batch.Set(key, []byte{})
+	}
+}
+
+func deleteLogIndex(batch dbm.Batch, node *state.BlockNode, ts *bc.TransactionStatus) {
+	for _, key := range logIndexKeys(node, ts) {
+		batch.Delete(key)
+	}
+}
+
+// getLogIndexBlocks return the blocks in the height range indexed under any
+// of the given addresses or topics
+func getLogIndexBlocks(db dbm.DB, prefix string, indexes [][]byte, fromHeight, toHeight uint64) map[bc.Hash]bool {
+	blocks := map[bc.Hash]bool{}
+	for _, index := range indexes {
+		indexPrefix := calcLogIndexPrefix(prefix, index)
+		iter := db.IteratorPrefix(indexPrefix)
+		for iter.Next() {
+			key := iter.Key()
+			if len(key) != len(indexPrefix)+8+32 {
+				continue
+			}
+
+			height := binary.BigEndian.Uint64(key[len(indexPrefix):])
+			if height < fromHeight {
+				continue
+			}
+			if height > toHeight {
+				break
+			}
+
+			var blockHash [32]byte
+			copy(blockHash[:], key[len(indexPrefix)+8:])
+			blocks[bc.NewHash(blockHash)] = true
+		}
+		iter.Release()
+	}
+	return blocks
+}
+
+func intersectBlocks(a, b map[bc.Hash]bool) map[bc.Hash]bool {
+	result := map[bc.Hash]bool{}
+	for hash := range a {
+		if b[hash] {
+			result[hash] = true
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package leveldb
+
+import (
+	"os"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/config"
+	"github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/state"
+	"github.com/doslink/doslink/testutil"
+)
+
+func TestLogIndex(t *testing.T) {
+	defer os.RemoveAll("temp")
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	store := NewStore(testDB)
+
+	addrA, addrB := []byte("contract-a"), []byte("contract-b")
+	topicX, topicY := []byte("topic-x"), []byte("topic-y")
+
+	block := config.GenesisBlock()
+	txStatus := bc.NewTransactionStatus()
+	txStatus.SetLogs(0, []*bc.TxLog{
+		{Address: addrA, Topics: [][]byte{topicX}},
+		{Address: addrB, Topics: [][]byte{topicY, topicX}},
+	})
+	if err := store.SaveBlock(block, txStatus); err != nil {
+		t.Fatal(err)
+	}
+	node, err := state.NewBlockNode(&block.BlockHeader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a block on another fork at the same height
+	block.Nonce++
+	forkStatus := bc.NewTransactionStatus()
+	forkStatus.SetLogs(0, []*bc.TxLog{{Address: addrA, Topics: [][]byte{topicY}}})
+	if err := store.SaveBlock(block, forkStatus); err != nil {
+		t.Fatal(err)
+	}
+	forkNode, err := state.NewBlockNode(&block.BlockHeader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the saved blocks are indexed once they join the main chain
+	hashes, err := store.GetLogBlocks([][]byte{addrA}, nil, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameBlocks(hashes, []bc.Hash{}) {
+		t.Errorf("saved blocks indexed: %v", hashes)
+	}
+	if err := store.SaveChainStatus(node, state.NewUtxoViewpoint(), nil, []*state.BlockNode{node}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		addresses [][]byte
+		topics    [][][]byte
+		from, to  uint64
+		want      []bc.Hash
+	}{
+		{addresses: [][]byte{addrA}, to: 10, want: []bc.Hash{node.Hash}},
+		{addresses: [][]byte{addrB}, to: 10, want: []bc.Hash{node.Hash}},
+		{topics: [][][]byte{{topicX}}, to: 10, want: []bc.Hash{node.Hash}},
+		{addresses: [][]byte{addrA}, topics: [][][]byte{{}, {topicY}}, to: 10, want: []bc.Hash{node.Hash}},
+		{addresses: [][]byte{addrB}, topics: [][][]byte{{topicX, topicY}}, to: 10, want: []bc.Hash{node.Hash}},
+		{addresses: [][]byte{addrA}, from: 1, to: 10, want: []bc.Hash{}},
+		{addresses: [][]byte{[]byte("unknown")}, to: 10, want: []bc.Hash{}},
+	}
+
+	for i, c := range cases {
+		hashes, err := store.GetLogBlocks(c.addresses, c.topics, c.from, c.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameBlocks(hashes, c.want) {
+			t.Errorf("case %d: got %v, want %v", i, hashes, c.want)
+		}
+	}
+
+	// reorganize to the fork
+	if err := store.SaveChainStatus(forkNode, state.NewUtxoViewpoint(), []*state.BlockNode{node}, []*state.BlockNode{forkNode}); err != nil {
+		t.Fatal(err)
+	}
+	if hashes, err = store.GetLogBlocks([][]byte{addrA}, nil, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !sameBlocks(hashes, []bc.Hash{forkNode.Hash}) {
+		t.Errorf("got %v after reorganize, want %v", hashes, forkNode.Hash)
+	}
+	if hashes, err = store.GetLogBlocks([][]byte{addrB}, nil, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !sameBlocks(hashes, []bc.Hash{}) {
+		t.Errorf("detached block still indexed: %v", hashes)
+	}
+}
+
+func TestBackfillLogIndex(t *testing.T) {
+	defer os.RemoveAll("temp")
+	testDB := dbm.NewDB("testdb", "leveldb", "temp")
+	store := NewStore(testDB)
+
+	block := config.GenesisBlock()
+	txStatus := bc.NewTransactionStatus()
+	txStatus.SetLogs(0, []*bc.TxLog{{Address: []byte("contract-a")}})
+	if err := store.SaveBlock(block, txStatus); err != nil {
+		t.Fatal(err)
+	}
+	node, err := state.NewBlockNode(&block.BlockHeader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lis := store.GetLogIndexState(); lis != nil {
+		t.Fatalf("got log index state %v before the backfill", lis)
+	}
+	want := &protocol.LogIndexState{Height: 1, Done: true}
+	if err := store.BackfillLogIndex([]*state.BlockNode{node}, want); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.GetLogIndexState(); !testutil.DeepEqual(got, want) {
+		t.Errorf("got log index state %v, want %v", got, want)
+	}
+
+	hashes, err := store.GetLogBlocks([][]byte{[]byte("contract-a")}, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameBlocks(hashes, []bc.Hash{node.Hash}) {
+		t.Errorf("got %v, want %v", hashes, node.Hash)
+	}
+}
+
+func sameBlocks(got []*bc.Hash, want []bc.Hash) bool {
+	if len(got) != len(want) {
+		return false
+	}
+
+	for _, w := range want {
+		found := false
+		for _, g := range got {
+			if *g == w {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -22,6 +22,7 @@ var (
 	blockPrefix       = []byte("B:")
 	blockHeaderPrefix = []byte("BH:")
 	txStatusPrefix    = []byte("BTS:")
+	txLocationPrefix  = []byte("TXL:")
 )
 
 func loadBlockStoreStateJSON(db dbm.DB) *protocol.BlockStoreState {
@@ -59,6 +60,16 @@ func calcTxStatusKey(hash *bc.Hash) []byte {
 	return append(txStatusPrefix, hash.Bytes()...)
 }
 
+func calcTxLocationPrefix(txID *bc.Hash) []byte {
+	return append(txLocationPrefix, txID.Bytes()...)
+}
+
+// calcTxLocationKey keys the location by both tx and block hash, so that a tx
+// included by blocks on different forks keeps one location per block.
+func calcTxLocationKey(txID, blockHash *bc.Hash) []byte {
+	return append(calcTxLocationPrefix(txID), blockHash.Bytes()...)
+}
+
 // GetBlock return the block by given hash
 func GetBlock(db dbm.DB, hash *bc.Hash) *types.Block {
 	bytez := db.Get(calcBlockKey(hash))
@@ -117,6 +128,87 @@ func (s *Store) GetTransactionStatus(hash *bc.Hash) (*bc.TransactionStatus, erro
 	return ts, nil
 }
 
+// GetTransactionLocations return every stored block that includes the tx
+func (s *Store) GetTransactionLocations(txID *bc.Hash) ([]*protocol.TxLocation, error) {
+	prefix := calcTxLocationPrefix(txID)
+	iter := s.db.IteratorPrefix(prefix)
+	defer iter.Release()
+
+	locations := []*protocol.TxLocation{}
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if len(key) != len(prefix)+32 || len(value) != 4 {
+			return nil, errors.New("malformed transaction location")
+		}
+
+		var blockHash [32]byte
+		copy(blockHash[:], key[len(prefix):])
+		locations = append(locations, &protocol.TxLocation{
+			BlockHash: bc.NewHash(blockHash),
+			Position:  binary.BigEndian.Uint32(value),
+		})
+	}
+	return locations, nil
+}
+
+// GetLogBlocks return the main chain blocks in the height range that contain
+// logs emitted by any of the addresses, and for every non empty topic
+// position, logs with any of the position's topics. The index does not keep
+// the topic positions, so the logs of the returned blocks must be matched
+// again by the caller.
+func (s *Store) GetLogBlocks(addresses [][]byte, topics [][][]byte, fromHeight, toHeight uint64) ([]*bc.Hash, error) {
+	var blocks map[bc.Hash]bool
+	if len(addresses) > 0 {
+		blocks = getLogIndexBlocks(s.db, logAddressPrefix, addresses, fromHeight, toHeight)
+	}
+	for _, alternatives := range topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+
+		topicBlocks := getLogIndexBlocks(s.db, logTopicPrefix, alternatives, fromHeight, toHeight)
+		if blocks == nil {
+			blocks = topicBlocks
+		} else {
+			blocks = intersectBlocks(blocks, topicBlocks)
+		}
+	}
+
+	hashes := []*bc.Hash{}
+	for hash := range blocks {
+		hash := hash
+		hashes = append(hashes, &hash)
+	}
+	return hashes, nil
+}
+
+// GetLogIndexState return how far the log index has been backfilled
+func (s *Store) GetLogIndexState() *protocol.LogIndexState {
+	return loadLogIndexStateJSON(s.db)
+}
+
+// BackfillLogIndex add the logs of the main chain blocks saved before the log
+// index existed, together with the backfill progress
+func (s *Store) BackfillLogIndex(nodes []*state.BlockNode, lis *protocol.LogIndexState) error {
+	batch := s.db.NewBatch()
+	for _, node := range nodes {
+		ts, err := s.GetTransactionStatus(&node.Hash)
+		if err != nil {
+			return err
+		}
+		saveLogIndex(batch, node, ts)
+	}
+
+	bytes, err := json.Marshal(lis)
+	if err != nil {
+		return err
+	}
+
+	batch.Set(logIndexStoreKey, bytes)
+	batch.Write()
+	return nil
+}
+
 // GetStoreStatus return the BlockStoreStateJSON
 func (s *Store) GetStoreStatus() *protocol.BlockStoreState {
 	return loadBlockStoreStateJSON(s.db)
@@ -175,19 +267,41 @@ func (s *Store) SaveBlock(block *types.Block, ts *bc.TransactionStatus) error {
 	batch.Set(calcBlockKey(&blockHash), binaryBlock)
 	batch.Set(calcBlockHeaderKey(block.Height, &blockHash), binaryBlockHeader)
 	batch.Set(calcTxStatusKey(&blockHash), binaryTxStatus)
+	for i, tx := range block.Transactions {
+		position := [4]byte{}
+		binary.BigEndian.PutUint32(position[:], uint32(i))
+		batch.Set(calcTxLocationKey(&tx.ID, &blockHash), position[:])
+	}
 	batch.Write()
 
 	log.WithFields(log.Fields{"height": block.Height, "hash": blockHash.String()}).Info("block saved on disk")
 	return nil
 }
 
-// SaveChainStatus save the core's newest status && delete old status
-func (s *Store) SaveChainStatus(node *state.BlockNode, view *state.UtxoViewpoint) error {
+// SaveChainStatus save the core's newest status && delete old status, the
+// logs of the detached blocks leave the log index and the logs of the
+// attached blocks join it
+func (s *Store) SaveChainStatus(node *state.BlockNode, view *state.UtxoViewpoint, detachNodes, attachNodes []*state.BlockNode) error {
 	batch := s.db.NewBatch()
 	if err := saveUtxoView(batch, view); err != nil {
 		return err
 	}
 
+	for _, detachNode := range detachNodes {
+		ts, err := s.GetTransactionStatus(&detachNode.Hash)
+		if err != nil {
+			return err
+		}
+		deleteLogIndex(batch, detachNode, ts)
+	}
+	for _, attachNode := range attachNodes {
+		ts, err := s.GetTransactionStatus(&attachNode.Hash)
+		if err != nil {
+			return err
+		}
+		saveLogIndex(batch, attachNode, ts)
+	}
+
 	bytes, err := json.Marshal(protocol.BlockStoreState{Height: node.Height, Hash: &node.Hash})
 	if err != nil {
 		return err
@@ -18,6 +18,7 @@ import (
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/state"
 	"github.com/doslink/doslink/protocol/validation"
+	vmstate "github.com/doslink/doslink/protocol/vm/state"
 	"github.com/doslink/doslink/protocol/vmutil"
 )
 
@@ -68,6 +69,43 @@ func createCoinbaseTx(accountManager *account.Manager, amount uint64, blockHeigh
 	return tx, nil
 }
 
+// senderNonce identifies the contract transaction of a sender in the pool
+type senderNonce struct {
+	sender string
+	nonce  uint64
+}
+
+// unpackedParent return the pool transaction the transaction waits for, the
+// one it spends the output of or the contract transaction of the previous
+// nonce of the same sender, nil if all of them are packed
+func unpackedParent(txDesc *protocol.TxDesc, poolOutputs map[bc.Hash]*protocol.TxDesc, poolNonces map[senderNonce]*protocol.TxDesc, packed map[bc.Hash]bool) *protocol.TxDesc {
+	for _, spent := range txDesc.Tx.SpentOutputIDs {
+		if parent, ok := poolOutputs[spent]; ok && !packed[parent.Tx.ID] {
+			return parent
+		}
+	}
+	if sender, nonce, ok := txDesc.SenderNonce(); ok && nonce > 0 {
+		if parent, ok := poolNonces[senderNonce{sender: sender, nonce: nonce - 1}]; ok && !packed[parent.Tx.ID] {
+			return parent
+		}
+	}
+	return nil
+}
+
+// contextBlock returns the block seen by the contracts of the template, it
+// carries the header fields and the coinbase of the block being mined
+func contextBlock(header *types.BlockHeader, coinbaseTx *types.Tx) *bc.Block {
+	return &bc.Block{
+		BlockHeader: &bc.BlockHeader{
+			Height:          header.Height,
+			Timestamp:       header.Timestamp,
+			Bits:            header.Bits,
+			PreviousBlockId: &header.PreviousBlockHash,
+		},
+		Transactions: []*bc.Tx{coinbaseTx.Tx},
+	}
+}
+
 // NewBlockTemplate returns a new block template that is ready to be solved
 func NewBlockTemplate(c *protocol.Chain, txPool *protocol.TxPool, accountManager *account.Manager) (b *types.Block, err error) {
 	view := state.NewUtxoViewpoint()
@@ -102,20 +140,54 @@ func NewBlockTemplate(c *protocol.Chain, txPool *protocol.TxPool, accountManager
 			Bits:              nextBits,
 		},
 	}
-	bcBlock := &bc.Block{BlockHeader: &bc.BlockHeader{Height: nextBlockHeight}}
 	b.Transactions = []*types.Tx{nil}
 
+	// the contracts see the miner of the block before the fees are known, the
+	// coinbase pays the same program once it is rebuilt with the fees
+	coinbaseTx, err := createCoinbaseTx(accountManager, 0, nextBlockHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "fail on createCoinbaseTx")
+	}
+	bcBlock := contextBlock(&b.BlockHeader, coinbaseTx)
+
 	stateDB, err := protocol.NewState(&preBlockHeader.StateRoot, c)
 	if err != nil {
 		return nil, err
 	}
 
+	gasPool := validation.NewBlockGasPool()
+	chain := validation.NewUtxoViewChain(c, view)
 	txs := txPool.GetTransactions()
-	sort.Sort(byTime(txs))
+	sort.Sort(byFeeRate(txs))
+
+	// a transaction spending the outputs of a pool transaction, or a contract
+	// transaction following a pool one of its sender, waits until its parent
+	// is packed, then it is picked before the rest of the queue. So the
+	// contract transactions of a sender are packed by nonce.
+	poolOutputs := make(map[bc.Hash]*protocol.TxDesc)
+	poolNonces := make(map[senderNonce]*protocol.TxDesc)
 	for _, txDesc := range txs {
+		for _, id := range txDesc.Tx.ResultIds {
+			poolOutputs[*id] = txDesc
+		}
+		if sender, nonce, ok := txDesc.SenderNonce(); ok {
+			poolNonces[senderNonce{sender: sender, nonce: nonce}] = txDesc
+		}
+	}
+	packed := make(map[bc.Hash]bool)
+	waiting := make(map[bc.Hash][]*protocol.TxDesc)
+
+	for queue := txs; len(queue) > 0; {
+		txDesc := queue[0]
+		queue = queue[1:]
 		tx := txDesc.Tx.Tx
 		gasOnlyTx := false
 
+		if parent := unpackedParent(txDesc, poolOutputs, poolNonces, packed); parent != nil {
+			waiting[parent.Tx.ID] = append(waiting[parent.Tx.ID], txDesc)
+			continue
+		}
+
 		if err := c.GetTransactionsUtxo(view, []*bc.Tx{tx}); err != nil {
 			log.WithField("error", err).Error("mining block generate skip tx due to")
 			txPool.RemoveTransaction(&tx.ID)
@@ -124,8 +196,14 @@ func NewBlockTemplate(c *protocol.Chain, txPool *protocol.TxPool, accountManager
 
 		revision := stateDB.Snapshot()
 		stateDB.Prepare(tx.ID.Byte32(), [32]byte{}, len(b.Transactions))
-		vs, err := validation.ValidateTx(tx, bcBlock, c, stateDB)
+		vs, err := validation.ValidateBlockTx(tx, bcBlock, chain, stateDB, gasPool)
 		gasStatus := vs.GasState()
+		if errors.Root(err) == vmstate.ErrGasLimitReached {
+			// the tx waits for the next block when the EVM gas of this block
+			// runs out
+			stateDB.RevertToSnapshot(revision)
+			continue
+		}
 		if err != nil {
 			if !gasStatus.GasValid {
 				log.WithField("error", err).Error("mining block generate skip tx due to")
@@ -152,28 +230,19 @@ func NewBlockTemplate(c *protocol.Chain, txPool *protocol.TxPool, accountManager
 		}
 		stateDB.Finalise(true)
 
-		var txLogs []*bc.TxLog
-		for _, log := range stateDB.GetLogs(tx.ID.Byte32()) {
-			var topics [][]byte
-			for _, topic := range log.Topics {
-				topics = append(topics, topic.Bytes())
-			}
-			txLogs = append(txLogs,
-				&bc.TxLog{
-					Address: log.Address.Bytes(),
-					Topics:  topics,
-					Data:    log.Data,
-				},
-			)
+		if err := validation.SetTxReceipt(txStatus, len(b.Transactions), tx, stateDB, gasOnlyTx, uint64(gasStatus.GasUsed), gasUsed+uint64(gasStatus.GasUsed), vs); err != nil {
+			return nil, err
 		}
 
-		txStatus.SetLogs(len(b.Transactions), txLogs)
-		txStatus.SetStatus(len(b.Transactions), gasOnlyTx)
 		b.Transactions = append(b.Transactions, txDesc.Tx)
 		txEntries = append(txEntries, tx)
 		gasUsed += uint64(gasStatus.GasUsed)
 		txFee += txDesc.Fee
 
+		packed[tx.ID] = true
+		queue = append(append([]*protocol.TxDesc{}, waiting[tx.ID]...), queue...)
+		delete(waiting, tx.ID)
+
 		if gasUsed == consensus.MaxBlockGas {
 			break
 		}
@@ -187,8 +256,8 @@ func NewBlockTemplate(c *protocol.Chain, txPool *protocol.TxPool, accountManager
 	txEntries[0] = b.Transactions[0].Tx
 
 	if config.SupportBalanceInStateDB {
-		bcBlock.Transactions = append(bcBlock.Transactions, b.Transactions[0].Tx)
-		_, err = validation.ValidateTx(b.Transactions[0].Tx, bcBlock, c, stateDB)
+		bcBlock.Transactions[0] = b.Transactions[0].Tx
+		_, err = validation.ValidateBlockTx(b.Transactions[0].Tx, bcBlock, chain, stateDB, gasPool)
 		if err != nil {
 			return nil, errors.Wrap(err, "fail on validate CoinbaseTx")
 		}
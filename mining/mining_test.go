@@ -4,8 +4,13 @@ import (
 	"testing"
 	"fmt"
 	"encoding/json"
+	"sort"
+
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol"
 	"github.com/doslink/doslink/protocol/validation"
 	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
 )
 
 func TestCreateCoinbaseTx(t *testing.T) {
@@ -62,3 +67,67 @@ func TestCreateCoinbaseTx(t *testing.T) {
 		}
 	}
 }
+
+func TestContextBlock(t *testing.T) {
+	header := &types.BlockHeader{
+		Version:           1,
+		Height:            5,
+		PreviousBlockHash: bc.Hash{V0: 4},
+		Timestamp:         1523352600,
+		Bits:              2305843009214532812,
+	}
+	coinbaseTx, err := createCoinbaseTx(nil, 0, header.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := contextBlock(header, coinbaseTx)
+	if block.Height != header.Height || block.Timestamp != header.Timestamp || block.Bits != header.Bits {
+		t.Errorf("got block header %v, want height %d timestamp %d bits %d", block.BlockHeader, header.Height, header.Timestamp, header.Bits)
+	}
+	if block.PreviousBlockId == nil || *block.PreviousBlockId != header.PreviousBlockHash {
+		t.Errorf("got previous block id %v, want %v", block.PreviousBlockId, header.PreviousBlockHash)
+	}
+	if len(block.Transactions) != 1 || block.Transactions[0].ID != coinbaseTx.ID {
+		t.Errorf("got block transactions %v, want the coinbase %v", block.Transactions, coinbaseTx.ID)
+	}
+}
+
+func mockCallTx(program byte, nonce uint64, fee uint64) *protocol.TxDesc {
+	tx := types.NewTx(types.TxData{
+		Inputs: []*types.TxInput{
+			types.NewCallInput([]byte{program}, nonce, []byte{0x01}, nil, nil),
+			types.NewSpendInput(nil, bc.NewHash([32]byte{program, byte(nonce)}), *consensus.NativeAssetID, fee, 0, []byte{program}),
+		},
+		Outputs: []*types.TxOutput{types.NewTxOutput(*consensus.NativeAssetID, 1, []byte{program})},
+	})
+	return &protocol.TxDesc{Tx: tx, Weight: 100, Fee: fee}
+}
+
+func TestUnpackedParentNonce(t *testing.T) {
+	first, second, other := mockCallTx(0x51, 0, 100), mockCallTx(0x51, 1, 300), mockCallTx(0x52, 1, 200)
+	txs := []*protocol.TxDesc{first, second, other}
+	sort.Sort(byFeeRate(txs))
+	if txs[0] != second {
+		t.Fatalf("got the first tx by fee rate %v, want the higher nonce %v", txs[0].Tx.ID, second.Tx.ID)
+	}
+
+	poolNonces := make(map[senderNonce]*protocol.TxDesc)
+	for _, txDesc := range txs {
+		sender, nonce, _ := txDesc.SenderNonce()
+		poolNonces[senderNonce{sender: sender, nonce: nonce}] = txDesc
+	}
+
+	packed := make(map[bc.Hash]bool)
+	if parent := unpackedParent(second, nil, poolNonces, packed); parent != first {
+		t.Errorf("got parent %v, want the lower nonce %v", parent, first.Tx.ID)
+	}
+	if parent := unpackedParent(other, nil, poolNonces, packed); parent != nil {
+		t.Errorf("got parent %v of the tx without the lower nonce in the pool", parent.Tx.ID)
+	}
+
+	packed[first.Tx.ID] = true
+	if parent := unpackedParent(second, nil, poolNonces, packed); parent != nil {
+		t.Errorf("got parent %v after the lower nonce is packed", parent.Tx.ID)
+	}
+}
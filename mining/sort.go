@@ -2,8 +2,15 @@ package mining
 
 import "github.com/doslink/doslink/protocol"
 
-type byTime []*protocol.TxDesc
+// byFeeRate sorts the transactions by the fee rate in descending order, the
+// earlier added one goes first for the same fee rate
+type byFeeRate []*protocol.TxDesc
 
-func (a byTime) Len() int           { return len(a) }
-func (a byTime) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a byTime) Less(i, j int) bool { return a[i].Added.Unix() < a[j].Added.Unix() }
+func (a byFeeRate) Len() int      { return len(a) }
+func (a byFeeRate) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byFeeRate) Less(i, j int) bool {
+	if cmp := a[i].CmpFeeRate(a[j]); cmp != 0 {
+		return cmp > 0
+	}
+	return a[i].Added.Before(a[j].Added)
+}
@@ -22,6 +22,8 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 	//ErrNoToken is returned when authenticate is called with no token.
 	ErrNoToken = errors.New("no token")
+	//ErrExpiredToken is returned when authenticate is called with expired token.
+	ErrExpiredToken = errors.New("expired token")
 )
 
 //API describe the token authenticate.
@@ -35,6 +37,7 @@ type API struct {
 }
 
 type tokenResult struct {
+	token      *accesstoken.Token
 	lastLookup time.Time
 }
 
@@ -51,10 +54,11 @@ func NewAPI(tokens *accesstoken.CredentialStore) *API {
 func (a *API) Authenticate(req *http.Request) (*http.Request, error) {
 	ctx := req.Context()
 
-	token, err := a.tokenAuthn(req)
+	token, accessToken, err := a.tokenAuthn(req)
 	if err == nil && token != "" {
 		// if this request was successfully authenticated with a token, pass the token along
 		ctx = newContextWithToken(ctx, token)
+		ctx = newContextWithAccessToken(ctx, accessToken)
 	}
 	local := a.localhostAuthn(req)
 	if local {
@@ -127,27 +131,40 @@ func (a *API) localhostAuthn(req *http.Request) bool {
 	return true
 }
 
-func (a *API) tokenAuthn(req *http.Request) (string, error) {
+func (a *API) tokenAuthn(req *http.Request) (string, *accesstoken.Token, error) {
 	user, pw, ok := req.BasicAuth()
 	if !ok {
-		return "", ErrNoToken
+		return "", nil, ErrNoToken
 	}
-	return user, a.cachedTokenAuthnCheck(req.Context(), user, pw)
+	token, err := a.cachedTokenAuthnCheck(req.Context(), user, pw)
+	return user, token, err
 }
 
-func (a *API) cachedTokenAuthnCheck(ctx context.Context, user, pw string) error {
+// cachedTokenAuthnCheck looks the token up at most once per tokenExpiry, the
+// last used time of the token is recorded by the lookups
+func (a *API) cachedTokenAuthnCheck(ctx context.Context, user, pw string) (*accesstoken.Token, error) {
 	a.tokenMu.Lock()
 	res, ok := a.tokenMap[user+pw]
 	a.tokenMu.Unlock()
-	if !ok || time.Now().After(res.lastLookup.Add(tokenExpiry)) {
-		err := a.tokens.Check(user, pw)
-		if err != nil {
-			return ErrInvalidToken
+	now := time.Now()
+	if !ok || now.After(res.lastLookup.Add(tokenExpiry)) {
+		token, err := a.tokens.Check(user, pw)
+		if errors.Root(err) == accesstoken.ErrExpiredToken {
+			return nil, ErrExpiredToken
+		} else if err != nil {
+			return nil, ErrInvalidToken
 		}
-		res = tokenResult{lastLookup: time.Now()}
+		if err := a.tokens.MarkUsed(user, now); err != nil {
+			return nil, err
+		}
+		res = tokenResult{token: token, lastLookup: now}
 		a.tokenMu.Lock()
 		a.tokenMap[user+pw] = res
 		a.tokenMu.Unlock()
 	}
-	return nil
+
+	if res.token.Expired(now) {
+		return nil, ErrExpiredToken
+	}
+	return res.token, nil
 }
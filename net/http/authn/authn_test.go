@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	dbm "github.com/tendermint/tmlibs/db"
 
@@ -16,7 +17,12 @@ func TestAuthenticate(t *testing.T) {
 	tokenDB := dbm.NewDB("testdb", "leveldb", "temp")
 	defer os.RemoveAll("temp")
 	tokenStore := accesstoken.NewStore(tokenDB)
-	token, err := tokenStore.Create("alice", "test")
+	token, err := tokenStore.Create("alice", "test", nil)
+	if err != nil {
+		t.Errorf("create token error")
+	}
+	expiresAt := time.Now().Add(-time.Minute)
+	expired, err := tokenStore.Create("bob", "test", &accesstoken.Policy{ExpiresAt: &expiresAt})
 	if err != nil {
 		t.Errorf("create token error")
 	}
@@ -27,6 +33,7 @@ func TestAuthenticate(t *testing.T) {
 	}{
 		{"alice", token.Token, nil},
 		{"alice", "alice:abcsdsdfassdfsefsfsfesfesfefsefa", ErrInvalidToken},
+		{"bob", expired.Token, ErrExpiredToken},
 	}
 
 	api := NewAPI(tokenStore)
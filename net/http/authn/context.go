@@ -3,12 +3,15 @@ package authn
 import (
 	"context"
 	"crypto/x509"
+
+	"github.com/doslink/doslink/core/accesstoken"
 )
 
 type key int
 
 const (
 	tokenKey key = iota
+	accessTokenKey
 	localhostKey
 	x509CertsKey
 )
@@ -33,6 +36,18 @@ func Token(ctx context.Context) string {
 	return t
 }
 
+// newContextWithAccessToken sets the access token in a new context and returns
+// the context.
+func newContextWithAccessToken(ctx context.Context, token *accesstoken.Token) context.Context {
+	return context.WithValue(ctx, accessTokenKey, token)
+}
+
+// AccessToken returns the access token stored in the context, if there is one.
+func AccessToken(ctx context.Context) *accesstoken.Token {
+	t, _ := ctx.Value(accessTokenKey).(*accesstoken.Token)
+	return t
+}
+
 // newContextWithLocalhost sets the localhost flag to `true` in a new context
 // and returns that context.
 func newContextWithLocalhost(ctx context.Context) context.Context {
@@ -13,10 +13,12 @@ import (
 )
 
 const (
-	syncCycle            = 5 * time.Second
-	blockProcessChSize   = 1024
-	blocksProcessChSize  = 128
-	headersProcessChSize = 1024
+	syncCycle               = 5 * time.Second
+	blockProcessChSize      = 1024
+	blocksProcessChSize     = 128
+	headersProcessChSize    = 1024
+	txStatusesProcessChSize = 128
+	stateNodesProcessChSize = 128
 )
 
 var (
@@ -45,29 +47,46 @@ type headersMsg struct {
 	peerID  string
 }
 
+type txStatusesMsg struct {
+	txStatuses []*bc.TransactionStatus
+	peerID     string
+}
+
+type stateNodesMsg struct {
+	nodes  [][]byte
+	peerID string
+}
+
 type blockKeeper struct {
 	chain Chain
 	peers *peerSet
 
-	syncPeer         *peer
-	blockProcessCh   chan *blockMsg
-	blocksProcessCh  chan *blocksMsg
-	headersProcessCh chan *headersMsg
+	syncPeer            *peer
+	blockProcessCh      chan *blockMsg
+	blocksProcessCh     chan *blocksMsg
+	headersProcessCh    chan *headersMsg
+	txStatusesProcessCh chan *txStatusesMsg
+	stateNodesProcessCh chan *stateNodesMsg
 
 	headerList *list.List
+	// forceStateSync allows a node with the state to sync the state of a
+	// checkpoint when it is far behind, a new node always does
+	forceStateSync bool
 }
 
-func newBlockKeeper(chain Chain, peers *peerSet) *blockKeeper {
+func newBlockKeeper(chain Chain, peers *peerSet, forceStateSync bool) *blockKeeper {
 	bk := &blockKeeper{
-		chain:            chain,
-		peers:            peers,
-		blockProcessCh:   make(chan *blockMsg, blockProcessChSize),
-		blocksProcessCh:  make(chan *blocksMsg, blocksProcessChSize),
-		headersProcessCh: make(chan *headersMsg, headersProcessChSize),
-		headerList:       list.New(),
+		chain:               chain,
+		peers:               peers,
+		forceStateSync:      forceStateSync,
+		blockProcessCh:      make(chan *blockMsg, blockProcessChSize),
+		blocksProcessCh:     make(chan *blocksMsg, blocksProcessChSize),
+		headersProcessCh:    make(chan *headersMsg, headersProcessChSize),
+		txStatusesProcessCh: make(chan *txStatusesMsg, txStatusesProcessChSize),
+		stateNodesProcessCh: make(chan *stateNodesMsg, stateNodesProcessChSize),
+		headerList:          list.New(),
 	}
 	bk.resetHeaderState()
-	go bk.syncWorker()
 	return bk
 }
 
@@ -112,7 +131,10 @@ func (bk *blockKeeper) blockLocator() []*bc.Hash {
 	return locator
 }
 
-func (bk *blockKeeper) fastBlockSync(checkPoint *consensus.Checkpoint) error {
+// checkpointHeaders downloads the headers from the best block to the
+// checkpoint and validates them, the blocks of the checkpoint branch are
+// matched against the header list afterwards
+func (bk *blockKeeper) checkpointHeaders(checkPoint *consensus.Checkpoint) error {
 	bk.resetHeaderState()
 	lastHeader := bk.headerList.Back().Value.(*types.BlockHeader)
 	for ; lastHeader.Hash() != checkPoint.Hash; lastHeader = bk.headerList.Back().Value.(*types.BlockHeader) {
@@ -135,6 +157,14 @@ func (bk *blockKeeper) fastBlockSync(checkPoint *consensus.Checkpoint) error {
 		}
 	}
 
+	return bk.validateHeaderList()
+}
+
+func (bk *blockKeeper) fastBlockSync(checkPoint *consensus.Checkpoint) error {
+	if err := bk.checkpointHeaders(checkPoint); err != nil {
+		return err
+	}
+
 	fastHeader := bk.headerList.Front()
 	for bk.chain.BestBlockHeight() < checkPoint.Height {
 		locator := bk.blockLocator()
@@ -352,6 +382,18 @@ func (bk *blockKeeper) resetHeaderState() {
 }
 
 func (bk *blockKeeper) startSync() bool {
+	if peer := bk.peers.bestPeer(consensus.SFFullNode | consensus.SFStateSync); peer != nil {
+		if pivot := bk.statePivot(peer.Height()); pivot != nil {
+			bk.syncPeer = peer
+			if err := bk.stateSync(pivot); err != nil {
+				log.WithField("err", err).Warning("fail on stateSync")
+				bk.peers.errorHandler(peer.ID(), err)
+				return false
+			}
+			return true
+		}
+	}
+
 	checkPoint := bk.nextCheckpoint()
 	peer := bk.peers.bestPeer(consensus.SFFastSync | consensus.SFFullNode)
 	if peer != nil && checkPoint != nil && peer.Height() >= checkPoint.Height {
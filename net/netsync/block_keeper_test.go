@@ -391,6 +391,72 @@ func TestNextCheckpoint(t *testing.T) {
 	}
 }
 
+func TestStatePivot(t *testing.T) {
+	checkPoints := []consensus.Checkpoint{
+		{10000, bc.Hash{V0: 1}},
+		{20000, bc.Hash{V0: 2}},
+		{30000, bc.Hash{V0: 3}},
+	}
+	cases := []struct {
+		bestHeight     uint64
+		peerHeight     uint64
+		forceStateSync bool
+		want           *consensus.Checkpoint
+	}{
+		{
+			bestHeight: 0,
+			peerHeight: 5000,
+			want:       nil,
+		},
+		{
+			bestHeight: 0,
+			peerHeight: 25000,
+			want:       &consensus.Checkpoint{20000, bc.Hash{V0: 2}},
+		},
+		{
+			bestHeight: 0,
+			peerHeight: 30000 + statePivotDistance - 1,
+			want:       &consensus.Checkpoint{20000, bc.Hash{V0: 2}},
+		},
+		{
+			bestHeight: 0,
+			peerHeight: 30000 + statePivotDistance,
+			want:       &consensus.Checkpoint{30000, bc.Hash{V0: 3}},
+		},
+		{
+			bestHeight: 5000,
+			peerHeight: 35000,
+			want:       nil,
+		},
+		{
+			bestHeight:     5000,
+			peerHeight:     35000,
+			forceStateSync: true,
+			want:           &consensus.Checkpoint{30000, bc.Hash{V0: 3}},
+		},
+		{
+			bestHeight:     29500,
+			peerHeight:     35000,
+			forceStateSync: true,
+			want:           nil,
+		},
+	}
+
+	defer func(checkPoints []consensus.Checkpoint) {
+		consensus.ActiveNetParams.Checkpoints = checkPoints
+	}(consensus.ActiveNetParams.Checkpoints)
+	consensus.ActiveNetParams.Checkpoints = checkPoints
+	mockChain := mock.NewChain()
+	for i, c := range cases {
+		mockChain.SetBestBlockHeader(&types.BlockHeader{Height: c.bestHeight})
+		bk := &blockKeeper{chain: mockChain, forceStateSync: c.forceStateSync}
+
+		if got := bk.statePivot(c.peerHeight); !testutil.DeepEqual(got, c.want) {
+			t.Errorf("case %d: got %v want %v", i, got, c.want)
+		}
+	}
+}
+
 func TestRegularBlockSync(t *testing.T) {
 	baseChain := mockBlocks(nil, 50)
 	chainX := append(baseChain, mockBlocks(baseChain[50], 60)...)
@@ -39,9 +39,13 @@ type Chain interface {
 	GetBlockByHeight(uint64) (*types.Block, error)
 	GetHeaderByHash(*bc.Hash) (*types.BlockHeader, error)
 	GetHeaderByHeight(uint64) (*types.BlockHeader, error)
+	GetStateNode(*bc.Hash) ([]byte, error)
 	GetTransactionStatus(*bc.Hash) (*bc.TransactionStatus, error)
+	HasState(*types.BlockHeader) bool
 	InMainChain(bc.Hash) bool
+	NewStateSync(*types.BlockHeader) *core.StateSync
 	ProcessBlock(*types.Block) (bool, error)
+	ProcessBlockWithoutState(*types.Block, *bc.TransactionStatus) error
 	ValidateTx(*types.Tx) (statusFail bool, height uint64, gasStatus *validation.GasState, err error)
 	ProcessTransaction(tx *types.Tx, statusFail bool, height, fee uint64) (bool, error)
 }
@@ -56,6 +60,7 @@ type SyncManager struct {
 	txPool       *core.TxPool
 	blockFetcher *blockFetcher
 	blockKeeper  *blockKeeper
+	spvKeeper    *spvKeeper
 	peers        *peerSet
 
 	newTxCh    chan *types.Tx
@@ -81,7 +86,7 @@ func NewSyncManager(config *cfg.Config, chain Chain, txPool *core.TxPool, newBlo
 		chain:        chain,
 		privKey:      crypto.GenPrivKeyEd25519(),
 		blockFetcher: newBlockFetcher(chain, peers),
-		blockKeeper:  newBlockKeeper(chain, peers),
+		blockKeeper:  newBlockKeeper(chain, peers, config.StateSync),
 		peers:        peers,
 		newTxCh:      make(chan *types.Tx, maxTxChanSize),
 		newBlockCh:   newBlockCh,
@@ -90,6 +95,12 @@ func NewSyncManager(config *cfg.Config, chain Chain, txPool *core.TxPool, newBlo
 		config:       config,
 	}
 
+	if config.LightMode {
+		if manager.spvKeeper, err = newSPVKeeper(genesisHeader, manager.blockKeeper, peers); err != nil {
+			return nil, err
+		}
+	}
+
 	protocolReactor := NewProtocolReactor(manager, manager.peers)
 	manager.sw.AddReactor("PROTOCOL", protocolReactor)
 
@@ -126,6 +137,12 @@ func (sm *SyncManager) GetNewTxCh() chan *types.Tx {
 	return sm.newTxCh
 }
 
+// RebroadcastTx relays the transaction to all the peers, including the ones
+// which have been announced of it
+func (sm *SyncManager) RebroadcastTx(tx *types.Tx) error {
+	return sm.peers.rebroadcastTx(tx)
+}
+
 //GetPeerInfos return peer info of all peers
 func (sm *SyncManager) GetPeerInfos() []*PeerInfo {
 	return sm.peers.getPeerInfos()
@@ -134,6 +151,9 @@ func (sm *SyncManager) GetPeerInfos() []*PeerInfo {
 //IsCaughtUp check wheather the peer finish the sync
 func (sm *SyncManager) IsCaughtUp() bool {
 	peer := sm.peers.bestPeer(consensus.SFFullNode)
+	if sm.spvKeeper != nil {
+		return peer == nil || peer.Height() <= sm.spvKeeper.bestNode().Height
+	}
 	return peer == nil || peer.Height() <= sm.chain.BestBlockHeight()
 }
 
@@ -142,6 +162,13 @@ func (sm *SyncManager) NodeInfo() *p2p.NodeInfo {
 	return sm.sw.NodeInfo()
 }
 
+// SetLightWallet set the wallet fed by the light mode sync
+func (sm *SyncManager) SetLightWallet(wallet LightWallet) {
+	if sm.spvKeeper != nil {
+		sm.spvKeeper.wallet = wallet
+	}
+}
+
 //StopPeer try to stop peer by given ID
 func (sm *SyncManager) StopPeer(peerID string) error {
 	if peer := sm.peers.getPeer(peerID); peer == nil {
@@ -282,6 +309,49 @@ func (sm *SyncManager) handleGetMerkleBlockMsg(peer *peer, msg *GetMerkleBlockMe
 	}
 }
 
+func (sm *SyncManager) handleGetStateNodesMsg(peer *peer, msg *GetStateNodesMessage) {
+	nodes := [][]byte{}
+	for i, hash := range msg.GetHashes() {
+		if i >= maxStateNodesPerMsg {
+			break
+		}
+
+		node, err := sm.chain.GetStateNode(hash)
+		if err != nil || len(node) == 0 {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	if ok := peer.sendStateNodes(nodes); !ok {
+		sm.peers.removePeer(peer.ID())
+	}
+}
+
+func (sm *SyncManager) handleGetTxStatusesMsg(peer *peer, msg *GetTxStatusesMessage) {
+	txStatuses := []*bc.TransactionStatus{}
+	for i, hash := range msg.GetHashes() {
+		if uint64(i) >= maxBlockPerMsg {
+			break
+		}
+
+		txStatus, err := sm.chain.GetTransactionStatus(hash)
+		if err != nil {
+			log.WithField("err", err).Warning("fail on handleGetTxStatusesMsg get transaction status")
+			return
+		}
+		txStatuses = append(txStatuses, txStatus)
+	}
+
+	ok, err := peer.sendTxStatuses(txStatuses)
+	if !ok {
+		sm.peers.removePeer(peer.ID())
+	}
+	if err != nil {
+		log.WithField("err", err).Error("fail on handleGetTxStatusesMsg sendTxStatuses")
+	}
+}
+
 func (sm *SyncManager) handleHeadersMsg(peer *peer, msg *HeadersMessage) {
 	headers, err := msg.GetHeaders()
 	if err != nil {
@@ -292,6 +362,21 @@ func (sm *SyncManager) handleHeadersMsg(peer *peer, msg *HeadersMessage) {
 	sm.blockKeeper.processHeaders(peer.ID(), headers)
 }
 
+func (sm *SyncManager) handleMerkleBlockMsg(peer *peer, msg *MerkleBlockMessage) {
+	if sm.spvKeeper == nil {
+		return
+	}
+
+	block, txStatus, err := msg.GetMerkleBlock()
+	if err != nil {
+		log.WithField("err", err).Warning("fail on handleMerkleBlockMsg GetMerkleBlock")
+		sm.peers.addBanScore(peer.ID(), 20, 0, "fail on validate merkle block")
+		return
+	}
+
+	sm.spvKeeper.processMerkleBlock(peer.ID(), block, txStatus)
+}
+
 func (sm *SyncManager) handleMineBlockMsg(peer *peer, msg *MineBlockMessage) {
 	block, err := msg.GetMineBlock()
 	if err != nil {
@@ -301,10 +386,16 @@ func (sm *SyncManager) handleMineBlockMsg(peer *peer, msg *MineBlockMessage) {
 
 	hash := block.Hash()
 	peer.markBlock(&hash)
-	sm.blockFetcher.processNewBlock(&blockMsg{peerID: peer.ID(), block: block})
+	if sm.spvKeeper == nil {
+		sm.blockFetcher.processNewBlock(&blockMsg{peerID: peer.ID(), block: block})
+	}
 	peer.setStatus(block.Height, &hash)
 }
 
+func (sm *SyncManager) handleStateNodesMsg(peer *peer, msg *StateNodesMessage) {
+	sm.blockKeeper.processStateNodes(peer.ID(), msg.Nodes)
+}
+
 func (sm *SyncManager) handleStatusRequestMsg(peer BasePeer) {
 	bestHeader := sm.chain.BestBlockHeader()
 	genesisBlock, err := sm.chain.GetBlockByHeight(0)
@@ -336,7 +427,22 @@ func (sm *SyncManager) handleStatusResponseMsg(basePeer BasePeer, msg *StatusRes
 	sm.peers.addPeer(basePeer, msg.Height, msg.GetHash())
 }
 
+func (sm *SyncManager) handleTxStatusesMsg(peer *peer, msg *TxStatusesMessage) {
+	txStatuses, err := msg.GetTxStatuses()
+	if err != nil {
+		log.WithField("err", err).Debug("fail on handleTxStatusesMsg GetTxStatuses")
+		return
+	}
+
+	sm.blockKeeper.processTxStatuses(peer.ID(), txStatuses)
+}
+
 func (sm *SyncManager) handleTransactionMsg(peer *peer, msg *TransactionMessage) {
+	// the light mode chain has no utxo to validate the transaction
+	if sm.spvKeeper != nil {
+		return
+	}
+
 	tx, err := msg.GetTransaction()
 	if err != nil {
 		sm.peers.addBanScore(peer.ID(), 0, 10, "fail on get tx from message")
@@ -405,6 +511,21 @@ func (sm *SyncManager) processMsg(basePeer BasePeer, msgType byte, msg Blockchai
 	case *GetMerkleBlockMessage:
 		sm.handleGetMerkleBlockMsg(peer, msg)
 
+	case *MerkleBlockMessage:
+		sm.handleMerkleBlockMsg(peer, msg)
+
+	case *GetTxStatusesMessage:
+		sm.handleGetTxStatusesMsg(peer, msg)
+
+	case *TxStatusesMessage:
+		sm.handleTxStatusesMsg(peer, msg)
+
+	case *GetStateNodesMessage:
+		sm.handleGetStateNodesMsg(peer, msg)
+
+	case *StateNodesMessage:
+		sm.handleStateNodesMsg(peer, msg)
+
 	default:
 		log.Errorf("unknown message type %v", reflect.TypeOf(msg))
 	}
@@ -426,7 +547,7 @@ func (sm *SyncManager) makeNodeInfo(listenerStatus bool) *p2p.NodeInfo {
 		Moniker: sm.config.Moniker,
 		Network: sm.config.ChainID,
 		Version: version.Version,
-		Other:   []string{strconv.FormatUint(uint64(consensus.DefaultServices), 10)},
+		Other:   []string{strconv.FormatUint(uint64(sm.services()), 10)},
 	}
 
 	if !sm.sw.IsListening() {
@@ -446,11 +567,25 @@ func (sm *SyncManager) makeNodeInfo(listenerStatus bool) *p2p.NodeInfo {
 	return nodeInfo
 }
 
+// services return the service flags advertised to the remote peers, the light
+// mode node serves no block
+func (sm *SyncManager) services() consensus.ServiceFlag {
+	if sm.config.LightMode {
+		return 0
+	}
+	return consensus.DefaultServices
+}
+
 //Start start sync manager service
 func (sm *SyncManager) Start() {
 	if _, err := sm.sw.Start(); err != nil {
 		cmn.Exit(cmn.Fmt("fail on start SyncManager: %v", err))
 	}
+	if sm.spvKeeper != nil {
+		go sm.spvKeeper.syncWorker()
+	} else {
+		go sm.blockKeeper.syncWorker()
+	}
 	// broadcast transactions
 	go sm.txBroadcastLoop()
 	go sm.minedBroadcastLoop()
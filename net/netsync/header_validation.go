@@ -0,0 +1,114 @@
+package netsync
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/consensus/difficulty"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+type powJob struct {
+	header *types.BlockHeader
+	hash   bc.Hash
+	seed   *bc.Hash
+}
+
+// validateHeaderList checks the bits and the proof of work of the header list
+// before the block bodies are downloaded. The bits and the seeds depend on the
+// previous headers so they are calculated in order, the proof of work of each
+// header is independent and checked in parallel.
+func (bk *blockKeeper) validateHeaderList() error {
+	headers := []*types.BlockHeader{}
+	for e := bk.headerList.Front(); e != nil; e = e.Next() {
+		headers = append(headers, e.Value.(*types.BlockHeader))
+	}
+	if len(headers) < 2 {
+		return nil
+	}
+
+	firstHash := headers[0].Hash()
+	seed, err := bk.chain.CalcNextSeed(&firstHash)
+	if err != nil {
+		return err
+	}
+
+	jobs := make([]*powJob, 0, len(headers)-1)
+	for i := 1; i < len(headers); i++ {
+		prev, header := headers[i-1], headers[i]
+		bits, err := bk.calcNextBits(headers, prev)
+		if err != nil {
+			return err
+		}
+
+		if header.Bits != bits {
+			return errors.Wrapf(errPeerMisbehave, "header %d bits %d, want %d", header.Height, header.Bits, bits)
+		}
+
+		if i > 1 && prev.Height%consensus.SeedPerRetarget == 0 {
+			prevHash := prev.Hash()
+			seed = &prevHash
+		}
+		jobs = append(jobs, &powJob{header: header, hash: header.Hash(), seed: seed})
+	}
+	return checkProofOfWork(jobs)
+}
+
+// calcNextBits return the bits of the header next to prev, the retarget
+// header is taken from the header list or the local main chain below it
+func (bk *blockKeeper) calcNextBits(headers []*types.BlockHeader, prev *types.BlockHeader) (uint64, error) {
+	if prev.Height%consensus.BlocksPerRetarget != 0 || prev.Height == 0 {
+		return prev.Bits, nil
+	}
+
+	compareHeight := prev.Height - consensus.BlocksPerRetarget
+	if compareHeight >= headers[0].Height {
+		return difficulty.CalcNextRequiredDifficulty(prev, headers[compareHeight-headers[0].Height]), nil
+	}
+
+	compareHeader, err := bk.chain.GetHeaderByHeight(compareHeight)
+	if err != nil {
+		return 0, err
+	}
+	return difficulty.CalcNextRequiredDifficulty(prev, compareHeader), nil
+}
+
+func checkProofOfWork(jobs []*powJob) error {
+	jobCh := make(chan *powJob, len(jobs))
+	for _, job := range jobs {
+		jobCh <- job
+	}
+	close(jobCh)
+
+	var (
+		wg      sync.WaitGroup
+		mtx     sync.Mutex
+		failJob *powJob
+	)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				if difficulty.CheckProofOfWork(&job.hash, job.seed, job.header.Bits) {
+					continue
+				}
+
+				mtx.Lock()
+				if failJob == nil || job.header.Height < failJob.header.Height {
+					failJob = job
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failJob != nil {
+		return errors.Wrapf(errPeerMisbehave, "header %d fail on proof of work", failJob.header.Height)
+	}
+	return nil
+}
@@ -16,21 +16,25 @@ import (
 const (
 	BlockchainChannel = byte(0x40)
 
-	BlockRequestByte    = byte(0x10)
-	BlockResponseByte   = byte(0x11)
-	HeadersRequestByte  = byte(0x12)
-	HeadersResponseByte = byte(0x13)
-	BlocksRequestByte   = byte(0x14)
-	BlocksResponseByte  = byte(0x15)
-	StatusRequestByte   = byte(0x20)
-	StatusResponseByte  = byte(0x21)
-	NewTransactionByte  = byte(0x30)
-	NewMineBlockByte    = byte(0x40)
-	FilterLoadByte      = byte(0x50)
-	FilterAddByte       = byte(0x51)
-	FilterClearByte     = byte(0x52)
-	MerkleRequestByte   = byte(0x60)
-	MerkleResponseByte  = byte(0x61)
+	BlockRequestByte     = byte(0x10)
+	BlockResponseByte    = byte(0x11)
+	HeadersRequestByte   = byte(0x12)
+	HeadersResponseByte  = byte(0x13)
+	BlocksRequestByte    = byte(0x14)
+	BlocksResponseByte   = byte(0x15)
+	StatusRequestByte    = byte(0x20)
+	StatusResponseByte   = byte(0x21)
+	NewTransactionByte   = byte(0x30)
+	NewMineBlockByte     = byte(0x40)
+	FilterLoadByte       = byte(0x50)
+	FilterAddByte        = byte(0x51)
+	FilterClearByte      = byte(0x52)
+	MerkleRequestByte    = byte(0x60)
+	MerkleResponseByte   = byte(0x61)
+	TxStatusRequestByte  = byte(0x70)
+	TxStatusResponseByte = byte(0x71)
+	StateRequestByte     = byte(0x72)
+	StateResponseByte    = byte(0x73)
 
 	maxBlockchainResponseSize = 22020096 + 2
 )
@@ -55,6 +59,10 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{&FilterClearMessage{}, FilterClearByte},
 	wire.ConcreteType{&GetMerkleBlockMessage{}, MerkleRequestByte},
 	wire.ConcreteType{&MerkleBlockMessage{}, MerkleResponseByte},
+	wire.ConcreteType{&GetTxStatusesMessage{}, TxStatusRequestByte},
+	wire.ConcreteType{&TxStatusesMessage{}, TxStatusResponseByte},
+	wire.ConcreteType{&GetStateNodesMessage{}, StateRequestByte},
+	wire.ConcreteType{&StateNodesMessage{}, StateResponseByte},
 )
 
 //DecodeMessage decode msg
@@ -431,3 +439,141 @@ func (msg *MerkleBlockMessage) setStatusInfo(statusHashes []*bc.Hash, relatedSta
 func NewMerkleBlockMessage() *MerkleBlockMessage {
 	return &MerkleBlockMessage{}
 }
+
+//GetMerkleBlock decode the merkle block message and verify the related
+//transactions and statuses against the merkle roots of the block header
+func (msg *MerkleBlockMessage) GetMerkleBlock() (*types.Block, *bc.TransactionStatus, error) {
+	block := &types.Block{}
+	if err := block.BlockHeader.UnmarshalText(msg.RawBlockHeader); err != nil {
+		return nil, nil, err
+	}
+
+	txIDs := []*bc.Hash{}
+	for _, rawTx := range msg.RawTxDatas {
+		tx := &types.Tx{}
+		if err := tx.UnmarshalText(rawTx); err != nil {
+			return nil, nil, err
+		}
+
+		block.Transactions = append(block.Transactions, tx)
+		txIDs = append(txIDs, &tx.ID)
+	}
+
+	txHashes := []*bc.Hash{}
+	for _, rawHash := range msg.TxHashes {
+		hash := bc.NewHash(rawHash)
+		txHashes = append(txHashes, &hash)
+	}
+	if !types.ValidateTxMerkleTreeProof(txHashes, msg.Flags, txIDs, block.TransactionsMerkleRoot) {
+		return nil, nil, errors.New("fail on validate the transactions merkle proof")
+	}
+
+	txStatus := bc.NewTransactionStatus()
+	for _, rawStatus := range msg.RawTxStatuses {
+		status := &bc.TxVerifyResult{}
+		if err := json.Unmarshal(rawStatus, status); err != nil {
+			return nil, nil, err
+		}
+		txStatus.VerifyStatus = append(txStatus.VerifyStatus, status)
+	}
+
+	statusHashes := []*bc.Hash{}
+	for _, rawHash := range msg.StatusHashes {
+		hash := bc.NewHash(rawHash)
+		statusHashes = append(statusHashes, &hash)
+	}
+	if !types.ValidateStatusMerkleTreeProof(statusHashes, msg.Flags, txStatus.VerifyStatus, block.TransactionStatusHash) {
+		return nil, nil, errors.New("fail on validate the statuses merkle proof")
+	}
+	if len(txStatus.VerifyStatus) != len(block.Transactions) {
+		return nil, nil, errors.New("the count of statuses mismatch the transactions")
+	}
+	return block, txStatus, nil
+}
+
+//GetTxStatusesMessage request the transaction statuses of the blocks for the
+//blocks below the pivot of the state sync
+type GetTxStatusesMessage struct {
+	RawHashes [][32]byte
+}
+
+//NewGetTxStatusesMessage create a new GetTxStatusesMessage
+func NewGetTxStatusesMessage(hashes []*bc.Hash) *GetTxStatusesMessage {
+	msg := &GetTxStatusesMessage{}
+	for _, hash := range hashes {
+		msg.RawHashes = append(msg.RawHashes, hash.Byte32())
+	}
+	return msg
+}
+
+//GetHashes return the block hashes of the msg
+func (msg *GetTxStatusesMessage) GetHashes() []*bc.Hash {
+	hashes := []*bc.Hash{}
+	for _, rawHash := range msg.RawHashes {
+		hash := bc.NewHash(rawHash)
+		hashes = append(hashes, &hash)
+	}
+	return hashes
+}
+
+//TxStatusesMessage return the transaction statuses in the order of the request
+type TxStatusesMessage struct {
+	RawTxStatuses [][]byte
+}
+
+//NewTxStatusesMessage create a new TxStatusesMessage
+func NewTxStatusesMessage(txStatuses []*bc.TransactionStatus) (*TxStatusesMessage, error) {
+	msg := &TxStatusesMessage{}
+	for _, txStatus := range txStatuses {
+		rawTxStatus, err := json.Marshal(txStatus)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.RawTxStatuses = append(msg.RawTxStatuses, rawTxStatus)
+	}
+	return msg, nil
+}
+
+//GetTxStatuses return the transaction statuses in the msg
+func (msg *TxStatusesMessage) GetTxStatuses() ([]*bc.TransactionStatus, error) {
+	txStatuses := []*bc.TransactionStatus{}
+	for _, rawTxStatus := range msg.RawTxStatuses {
+		txStatus := &bc.TransactionStatus{}
+		if err := json.Unmarshal(rawTxStatus, txStatus); err != nil {
+			return nil, err
+		}
+
+		txStatuses = append(txStatuses, txStatus)
+	}
+	return txStatuses, nil
+}
+
+//GetStateNodesMessage request the state trie nodes and contract codes by hash
+type GetStateNodesMessage struct {
+	RawHashes [][32]byte
+}
+
+//NewGetStateNodesMessage create a new GetStateNodesMessage
+func NewGetStateNodesMessage(hashes []bc.Hash) *GetStateNodesMessage {
+	msg := &GetStateNodesMessage{}
+	for _, hash := range hashes {
+		msg.RawHashes = append(msg.RawHashes, hash.Byte32())
+	}
+	return msg
+}
+
+//GetHashes return the state node hashes of the msg
+func (msg *GetStateNodesMessage) GetHashes() []*bc.Hash {
+	hashes := []*bc.Hash{}
+	for _, rawHash := range msg.RawHashes {
+		hash := bc.NewHash(rawHash)
+		hashes = append(hashes, &hash)
+	}
+	return hashes
+}
+
+//StateNodesMessage return the found state nodes, the missing ones are skipped
+type StateNodesMessage struct {
+	Nodes [][]byte
+}
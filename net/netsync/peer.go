@@ -126,6 +126,11 @@ func (p *peer) getHeaders(locator []*bc.Hash, stopHash *bc.Hash) bool {
 	return p.TrySend(BlockchainChannel, msg)
 }
 
+func (p *peer) getMerkleBlock(hash *bc.Hash) bool {
+	msg := struct{ BlockchainMessage }{&GetMerkleBlockMessage{RawHash: hash.Byte32()}}
+	return p.TrySend(BlockchainChannel, msg)
+}
+
 func (p *peer) getPeerInfo() *PeerInfo {
 	p.mtx.RLock()
 	defer p.mtx.RUnlock()
@@ -148,6 +153,22 @@ func (p *peer) getRelatedTxAndStatus(txs []*types.Tx, txStatuses *bc.Transaction
 	return relatedTxs, relatedStatuses
 }
 
+func (p *peer) getStateNodes(hashes []bc.Hash) bool {
+	msg := struct{ BlockchainMessage }{NewGetStateNodesMessage(hashes)}
+	return p.TrySend(BlockchainChannel, msg)
+}
+
+func (p *peer) getStatus() (uint64, *bc.Hash) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	return p.height, p.hash
+}
+
+func (p *peer) getTxStatuses(hashes []*bc.Hash) bool {
+	msg := struct{ BlockchainMessage }{NewGetTxStatusesMessage(hashes)}
+	return p.TrySend(BlockchainChannel, msg)
+}
+
 func (p *peer) isRelatedTx(tx *types.Tx) bool {
 	for _, input := range tx.Inputs {
 		switch inp := input.TypedInput.(type) {
@@ -169,6 +190,11 @@ func (p *peer) isSPVNode() bool {
 	return !p.services.IsEnable(consensus.SFFullNode)
 }
 
+func (p *peer) loadFilter(addresses [][]byte) bool {
+	msg := struct{ BlockchainMessage }{&FilterLoadMessage{Addresses: addresses}}
+	return p.TrySend(BlockchainChannel, msg)
+}
+
 func (p *peer) markBlock(hash *bc.Hash) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -252,6 +278,11 @@ func (p *peer) sendMerkleBlock(block *types.Block, txStatuses *bc.TransactionSta
 	return ok, nil
 }
 
+func (p *peer) sendStateNodes(nodes [][]byte) bool {
+	msg := struct{ BlockchainMessage }{&StateNodesMessage{Nodes: nodes}}
+	return p.TrySend(BlockchainChannel, msg)
+}
+
 func (p *peer) sendTransactions(txs []*types.Tx) (bool, error) {
 	for _, tx := range txs {
 		if p.isSPVNode() && !p.isRelatedTx(tx) {
@@ -273,6 +304,16 @@ func (p *peer) sendTransactions(txs []*types.Tx) (bool, error) {
 	return true, nil
 }
 
+func (p *peer) sendTxStatuses(txStatuses []*bc.TransactionStatus) (bool, error) {
+	msg, err := NewTxStatusesMessage(txStatuses)
+	if err != nil {
+		return false, errors.Wrap(err, "fail on NewTxStatusesMessage")
+	}
+
+	ok := p.TrySend(BlockchainChannel, struct{ BlockchainMessage }{msg})
+	return ok, nil
+}
+
 func (p *peer) setStatus(height uint64, hash *bc.Hash) {
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
@@ -322,6 +363,17 @@ func (ps *peerSet) addPeer(peer BasePeer, height uint64, hash *bc.Hash) {
 	log.WithField("ID", peer.ID()).Warning("add existing peer to blockKeeper")
 }
 
+func (ps *peerSet) allPeers() []*peer {
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+
+	peers := []*peer{}
+	for _, peer := range ps.peers {
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
 func (ps *peerSet) bestPeer(flag consensus.ServiceFlag) *peer {
 	ps.mtx.RLock()
 	defer ps.mtx.RUnlock()
@@ -372,12 +424,21 @@ func (ps *peerSet) broadcastNewStatus(bestBlock, genesisBlock *types.Block) erro
 }
 
 func (ps *peerSet) broadcastTx(tx *types.Tx) error {
+	return ps.sendTx(tx, ps.peersWithoutTx(&tx.ID))
+}
+
+// rebroadcastTx relays the transaction to all the peers, the ones which are
+// announced of the transaction may have dropped it
+func (ps *peerSet) rebroadcastTx(tx *types.Tx) error {
+	return ps.sendTx(tx, ps.allPeers())
+}
+
+func (ps *peerSet) sendTx(tx *types.Tx, peers []*peer) error {
 	msg, err := NewTransactionMessage(tx)
 	if err != nil {
 		return errors.Wrap(err, "fail on broadcast tx")
 	}
 
-	peers := ps.peersWithoutTx(&tx.ID)
 	for _, peer := range peers {
 		if peer.isSPVNode() && !peer.isRelatedTx(tx) {
 			continue
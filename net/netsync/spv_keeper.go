@@ -0,0 +1,281 @@
+package netsync
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/core/wallet"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+	"github.com/doslink/doslink/protocol/state"
+	"github.com/doslink/doslink/protocol/validation"
+)
+
+const merkleBlockProcessChSize = 1024
+
+// LightWallet is the wallet driven by the light mode sync, its control
+// programs are loaded into the filter of the sync peer and it is fed with the
+// proven transactions of the main chain headers
+type LightWallet interface {
+	AttachMerkleBlock(*types.Block, *bc.TransactionStatus) error
+	ControlPrograms() ([][]byte, error)
+	GetWalletStatusInfo() wallet.StatusInfo
+	RescanBlocks()
+}
+
+type merkleBlockMsg struct {
+	block    *types.Block
+	txStatus *bc.TransactionStatus
+	peerID   string
+}
+
+// spvKeeper syncs the block headers of the light mode and the merkle blocks of
+// the wallet. The headers are kept in memory only, they are synced again from
+// the genesis block after a restart.
+type spvKeeper struct {
+	blockKeeper *blockKeeper
+	peers       *peerSet
+	wallet      LightWallet
+
+	mtx       sync.RWMutex
+	mainChain []*state.BlockNode
+
+	merkleBlockProcessCh chan *merkleBlockMsg
+	filterPeer           string
+	filterSize           int
+}
+
+func newSPVKeeper(genesis *types.BlockHeader, blockKeeper *blockKeeper, peers *peerSet) (*spvKeeper, error) {
+	node, err := state.NewBlockNode(genesis, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &spvKeeper{
+		blockKeeper:          blockKeeper,
+		peers:                peers,
+		mainChain:            []*state.BlockNode{node},
+		merkleBlockProcessCh: make(chan *merkleBlockMsg, merkleBlockProcessChSize),
+	}, nil
+}
+
+// appendHeaders validates the headers and switches the main chain to them if
+// they carry more work, the first header must extend the local main chain
+func (sk *spvKeeper) appendHeaders(headers []*types.BlockHeader) error {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	sk.mtx.Lock()
+	defer sk.mtx.Unlock()
+
+	forkHeight := headers[0].Height - 1
+	if headers[0].Height == 0 || forkHeight >= uint64(len(sk.mainChain)) {
+		return errors.Wrap(errPeerMisbehave, "headers not connect to the main chain")
+	}
+
+	parent := sk.mainChain[forkHeight]
+	nodes := []*state.BlockNode{}
+	for _, header := range headers {
+		if err := validation.ValidateBlockHeader(types.MapBlock(&types.Block{BlockHeader: *header}), parent); err != nil {
+			return errors.Wrap(errPeerMisbehave, err.Error())
+		}
+
+		node, err := state.NewBlockNode(header, parent)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, node)
+		parent = node
+	}
+
+	if parent.WorkSum.Cmp(sk.mainChain[len(sk.mainChain)-1].WorkSum) <= 0 {
+		return nil
+	}
+	sk.mainChain = append(sk.mainChain[:forkHeight+1], nodes...)
+	return nil
+}
+
+func (sk *spvKeeper) bestNode() *state.BlockNode {
+	sk.mtx.RLock()
+	defer sk.mtx.RUnlock()
+	return sk.mainChain[len(sk.mainChain)-1]
+}
+
+func (sk *spvKeeper) blockLocator() []*bc.Hash {
+	sk.mtx.RLock()
+	defer sk.mtx.RUnlock()
+
+	locator := []*bc.Hash{}
+	step := uint64(1)
+	for height := uint64(len(sk.mainChain) - 1); ; {
+		locator = append(locator, &sk.mainChain[height].Hash)
+		if height == 0 {
+			break
+		}
+
+		if height < step {
+			height = 0
+		} else {
+			height -= step
+		}
+
+		if len(locator) >= 9 {
+			step *= 2
+		}
+	}
+	return locator
+}
+
+// loadFilter loads the wallet control programs into the filter of the peer,
+// the filter is loaded again once the wallet creates new addresses
+func (sk *spvKeeper) loadFilter(peer *peer) error {
+	programs, err := sk.wallet.ControlPrograms()
+	if err != nil {
+		return err
+	}
+
+	if peer.ID() == sk.filterPeer && len(programs) == sk.filterSize {
+		return nil
+	}
+	if len(programs) > maxFilterAddressCount {
+		log.WithField("count", len(programs)).Warning("the count of wallet control programs is greater than filter limit")
+	}
+
+	if ok := peer.loadFilter(programs); !ok {
+		return errPeerDropped
+	}
+	sk.filterPeer, sk.filterSize = peer.ID(), len(programs)
+	return nil
+}
+
+func (sk *spvKeeper) nodeByHeight(height uint64) *state.BlockNode {
+	sk.mtx.RLock()
+	defer sk.mtx.RUnlock()
+
+	if height >= uint64(len(sk.mainChain)) {
+		return nil
+	}
+	return sk.mainChain[height]
+}
+
+func (sk *spvKeeper) processMerkleBlock(peerID string, block *types.Block, txStatus *bc.TransactionStatus) {
+	sk.merkleBlockProcessCh <- &merkleBlockMsg{block: block, txStatus: txStatus, peerID: peerID}
+}
+
+func (sk *spvKeeper) requireMerkleBlock(peer *peer, hash *bc.Hash) (*types.Block, *bc.TransactionStatus, error) {
+	if ok := peer.getMerkleBlock(hash); !ok {
+		return nil, nil, errPeerDropped
+	}
+
+	waitTicker := time.NewTimer(syncTimeout)
+	for {
+		select {
+		case msg := <-sk.merkleBlockProcessCh:
+			if msg.peerID != peer.ID() {
+				continue
+			}
+			if msg.block.Hash() != *hash {
+				continue
+			}
+			return msg.block, msg.txStatus, nil
+		case <-waitTicker.C:
+			return nil, nil, errors.Wrap(errRequestTimeout, "requireMerkleBlock")
+		}
+	}
+}
+
+func (sk *spvKeeper) syncHeaders(peer *peer) error {
+	sk.blockKeeper.syncPeer = peer
+	for {
+		peerHeight, peerHash := peer.getStatus()
+		if sk.bestNode().Height >= peerHeight {
+			return nil
+		}
+
+		headers, err := sk.blockKeeper.requireHeaders(sk.blockLocator(), peerHash)
+		if err != nil {
+			return err
+		}
+
+		if len(headers) == 0 {
+			return errors.Wrap(errPeerMisbehave, "requireHeaders return empty list")
+		}
+
+		bestHash := sk.bestNode().Hash
+		if err := sk.appendHeaders(headers); err != nil {
+			return err
+		}
+
+		// the peer is on a branch with less work
+		if sk.bestNode().Hash == bestHash {
+			return nil
+		}
+	}
+}
+
+// syncMerkleBlocks feeds the wallet with the merkle blocks of the main chain
+// headers above the wallet, the wallet is rescanned if it is on a branch
+// which is no longer in the main chain
+func (sk *spvKeeper) syncMerkleBlocks(peer *peer) error {
+	status := sk.wallet.GetWalletStatusInfo()
+	if node := sk.nodeByHeight(status.WorkHeight); node == nil || node.Hash != status.WorkHash {
+		sk.wallet.RescanBlocks()
+		return nil
+	}
+
+	if err := sk.loadFilter(peer); err != nil {
+		return err
+	}
+
+	for height := status.WorkHeight + 1; ; height++ {
+		node := sk.nodeByHeight(height)
+		if node == nil {
+			return nil
+		}
+
+		block, txStatus, err := sk.requireMerkleBlock(peer, &node.Hash)
+		if err != nil {
+			return err
+		}
+
+		if err := sk.wallet.AttachMerkleBlock(block, txStatus); err != nil {
+			return err
+		}
+
+		// the wallet skips the block once a rescan resets its status
+		if sk.wallet.GetWalletStatusInfo().WorkHash != node.Hash {
+			return nil
+		}
+	}
+}
+
+func (sk *spvKeeper) syncWorker() {
+	syncTicker := time.NewTicker(syncCycle)
+	for {
+		<-syncTicker.C
+		peer := sk.peers.bestPeer(consensus.SFFullNode | consensus.SFSPV)
+		if peer == nil {
+			continue
+		}
+
+		if err := sk.syncHeaders(peer); err != nil {
+			log.WithField("err", err).Warning("fail on spv syncHeaders")
+			sk.peers.errorHandler(peer.ID(), err)
+			continue
+		}
+
+		if sk.wallet == nil {
+			continue
+		}
+
+		if err := sk.syncMerkleBlocks(peer); err != nil {
+			log.WithField("err", err).Warning("fail on spv syncMerkleBlocks")
+			sk.peers.errorHandler(peer.ID(), err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package netsync
+
+import (
+	"time"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	core "github.com/doslink/doslink/protocol"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+const (
+	// statePivotDistance is the least distance of the state sync pivot below
+	// the best height of the sync peer
+	statePivotDistance = 64
+	// minStateSyncGap is the least distance between the best height and the
+	// pivot to start the forced state sync on a node with the full state
+	minStateSyncGap     = 1024
+	maxStateNodesPerMsg = 384
+)
+
+// statePivot return the checkpoint to sync the state of, nil if the node
+// should execute the blocks. Only a new node at the genesis block, a node in
+// the middle of a state sync which has no state of its best block, or a node
+// with forceStateSync syncs the state, and the pivot is always a checkpoint so
+// that the saved blocks and the state root are trusted.
+func (bk *blockKeeper) statePivot(peerHeight uint64) *consensus.Checkpoint {
+	bestHeader := bk.chain.BestBlockHeader()
+	hasState := bk.chain.HasState(bestHeader)
+	if hasState && bestHeader.Height != 0 && !bk.forceStateSync {
+		return nil
+	}
+
+	var pivot *consensus.Checkpoint
+	checkpoints := consensus.ActiveNetParams.Checkpoints
+	for i := range checkpoints {
+		checkpoint := &checkpoints[i]
+		if !hasState && checkpoint.Height == bestHeader.Height && checkpoint.Hash == bestHeader.Hash() {
+			return checkpoint
+		}
+		if checkpoint.Height <= bestHeader.Height || checkpoint.Height+statePivotDistance > peerHeight {
+			continue
+		}
+		if hasState && bestHeader.Height != 0 && checkpoint.Height < bestHeader.Height+minStateSyncGap {
+			continue
+		}
+		if pivot == nil || checkpoint.Height > pivot.Height {
+			pivot = checkpoint
+		}
+	}
+	return pivot
+}
+
+// stateSync saves the blocks of the checkpoint branch up to the pivot with the
+// transaction statuses of the sync peer instead of executing them, then
+// downloads the state trie of the pivot. The blocks above the pivot are
+// validated as usual.
+func (bk *blockKeeper) stateSync(pivot *consensus.Checkpoint) error {
+	if bk.chain.BestBlockHeight() < pivot.Height {
+		if err := bk.checkpointHeaders(pivot); err != nil {
+			return err
+		}
+	}
+
+	pivotHeader := bk.headerList.Front()
+	for bk.chain.BestBlockHeight() < pivot.Height {
+		blocks, err := bk.requireBlocks(bk.blockLocator(), &pivot.Hash)
+		if err != nil {
+			return err
+		}
+
+		if len(blocks) == 0 {
+			return errors.Wrap(errPeerMisbehave, "requireBlocks return empty list")
+		}
+
+		hashes := []*bc.Hash{}
+		for _, block := range blocks {
+			if pivotHeader = pivotHeader.Next(); pivotHeader == nil {
+				return errors.Wrap(errPeerMisbehave, "get block than is higher than the pivot")
+			}
+
+			blockHash := block.Hash()
+			if blockHash != pivotHeader.Value.(*types.BlockHeader).Hash() {
+				return errors.Wrap(errPeerMisbehave, "block is not in the checkpoint branch")
+			}
+			hashes = append(hashes, &blockHash)
+		}
+
+		txStatuses, err := bk.requireTxStatuses(hashes)
+		if err != nil {
+			return err
+		}
+
+		if len(txStatuses) != len(blocks) {
+			return errors.Wrap(errPeerMisbehave, "requireTxStatuses return mismatch list")
+		}
+
+		for i, block := range blocks {
+			if err := bk.chain.ProcessBlockWithoutState(block, txStatuses[i]); err != nil {
+				if errors.Root(err) == core.ErrBadBlock {
+					return errors.Wrap(errPeerMisbehave, err.Error())
+				}
+				return err
+			}
+		}
+	}
+
+	stateSync := bk.chain.NewStateSync(bk.chain.BestBlockHeader())
+	for stateSync.Pending() > 0 {
+		nodes, err := bk.requireStateNodes(stateSync.Missing(maxStateNodesPerMsg))
+		if err != nil {
+			return err
+		}
+
+		if len(nodes) == 0 {
+			return errors.Wrap(errPeerMisbehave, "requireStateNodes return empty list")
+		}
+
+		if err := stateSync.Process(nodes); err != nil {
+			return errors.Wrap(errPeerMisbehave, err.Error())
+		}
+	}
+	return nil
+}
+
+func (bk *blockKeeper) processStateNodes(peerID string, nodes [][]byte) {
+	bk.stateNodesProcessCh <- &stateNodesMsg{nodes: nodes, peerID: peerID}
+}
+
+func (bk *blockKeeper) processTxStatuses(peerID string, txStatuses []*bc.TransactionStatus) {
+	bk.txStatusesProcessCh <- &txStatusesMsg{txStatuses: txStatuses, peerID: peerID}
+}
+
+func (bk *blockKeeper) requireStateNodes(hashes []bc.Hash) ([][]byte, error) {
+	if ok := bk.syncPeer.getStateNodes(hashes); !ok {
+		return nil, errPeerDropped
+	}
+
+	waitTicker := time.NewTimer(syncTimeout)
+	for {
+		select {
+		case msg := <-bk.stateNodesProcessCh:
+			if msg.peerID != bk.syncPeer.ID() {
+				continue
+			}
+			return msg.nodes, nil
+		case <-waitTicker.C:
+			return nil, errors.Wrap(errRequestTimeout, "requireStateNodes")
+		}
+	}
+}
+
+func (bk *blockKeeper) requireTxStatuses(hashes []*bc.Hash) ([]*bc.TransactionStatus, error) {
+	if ok := bk.syncPeer.getTxStatuses(hashes); !ok {
+		return nil, errPeerDropped
+	}
+
+	waitTicker := time.NewTimer(syncTimeout)
+	for {
+		select {
+		case msg := <-bk.txStatusesProcessCh:
+			if msg.peerID != bk.syncPeer.ID() {
+				continue
+			}
+			return msg.txStatuses, nil
+		case <-waitTicker.C:
+			return nil, errors.Wrap(errRequestTimeout, "requireTxStatuses")
+		}
+	}
+}
@@ -153,7 +153,7 @@ func mockSync(blocks []*types.Block) *SyncManager {
 	return &SyncManager{
 		genesisHash: genesis.Hash(),
 		chain:       chain,
-		blockKeeper: newBlockKeeper(chain, peers),
+		blockKeeper: newBlockKeeper(chain, peers, false),
 		peers:       peers,
 	}
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/doslink/doslink/api"
 	"github.com/doslink/doslink/core/asset"
 	"github.com/doslink/doslink/core/pseudohsm"
+	"github.com/doslink/doslink/core/remotesigner"
 	cfg "github.com/doslink/doslink/config"
 	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/database/leveldb"
@@ -49,6 +50,7 @@ type Node struct {
 	accessTokens *accesstoken.CredentialStore
 	api          *api.API
 	chain        *protocol.Chain
+	txPool       *protocol.TxPool
 	cpuMiner     *cpuminer.CPUMiner
 	miningPool   *miningpool.MiningPool
 	miningEnable bool
@@ -77,7 +79,12 @@ func NewNode(config *cfg.Config) *Node {
 	var assets *asset.Registry = nil
 	var wallet *w.Wallet = nil
 
-	hsm, err := pseudohsm.New(config.KeysDir())
+	var hsm pseudohsm.Signer
+	if config.HsmUrl != "" {
+		hsm, err = remotesigner.NewClient(config.HsmUrl)
+	} else {
+		hsm, err = pseudohsm.New(config.KeysDir())
+	}
 	if err != nil {
 		cmn.Exit(cmn.Fmt("initialize HSM failed: %v", err))
 	}
@@ -86,7 +93,11 @@ func NewNode(config *cfg.Config) *Node {
 		walletDB := dbm.NewDB("wallet", config.DBBackend, config.DBDir())
 		accounts = account.NewManager(walletDB, chain)
 		assets = asset.NewRegistry(walletDB, chain)
-		wallet, err = w.NewWallet(walletDB, accounts, assets, hsm, chain)
+		if config.LightMode {
+			wallet, err = w.NewLightWallet(walletDB, accounts, assets, hsm, chain)
+		} else {
+			wallet, err = w.NewWallet(walletDB, accounts, assets, hsm, chain)
+		}
 		if err != nil {
 			log.WithField("error", err).Error("init NewWallet")
 		}
@@ -99,6 +110,11 @@ func NewNode(config *cfg.Config) *Node {
 	newBlockCh := make(chan *bc.Hash, maxNewBlockChSize)
 
 	syncManager, _ := netsync.NewSyncManager(config, chain, txPool, newBlockCh)
+	if config.LightMode && wallet != nil {
+		syncManager.SetLightWallet(wallet)
+	} else if wallet != nil {
+		wallet.SetTxBroadcaster(syncManager)
+	}
 
 	// get transaction from txPool and send it to syncManager and wallet
 	go newPoolTxListener(txPool, syncManager, wallet)
@@ -119,6 +135,7 @@ func NewNode(config *cfg.Config) *Node {
 		accessTokens: accessTokens,
 		wallet:       wallet,
 		chain:        chain,
+		txPool:       txPool,
 		miningEnable: config.Mining,
 	}
 
@@ -168,6 +185,12 @@ func initActiveNetParams(config *cfg.Config) {
 	if !exist {
 		cmn.Exit(cmn.Fmt("chain_id[%v] don't exist", config.ChainID))
 	}
+
+	checkpoints, err := config.ChainCheckpoints(config.ChainID, consensus.ActiveNetParams.Checkpoints)
+	if err != nil {
+		cmn.Exit(cmn.Fmt("Failed to load checkpoints: %v", err))
+	}
+	consensus.ActiveNetParams.Checkpoints = checkpoints
 }
 
 func initLogFile(config *cfg.Config) {
@@ -214,6 +237,12 @@ func (n *Node) OnStart() error {
 	if !n.config.VaultMode {
 		n.syncManager.Start()
 	}
+	// the light mode has no utxo to validate the saved transactions
+	if !n.config.LightMode {
+		if err := n.chain.RestoreTxPool(); err != nil {
+			log.WithField("err", err).Error("fail on restore the transaction pool")
+		}
+	}
 	n.initAndstartApiServer()
 	if !n.config.Web.Closed {
 		s := strings.Split(n.config.ApiAddress, ":")
@@ -233,6 +262,11 @@ func (n *Node) OnStop() {
 	if !n.config.VaultMode {
 		n.syncManager.Stop()
 	}
+	if !n.config.LightMode {
+		if err := n.txPool.Save(); err != nil {
+			log.WithField("err", err).Error("fail on save the transaction pool")
+		}
+	}
 }
 
 func (n *Node) RunForever() {
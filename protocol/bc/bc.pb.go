@@ -448,8 +448,13 @@ func (m *TxLog) GetData() []byte {
 }
 
 type TxVerifyResult struct {
-	StatusFail bool     `protobuf:"varint,1,opt,name=status_fail,json=statusFail" json:"status_fail,omitempty"`
-	Logs       []*TxLog `protobuf:"bytes,2,rep,name=logs" json:"logs,omitempty"`
+	StatusFail        bool     `protobuf:"varint,1,opt,name=status_fail,json=statusFail" json:"status_fail,omitempty"`
+	Logs              []*TxLog `protobuf:"bytes,2,rep,name=logs" json:"logs,omitempty"`
+	GasUsed           uint64   `protobuf:"varint,3,opt,name=gas_used,json=gasUsed" json:"gas_used,omitempty"`
+	CumulativeGasUsed uint64   `protobuf:"varint,4,opt,name=cumulative_gas_used,json=cumulativeGasUsed" json:"cumulative_gas_used,omitempty"`
+	ContractAddress   []byte   `protobuf:"bytes,5,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+	Bloom             []byte   `protobuf:"bytes,6,opt,name=bloom,proto3" json:"bloom,omitempty"`
+	RevertData        []byte   `protobuf:"bytes,7,opt,name=revert_data,json=revertData,proto3" json:"revert_data,omitempty"`
 }
 
 func (m *TxVerifyResult) Reset()                    { *m = TxVerifyResult{} }
@@ -471,6 +476,41 @@ func (m *TxVerifyResult) GetLogs() []*TxLog {
 	return nil
 }
 
+func (m *TxVerifyResult) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *TxVerifyResult) GetCumulativeGasUsed() uint64 {
+	if m != nil {
+		return m.CumulativeGasUsed
+	}
+	return 0
+}
+
+func (m *TxVerifyResult) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+func (m *TxVerifyResult) GetBloom() []byte {
+	if m != nil {
+		return m.Bloom
+	}
+	return nil
+}
+
+func (m *TxVerifyResult) GetRevertData() []byte {
+	if m != nil {
+		return m.RevertData
+	}
+	return nil
+}
+
 type TransactionStatus struct {
 	Version      uint64            `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
 	VerifyStatus []*TxVerifyResult `protobuf:"bytes,2,rep,name=verify_status,json=verifyStatus" json:"verify_status,omitempty"`
@@ -1037,80 +1077,84 @@ func init() {
 func init() { proto.RegisterFile("bc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1186 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x8e, 0x1b, 0x45,
-	0x10, 0x96, 0x67, 0xc6, 0x3f, 0x5b, 0xde, 0xac, 0x77, 0x3b, 0x3f, 0x8c, 0x42, 0xa2, 0x0d, 0x96,
-	0x42, 0x82, 0x90, 0xa2, 0x64, 0x13, 0xc2, 0x85, 0x03, 0x61, 0x97, 0x10, 0x4b, 0xac, 0x12, 0xf5,
-	0xae, 0xc2, 0x05, 0x69, 0xd4, 0x9e, 0x69, 0xdb, 0x2d, 0xc6, 0xd3, 0xa6, 0xbb, 0xc7, 0x71, 0x72,
-	0x83, 0x2b, 0xcf, 0xc1, 0x99, 0x23, 0x8f, 0xc0, 0x89, 0x1b, 0x4f, 0xc2, 0x13, 0xa0, 0xae, 0xe9,
-	0xb1, 0xc7, 0x3f, 0xc9, 0x66, 0x45, 0x20, 0x37, 0xd7, 0x4f, 0x7f, 0x5d, 0xf5, 0x55, 0x75, 0xd5,
-	0x18, 0x5a, 0xfd, 0xf8, 0xce, 0x44, 0x49, 0x23, 0x89, 0xd7, 0x8f, 0xbb, 0x8f, 0x21, 0x78, 0xc2,
-	0xf4, 0x88, 0xec, 0x80, 0x37, 0xbd, 0x1b, 0xd6, 0x6e, 0xd4, 0x6e, 0x37, 0xa8, 0x37, 0xbd, 0x8b,
-	0xf2, 0xbd, 0xd0, 0x73, 0xf2, 0x3d, 0x94, 0x0f, 0x42, 0xdf, 0xc9, 0x07, 0x28, 0xdf, 0x0f, 0x03,
-	0x27, 0xdf, 0xef, 0x7e, 0x01, 0xcd, 0x67, 0x4a, 0x0e, 0x15, 0x1b, 0x93, 0xeb, 0x00, 0xd3, 0x71,
-	0x34, 0xe5, 0x4a, 0x0b, 0x99, 0x21, 0x64, 0x40, 0xb7, 0xa6, 0xe3, 0xe7, 0x85, 0x82, 0x10, 0x08,
-	0x62, 0x99, 0x70, 0xc4, 0xde, 0xa6, 0xf8, 0xbb, 0xdb, 0x83, 0xe6, 0x23, 0xad, 0xb9, 0xe9, 0x1d,
-	0xfd, 0xeb, 0x40, 0x8e, 0xa1, 0x8d, 0x50, 0x8f, 0xc6, 0x32, 0xcf, 0x0c, 0xf9, 0x18, 0x5a, 0xcc,
-	0x8a, 0x91, 0x48, 0x10, 0xb4, 0x7d, 0xd0, 0xbe, 0xd3, 0x8f, 0xef, 0xb8, 0xdb, 0x68, 0x13, 0x8d,
-	0xbd, 0x84, 0x5c, 0x81, 0x06, 0xc3, 0x13, 0x78, 0x55, 0x40, 0x9d, 0xd4, 0x1d, 0x42, 0x07, 0x7d,
-	0x8f, 0xf8, 0x40, 0x64, 0xc2, 0xd8, 0x04, 0x1e, 0xc2, 0xae, 0xd0, 0x3a, 0x67, 0x59, 0xcc, 0xa3,
-	0x49, 0x91, 0x73, 0x15, 0xda, 0xd1, 0x40, 0x3b, 0xa5, 0x53, 0xc9, 0xcb, 0x35, 0x08, 0x12, 0x66,
-	0x18, 0x5e, 0xd0, 0x3e, 0x68, 0x59, 0x5f, 0x4b, 0x3d, 0x45, 0x6d, 0x37, 0x85, 0xf6, 0x73, 0x96,
-	0xe6, 0xfc, 0x44, 0xe6, 0x2a, 0xe6, 0xe4, 0x2a, 0xf8, 0x8a, 0x0f, 0x1c, 0xee, 0xc2, 0xd7, 0x2a,
-	0xc9, 0x4d, 0xa8, 0x4f, 0xad, 0xab, 0x43, 0xea, 0xcc, 0x13, 0x2a, 0x72, 0xa6, 0x85, 0x95, 0x5c,
-	0x85, 0xd6, 0x44, 0x6a, 0x8c, 0x19, 0xf9, 0x0a, 0xe8, 0x5c, 0xee, 0xfe, 0x08, 0xbb, 0x78, 0xdb,
-	0x11, 0xd7, 0x46, 0x64, 0x0c, 0xf3, 0xfa, 0x8f, 0xaf, 0xfc, 0xd5, 0x87, 0xf6, 0x57, 0xa9, 0x8c,
-	0x7f, 0x78, 0xc2, 0x59, 0xc2, 0x15, 0x09, 0xa1, 0xb9, 0xdc, 0x23, 0xa5, 0x68, 0x6b, 0x31, 0xe2,
-	0x62, 0x38, 0x9a, 0xd7, 0xa2, 0x90, 0xc8, 0x03, 0xd8, 0x9b, 0x28, 0x3e, 0x15, 0x32, 0xd7, 0x51,
-	0xdf, 0x22, 0xd9, 0xa2, 0xfa, 0x2b, 0xe1, 0x76, 0x4a, 0x17, 0xbc, 0xab, 0x97, 0x90, 0x6b, 0xb0,
-	0x65, 0xc4, 0x98, 0x6b, 0xc3, 0xc6, 0x13, 0xec, 0x93, 0x80, 0x2e, 0x14, 0xe4, 0x33, 0xd8, 0x33,
-	0x8a, 0x65, 0x9a, 0xc5, 0x36, 0x48, 0x1d, 0x29, 0x29, 0x4d, 0x58, 0x5f, 0xc1, 0xdc, 0xad, 0xba,
-	0x50, 0x29, 0x0d, 0xf9, 0x12, 0x3e, 0xa8, 0xe8, 0x22, 0x6d, 0x98, 0xc9, 0x75, 0x34, 0x62, 0x7a,
-	0x14, 0x36, 0x56, 0x0e, 0x5f, 0xae, 0x38, 0x9e, 0xa0, 0x1f, 0x3e, 0xb8, 0x5b, 0x00, 0xf6, 0x14,
-	0x2f, 0x6e, 0x6c, 0xae, 0x1c, 0xda, 0x42, 0x1b, 0x5e, 0x75, 0x09, 0xea, 0x99, 0xcc, 0x62, 0x1e,
-	0xb6, 0x30, 0xf6, 0x42, 0xb0, 0xaf, 0xa8, 0x2f, 0x8c, 0x0e, 0xb7, 0x50, 0x89, 0xbf, 0xc9, 0x11,
-	0x90, 0xf5, 0xa0, 0x42, 0x40, 0xe8, 0xcb, 0x16, 0xfa, 0x74, 0x35, 0x12, 0xba, 0xb7, 0x16, 0x5c,
-	0xf7, 0xaf, 0x1a, 0xb4, 0x4e, 0x67, 0x67, 0x16, 0xe9, 0x16, 0x74, 0x34, 0x57, 0x82, 0xa5, 0xe2,
-	0x15, 0x4f, 0x22, 0x2d, 0x5e, 0x71, 0x57, 0xad, 0x9d, 0x85, 0xfa, 0x44, 0xbc, 0xe2, 0x76, 0x1c,
-	0x58, 0xba, 0x23, 0xc5, 0xb2, 0x21, 0x77, 0x5d, 0x81, 0x05, 0xa0, 0x56, 0x61, 0x79, 0x50, 0x5c,
-	0xe7, 0xa9, 0x7d, 0xa1, 0x3a, 0x0c, 0x6e, 0xf8, 0xcb, 0x3c, 0x14, 0xb6, 0x5e, 0xa2, 0xc9, 0x3e,
-	0x34, 0xc6, 0xf9, 0xcc, 0x96, 0x7c, 0xb5, 0x3c, 0xf5, 0x71, 0x3e, 0xc3, 0x42, 0x17, 0xef, 0xab,
-	0xb1, 0xf1, 0x7d, 0x1d, 0x43, 0xfd, 0x74, 0xf6, 0xad, 0x1c, 0xda, 0x94, 0x58, 0x92, 0x28, 0xae,
-	0x35, 0xa6, 0xb4, 0x4d, 0x4b, 0xd1, 0xf6, 0x9d, 0x91, 0x13, 0x11, 0xeb, 0xd0, 0xbb, 0xe1, 0xdf,
-	0xde, 0xa6, 0x4e, 0xb2, 0x5c, 0x23, 0xb0, 0x5f, 0x4c, 0x2c, 0x84, 0x7b, 0x06, 0x3b, 0xa7, 0xb3,
-	0xe7, 0x5c, 0x89, 0xc1, 0x4b, 0x8a, 0x21, 0x92, 0x7d, 0x68, 0xbb, 0x36, 0x18, 0x30, 0x91, 0x22,
-	0x76, 0x8b, 0x42, 0xa1, 0x7a, 0xcc, 0x44, 0x4a, 0xae, 0x43, 0x90, 0xca, 0x61, 0x01, 0xde, 0x3e,
-	0xd8, 0xc2, 0x82, 0xd8, 0x88, 0x28, 0xaa, 0xbb, 0x03, 0xd8, 0x5b, 0xab, 0xcf, 0x1b, 0xf8, 0xff,
-	0x1c, 0x2e, 0x4c, 0xf1, 0xfa, 0xb2, 0xce, 0x05, 0x2c, 0x29, 0x60, 0xab, 0x91, 0xd1, 0xed, 0xc2,
-	0xd1, 0xd5, 0xf7, 0xcf, 0x1a, 0xf8, 0xc7, 0xf9, 0x8c, 0x7c, 0x02, 0x4d, 0x8d, 0xb3, 0xc6, 0xf2,
-	0xe0, 0x97, 0x8f, 0xba, 0x32, 0x83, 0x68, 0x69, 0x27, 0x37, 0xa1, 0x59, 0x0e, 0x3a, 0x6f, 0x7d,
-	0xd0, 0x95, 0x36, 0xf2, 0x0d, 0x5c, 0x7a, 0x21, 0x4c, 0xc6, 0xb5, 0x8e, 0x92, 0xc5, 0x5c, 0xd1,
-	0xa1, 0x8f, 0xf0, 0x97, 0xe6, 0xf0, 0x95, 0xa1, 0x43, 0x2f, 0xba, 0x13, 0x15, 0x9d, 0x26, 0x9f,
-	0xc2, 0x5e, 0x09, 0xc4, 0xd4, 0x30, 0x1f, 0xf3, 0xcc, 0x14, 0xad, 0xb1, 0x4d, 0x77, 0x9d, 0xe1,
-	0x51, 0xa9, 0xef, 0x4a, 0x68, 0x1d, 0x4a, 0x91, 0xf5, 0x99, 0xe6, 0xe4, 0x6b, 0xb8, 0xb8, 0x21,
-	0x02, 0x37, 0xd2, 0x36, 0x07, 0x40, 0xd6, 0x03, 0xb0, 0x23, 0x83, 0xa9, 0xbe, 0x30, 0x8a, 0xa9,
-	0x97, 0x6e, 0x4f, 0x2d, 0x14, 0xdd, 0x9f, 0x6a, 0xd0, 0x78, 0x9a, 0x9b, 0x49, 0x6e, 0xc8, 0x2d,
-	0x68, 0x14, 0x1c, 0xb9, 0x2b, 0xd6, 0x28, 0x74, 0x66, 0xf2, 0x00, 0x3a, 0xb1, 0xcc, 0x8c, 0x92,
-	0x69, 0xf4, 0x06, 0x26, 0x77, 0x9c, 0x4f, 0xb9, 0x31, 0x42, 0x68, 0x4a, 0x95, 0x88, 0x8c, 0xa5,
-	0xee, 0xdd, 0x94, 0x62, 0xf7, 0x29, 0x00, 0xe5, 0x46, 0x28, 0x6e, 0x39, 0x78, 0xfb, 0x30, 0x2a,
-	0x80, 0xde, 0x32, 0xe0, 0xef, 0x1e, 0xb4, 0x7a, 0x6e, 0x61, 0xd9, 0x37, 0x89, 0x53, 0xa6, 0x18,
-	0x68, 0xab, 0x0b, 0x61, 0x0b, 0x6d, 0x38, 0xc4, 0xde, 0x72, 0x2d, 0xbc, 0xa6, 0x2c, 0xfe, 0x39,
-	0xcb, 0x72, 0x0c, 0xe1, 0xbc, 0x2d, 0x70, 0xa7, 0x27, 0xf3, 0xa5, 0x8c, 0x83, 0xbd, 0x7d, 0x70,
-	0x71, 0x1e, 0xc0, 0x62, 0x5f, 0xd3, 0x2b, 0x65, 0xcb, 0xac, 0xec, 0xf1, 0x8d, 0x5d, 0x56, 0xdf,
-	0xdc, 0x65, 0x55, 0xe6, 0x1a, 0xcb, 0xcc, 0xfd, 0x51, 0x83, 0xfa, 0xc9, 0x84, 0x67, 0x09, 0xb9,
-	0x0b, 0x1d, 0x3d, 0xe1, 0x99, 0x89, 0x24, 0x76, 0xc7, 0xe2, 0x93, 0x63, 0xc1, 0xdd, 0x05, 0x74,
-	0x28, 0xba, 0xa7, 0x97, 0xbc, 0x8e, 0x18, 0xef, 0x9c, 0xc4, 0x6c, 0xcc, 0xc4, 0x3f, 0x3b, 0x93,
-	0x60, 0x39, 0x93, 0xef, 0x81, 0x1c, 0xda, 0x06, 0x64, 0x71, 0x95, 0xa6, 0x9b, 0xd0, 0x8c, 0x15,
-	0x67, 0x46, 0xaa, 0x4d, 0x5f, 0x39, 0xa5, 0x8d, 0xec, 0x2f, 0x7d, 0xdd, 0x2c, 0xf9, 0x14, 0x13,
-	0xf3, 0xef, 0x1a, 0xb4, 0x0e, 0xad, 0xb3, 0x05, 0x9d, 0x2f, 0xb5, 0x5a, 0x75, 0xa9, 0xbd, 0x23,
-	0x3a, 0xf6, 0x21, 0x18, 0x28, 0x39, 0x76, 0xfd, 0xb5, 0x1c, 0x8a, 0x35, 0x90, 0x8f, 0xa0, 0x2e,
-	0xb2, 0x49, 0x6e, 0x5c, 0xd7, 0x2c, 0x79, 0x14, 0x96, 0x77, 0xd5, 0x1c, 0xbf, 0x78, 0x10, 0x1c,
-	0xb2, 0x34, 0x7d, 0xcf, 0x09, 0x7f, 0x08, 0x9e, 0x91, 0x9b, 0xb2, 0xf5, 0x8c, 0x5c, 0xb0, 0x51,
-	0x3f, 0x1f, 0x1b, 0x8d, 0xb3, 0xd9, 0x68, 0x2e, 0xb3, 0xf1, 0xb3, 0x67, 0x67, 0x75, 0xd1, 0x61,
-	0xef, 0x99, 0x91, 0x9d, 0x39, 0x23, 0xdb, 0xff, 0x37, 0x09, 0x35, 0x68, 0x1e, 0x71, 0xfc, 0x2c,
-	0x7e, 0x7f, 0xfb, 0xe3, 0x37, 0x0f, 0xe0, 0x3b, 0x61, 0x46, 0x89, 0x62, 0x2f, 0x58, 0xba, 0x09,
-	0xbe, 0x76, 0x36, 0xfc, 0x5b, 0x4e, 0xff, 0x87, 0x60, 0xe9, 0xc1, 0xab, 0xe6, 0xe8, 0x1b, 0xea,
-	0xd2, 0x29, 0x9d, 0x4a, 0xf8, 0xd7, 0xb4, 0x42, 0xf0, 0x2e, 0x86, 0xe3, 0xb9, 0x5f, 0x72, 0xbf,
-	0x81, 0xff, 0x99, 0xef, 0xff, 0x13, 0x00, 0x00, 0xff, 0xff, 0x0d, 0x40, 0xa9, 0x77, 0x3f, 0x0f,
-	0x00, 0x00,
+	// 1261 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x57, 0xcd, 0x6e, 0x1c, 0x45,
+	0x10, 0xd6, 0xce, 0xcc, 0xfe, 0xb8, 0x76, 0xe3, 0xb5, 0xdb, 0x4e, 0x18, 0x42, 0x22, 0x87, 0x95,
+	0x42, 0x12, 0x21, 0x59, 0x89, 0x13, 0xc2, 0x85, 0x03, 0xc6, 0x26, 0xc9, 0x4a, 0x58, 0x41, 0x63,
+	0x13, 0x2e, 0x48, 0xa3, 0xde, 0x99, 0xf6, 0xba, 0xc5, 0xec, 0xf4, 0xd2, 0xdd, 0xb3, 0xd9, 0xe4,
+	0x06, 0x57, 0x9e, 0x83, 0x33, 0x47, 0x1e, 0x81, 0x13, 0x37, 0x9e, 0x84, 0x3b, 0x12, 0xea, 0xea,
+	0x9e, 0xfd, 0x4f, 0x1c, 0x8b, 0x40, 0x6e, 0x5b, 0x3f, 0xf3, 0x55, 0xd5, 0x57, 0xd5, 0xd5, 0xbd,
+	0xd0, 0xe8, 0x25, 0xbb, 0x43, 0x29, 0xb4, 0x20, 0x5e, 0x2f, 0xe9, 0x3c, 0x82, 0xe0, 0x09, 0x55,
+	0x67, 0x64, 0x1d, 0xbc, 0xd1, 0xdd, 0xb0, 0x72, 0xa3, 0x72, 0xbb, 0x16, 0x79, 0xa3, 0xbb, 0x28,
+	0xdf, 0x0b, 0x3d, 0x27, 0xdf, 0x43, 0x79, 0x2f, 0xf4, 0x9d, 0xbc, 0x87, 0xf2, 0xfd, 0x30, 0x70,
+	0xf2, 0xfd, 0xce, 0x67, 0x50, 0xff, 0x5a, 0x8a, 0xbe, 0xa4, 0x03, 0x72, 0x1d, 0x60, 0x34, 0x88,
+	0x47, 0x4c, 0x2a, 0x2e, 0x72, 0x84, 0x0c, 0xa2, 0xb5, 0xd1, 0xe0, 0x99, 0x55, 0x10, 0x02, 0x41,
+	0x22, 0x52, 0x86, 0xd8, 0xad, 0x08, 0x7f, 0x77, 0xba, 0x50, 0xdf, 0x57, 0x8a, 0xe9, 0xee, 0xe1,
+	0xbf, 0x4e, 0xe4, 0x08, 0x9a, 0x08, 0xb5, 0x3f, 0x10, 0x45, 0xae, 0xc9, 0x47, 0xd0, 0xa0, 0x46,
+	0x8c, 0x79, 0x8a, 0xa0, 0xcd, 0xbd, 0xe6, 0x6e, 0x2f, 0xd9, 0x75, 0xd1, 0xa2, 0x3a, 0x1a, 0xbb,
+	0x29, 0xb9, 0x02, 0x35, 0x8a, 0x5f, 0x60, 0xa8, 0x20, 0x72, 0x52, 0xa7, 0x0f, 0x6d, 0xf4, 0x3d,
+	0x64, 0xa7, 0x3c, 0xe7, 0xda, 0x14, 0xf0, 0x10, 0x36, 0xb8, 0x52, 0x05, 0xcd, 0x13, 0x16, 0x0f,
+	0x6d, 0xcd, 0xb3, 0xd0, 0x8e, 0x86, 0xa8, 0x5d, 0x3a, 0x95, 0xbc, 0x5c, 0x83, 0x20, 0xa5, 0x9a,
+	0x62, 0x80, 0xe6, 0x5e, 0xc3, 0xf8, 0x1a, 0xea, 0x23, 0xd4, 0x76, 0x32, 0x68, 0x3e, 0xa3, 0x59,
+	0xc1, 0x8e, 0x45, 0x21, 0x13, 0x46, 0xae, 0x82, 0x2f, 0xd9, 0x69, 0x58, 0x59, 0xf0, 0x35, 0x4a,
+	0x72, 0x13, 0xaa, 0x23, 0xe3, 0xea, 0x90, 0xda, 0x93, 0x82, 0x6c, 0xcd, 0x91, 0xb5, 0x92, 0xab,
+	0xd0, 0x18, 0x0a, 0x85, 0x39, 0x23, 0x5f, 0x41, 0x34, 0x91, 0x3b, 0x3f, 0xc0, 0x06, 0x46, 0x3b,
+	0x64, 0x4a, 0xf3, 0x9c, 0x62, 0x5d, 0xff, 0x71, 0xc8, 0x5f, 0x7c, 0x68, 0x7e, 0x91, 0x89, 0xe4,
+	0xfb, 0x27, 0x8c, 0xa6, 0x4c, 0x92, 0x10, 0xea, 0xf3, 0x33, 0x52, 0x8a, 0xa6, 0x17, 0x67, 0x8c,
+	0xf7, 0xcf, 0x26, 0xbd, 0xb0, 0x12, 0x79, 0x00, 0x9b, 0x43, 0xc9, 0x46, 0x5c, 0x14, 0x2a, 0xee,
+	0x19, 0x24, 0xd3, 0x54, 0x7f, 0x21, 0xdd, 0x76, 0xe9, 0x82, 0xb1, 0xba, 0x29, 0xb9, 0x06, 0x6b,
+	0x9a, 0x0f, 0x98, 0xd2, 0x74, 0x30, 0xc4, 0x39, 0x09, 0xa2, 0xa9, 0x82, 0x7c, 0x02, 0x9b, 0x5a,
+	0xd2, 0x5c, 0xd1, 0xc4, 0x24, 0xa9, 0x62, 0x29, 0x84, 0x0e, 0xab, 0x0b, 0x98, 0x1b, 0xb3, 0x2e,
+	0x91, 0x10, 0x9a, 0x7c, 0x0e, 0xef, 0xcd, 0xe8, 0x62, 0xa5, 0xa9, 0x2e, 0x54, 0x7c, 0x46, 0xd5,
+	0x59, 0x58, 0x5b, 0xf8, 0xf8, 0xf2, 0x8c, 0xe3, 0x31, 0xfa, 0x19, 0x35, 0xb9, 0x05, 0x60, 0xbe,
+	0x62, 0x36, 0x62, 0x7d, 0xe1, 0xa3, 0x35, 0xb4, 0x61, 0xa8, 0x6d, 0xa8, 0xe6, 0x22, 0x4f, 0x58,
+	0xd8, 0xc0, 0xdc, 0xad, 0x60, 0x4e, 0x51, 0x8f, 0x6b, 0x15, 0xae, 0xa1, 0x12, 0x7f, 0x93, 0x43,
+	0x20, 0xcb, 0x49, 0x85, 0x80, 0xd0, 0x97, 0x0d, 0xf4, 0xc9, 0x62, 0x26, 0xd1, 0xe6, 0x52, 0x72,
+	0x9d, 0x3f, 0x2b, 0xd0, 0x38, 0x19, 0x9f, 0xdb, 0xa4, 0x5b, 0xd0, 0x56, 0x4c, 0x72, 0x9a, 0xf1,
+	0x97, 0x2c, 0x8d, 0x15, 0x7f, 0xc9, 0x5c, 0xb7, 0xd6, 0xa7, 0xea, 0x63, 0xfe, 0x92, 0x99, 0x75,
+	0x60, 0xe8, 0x8e, 0x25, 0xcd, 0xfb, 0x2c, 0xf4, 0xa7, 0x0d, 0x88, 0x8c, 0xc2, 0xf0, 0x20, 0x99,
+	0x2a, 0x32, 0x73, 0x42, 0x55, 0x18, 0xdc, 0xf0, 0xe7, 0x79, 0xb0, 0xb6, 0x6e, 0xaa, 0xc8, 0x0e,
+	0xd4, 0x06, 0xc5, 0xd8, 0xb4, 0x7c, 0xb1, 0x3d, 0xd5, 0x41, 0x31, 0xc6, 0x46, 0xdb, 0xf3, 0x55,
+	0x5b, 0x79, 0xbe, 0x8e, 0xa0, 0x7a, 0x32, 0xfe, 0x4a, 0xf4, 0x4d, 0x49, 0x34, 0x4d, 0x25, 0x53,
+	0x0a, 0x4b, 0x6a, 0x45, 0xa5, 0x68, 0xe6, 0x4e, 0x8b, 0x21, 0x4f, 0x54, 0xe8, 0xdd, 0xf0, 0x6f,
+	0xb7, 0x22, 0x27, 0x19, 0xae, 0x11, 0xd8, 0xb7, 0x1b, 0x0b, 0xe1, 0xfe, 0xae, 0xc0, 0xfa, 0xc9,
+	0xf8, 0x19, 0x93, 0xfc, 0xf4, 0x45, 0x84, 0x39, 0x92, 0x1d, 0x68, 0xba, 0x39, 0x38, 0xa5, 0x3c,
+	0x43, 0xf0, 0x46, 0x04, 0x56, 0xf5, 0x88, 0xf2, 0x8c, 0x5c, 0x87, 0x20, 0x13, 0x7d, 0x8b, 0xde,
+	0xdc, 0x5b, 0xc3, 0x8e, 0x98, 0x94, 0x22, 0x54, 0x93, 0xf7, 0xa1, 0xd1, 0xa7, 0x2a, 0x2e, 0x14,
+	0x4b, 0x1d, 0x4d, 0xf5, 0x3e, 0x55, 0xdf, 0x28, 0x96, 0x92, 0x5d, 0xd8, 0x4a, 0x8a, 0x41, 0x91,
+	0x51, 0xcd, 0x47, 0x2c, 0x9e, 0x78, 0xd9, 0x69, 0xde, 0x9c, 0x9a, 0x1e, 0x3b, 0xff, 0x3b, 0xb0,
+	0x91, 0x88, 0x5c, 0x4b, 0x9a, 0xe8, 0xb8, 0x2c, 0xb6, 0x8a, 0xd9, 0xb7, 0x4b, 0xfd, 0xbe, 0x2b,
+	0x7a, 0x1b, 0xaa, 0xbd, 0x4c, 0x88, 0x01, 0xd2, 0xd6, 0x8a, 0xac, 0x60, 0x6a, 0x91, 0x6c, 0xc4,
+	0xa4, 0x8e, 0xb1, 0xf2, 0x3a, 0xda, 0xc0, 0xaa, 0x0e, 0x4d, 0xfd, 0xa7, 0xb0, 0xb9, 0x34, 0x4d,
+	0xaf, 0x99, 0x96, 0x4f, 0xe1, 0xd2, 0x08, 0xb9, 0x2a, 0xa7, 0xd2, 0x72, 0x40, 0x2c, 0x07, 0xb3,
+	0x34, 0x46, 0x2d, 0xeb, 0xe8, 0xa6, 0xf1, 0x8f, 0x0a, 0xf8, 0x47, 0xc5, 0x98, 0xdc, 0x81, 0xba,
+	0xc2, 0xcd, 0x68, 0xba, 0xe6, 0x97, 0x2b, 0x68, 0x66, 0x63, 0x46, 0xa5, 0x9d, 0xdc, 0x84, 0x7a,
+	0xb9, 0x96, 0xbd, 0xe5, 0xb5, 0x5c, 0xda, 0xc8, 0x63, 0xd8, 0x7e, 0xce, 0x75, 0xce, 0x94, 0x8a,
+	0xd3, 0xe9, 0x16, 0x54, 0xa1, 0x8f, 0xf0, 0xdb, 0x13, 0xf8, 0x99, 0x15, 0x19, 0x6d, 0xb9, 0x2f,
+	0x66, 0x74, 0x8a, 0x7c, 0x0c, 0x9b, 0x25, 0x10, 0x95, 0xfd, 0x62, 0xc0, 0x72, 0x6d, 0x07, 0xb9,
+	0x15, 0x6d, 0x38, 0xc3, 0x7e, 0xa9, 0xef, 0x08, 0x68, 0x1c, 0x08, 0x9e, 0xf7, 0xa8, 0x62, 0xe4,
+	0x4b, 0xd8, 0x5a, 0x91, 0x81, 0x5b, 0xc0, 0xab, 0x13, 0x20, 0xcb, 0x09, 0x98, 0x05, 0x47, 0x65,
+	0x8f, 0x6b, 0x49, 0xe5, 0x0b, 0x77, 0xab, 0x4e, 0x15, 0x9d, 0x1f, 0x2b, 0x50, 0x7b, 0x5a, 0xe8,
+	0x61, 0xa1, 0xc9, 0x2d, 0xa8, 0x59, 0x8e, 0x5c, 0x88, 0x25, 0x0a, 0x9d, 0x99, 0x3c, 0x00, 0x3b,
+	0x26, 0x22, 0x8b, 0x5f, 0xc3, 0xe4, 0xba, 0xf3, 0x71, 0xb2, 0xe9, 0xbe, 0x90, 0x29, 0xcf, 0x69,
+	0x56, 0x8e, 0xaf, 0x13, 0x3b, 0x4f, 0x01, 0x22, 0xa6, 0xb9, 0x64, 0x86, 0x83, 0x37, 0x4f, 0x63,
+	0x06, 0xd0, 0x9b, 0x07, 0xfc, 0xcd, 0x83, 0x46, 0xd7, 0x5d, 0xaf, 0x66, 0x83, 0xe0, 0x4e, 0xb4,
+	0xeb, 0x77, 0xf1, 0xfa, 0x5a, 0x43, 0x9b, 0xf9, 0xf9, 0xa6, 0x97, 0xd8, 0x2b, 0xda, 0xe2, 0x5f,
+	0xb0, 0x2d, 0x47, 0x10, 0x4e, 0xc6, 0x02, 0x5f, 0x20, 0xe9, 0xe4, 0x09, 0x81, 0x07, 0xb7, 0xb9,
+	0xb7, 0x35, 0x49, 0x60, 0xfa, 0xba, 0x88, 0xae, 0x94, 0x23, 0x33, 0xaf, 0x5f, 0x3d, 0x65, 0xd5,
+	0xd5, 0x53, 0x36, 0xcb, 0x5c, 0x6d, 0x9e, 0xb9, 0xdf, 0x2b, 0x50, 0x3d, 0x1e, 0xb2, 0x3c, 0x25,
+	0x77, 0xa1, 0xad, 0x86, 0x2c, 0xd7, 0xb1, 0xc0, 0xe9, 0x98, 0x3e, 0x90, 0xa6, 0xdc, 0x5d, 0x42,
+	0x07, 0x3b, 0x3d, 0xdd, 0xf4, 0x55, 0xc4, 0x78, 0x17, 0x24, 0x66, 0x65, 0x25, 0xfe, 0xf9, 0x95,
+	0x04, 0xf3, 0x95, 0x7c, 0x07, 0xe4, 0xc0, 0xed, 0xb2, 0x19, 0x9a, 0x6e, 0x42, 0x3d, 0x91, 0x8c,
+	0x6a, 0x21, 0x57, 0xbd, 0xc9, 0x4a, 0x1b, 0xd9, 0x99, 0x7b, 0x8b, 0xcd, 0xf9, 0xd8, 0xfd, 0xfe,
+	0x57, 0x05, 0x1a, 0x07, 0xc6, 0xd9, 0x80, 0x4e, 0xae, 0xe0, 0xca, 0xec, 0x15, 0xfc, 0x96, 0xe8,
+	0xd8, 0x81, 0xe0, 0x54, 0x8a, 0x41, 0xe8, 0xaf, 0x48, 0xc5, 0x18, 0xc8, 0x87, 0x50, 0xe5, 0xf9,
+	0xb0, 0xd0, 0x61, 0xb0, 0xec, 0x61, 0x2d, 0x6f, 0x6b, 0x38, 0x7e, 0xf6, 0x20, 0x38, 0xa0, 0x59,
+	0xf6, 0x8e, 0x0b, 0xfe, 0x00, 0x3c, 0x2d, 0x56, 0x55, 0xeb, 0x69, 0x31, 0x65, 0xa3, 0x7a, 0x31,
+	0x36, 0x6a, 0xe7, 0xb3, 0x51, 0x9f, 0x67, 0xe3, 0x27, 0x0f, 0x1a, 0xe5, 0x84, 0xbd, 0x63, 0x46,
+	0xd6, 0x27, 0x8c, 0xb4, 0xfe, 0x6f, 0x12, 0x2a, 0x50, 0x3f, 0x64, 0xf8, 0x88, 0x7f, 0x77, 0xf7,
+	0xc7, 0xaf, 0x1e, 0xc0, 0xb7, 0x5c, 0x9f, 0xa5, 0x92, 0x3e, 0xa7, 0xd9, 0x2a, 0xf8, 0xca, 0xf9,
+	0xf0, 0x6f, 0xb8, 0xfd, 0x1f, 0xc2, 0xc6, 0x73, 0x17, 0x6a, 0x82, 0xbe, 0xa2, 0x2f, 0xed, 0xd2,
+	0xa9, 0x84, 0x7f, 0xc5, 0x28, 0x04, 0x6f, 0x63, 0x39, 0x5e, 0xf8, 0x24, 0xf7, 0x6a, 0xf8, 0x0f,
+	0xff, 0xfe, 0x3f, 0x03, 0x00, 0x9a, 0x38, 0x14, 0x4b, 0xed, 0x0f, 0x00, 0x00,
 }
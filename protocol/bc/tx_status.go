@@ -60,9 +60,51 @@ func (ts *TransactionStatus) GetLogs(i int) ([]*TxLog, error) {
 	return ts.VerifyStatus[i].Logs, nil
 }
 
-// WriteTo will write TxVerifyResult struct to io.Writer
+// SetReceipt set the evm receipt fields of the tx at given index, the tx
+// status must be set before
+func (ts *TransactionStatus) SetReceipt(i int, gasUsed, cumulativeGasUsed uint64, contractAddress, bloom []byte) error {
+	if i >= len(ts.VerifyStatus) {
+		return errors.New("SetReceipt is out of range")
+	}
+
+	ts.VerifyStatus[i].GasUsed = gasUsed
+	ts.VerifyStatus[i].CumulativeGasUsed = cumulativeGasUsed
+	ts.VerifyStatus[i].ContractAddress = contractAddress
+	ts.VerifyStatus[i].Bloom = bloom
+	return nil
+}
+
+// SetRevertData set the return data of the failed contract message of the tx
+// at given index, the tx status must be set before
+func (ts *TransactionStatus) SetRevertData(i int, data []byte) error {
+	if i >= len(ts.VerifyStatus) {
+		return errors.New("SetRevertData is out of range")
+	}
+
+	ts.VerifyStatus[i].RevertData = data
+	return nil
+}
+
+// GetVerifyResult get the tx verify result of given index
+func (ts *TransactionStatus) GetVerifyResult(i int) (*TxVerifyResult, error) {
+	if i >= len(ts.VerifyStatus) {
+		return nil, errors.New("GetVerifyResult is out of range")
+	}
+
+	return ts.VerifyStatus[i], nil
+}
+
+// WriteTo will write TxVerifyResult struct to io.Writer. Only the fields
+// committed by the block transaction status hash are written, the receipt
+// fields are recomputed by every node on validation and kept out of the hash.
 func (tvr *TxVerifyResult) WriteTo(w io.Writer) (int64, error) {
-	bytes, err := json.Marshal(tvr)
+	bytes, err := json.Marshal(struct {
+		StatusFail bool     `json:"status_fail,omitempty"`
+		Logs       []*TxLog `json:"logs,omitempty"`
+	}{
+		StatusFail: tvr.StatusFail,
+		Logs:       tvr.Logs,
+	})
 	if err != nil {
 		return 0, err
 	}
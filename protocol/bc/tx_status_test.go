@@ -1,7 +1,10 @@
 package bc
 
 import (
+	"bytes"
 	"testing"
+
+	"github.com/golang/protobuf/proto"
 )
 
 func TestSetBits(t *testing.T) {
@@ -71,3 +74,48 @@ func TestSetBits(t *testing.T) {
 		}
 	}
 }
+
+func TestReceiptNotCommitted(t *testing.T) {
+	ts := NewTransactionStatus()
+	ts.SetLogs(0, []*TxLog{{Address: []byte{1}, Data: []byte{2}}})
+	ts.SetStatus(0, true)
+
+	var before bytes.Buffer
+	if _, err := ts.VerifyStatus[0].WriteTo(&before); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.SetReceipt(0, 100, 200, []byte{3}, []byte{4}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ts.SetReceipt(1, 100, 200, nil, nil); err == nil {
+		t.Error("set receipt of unknown tx should fail")
+	}
+	if err := ts.SetRevertData(0, []byte{5}); err != nil {
+		t.Fatal(err)
+	}
+
+	var after bytes.Buffer
+	if _, err := ts.VerifyStatus[0].WriteTo(&after); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before.Bytes(), after.Bytes()) {
+		t.Errorf("receipt changed the committed status, got %s want %s", after.String(), before.String())
+	}
+
+	data, err := proto.Marshal(ts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &TransactionStatus{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		t.Fatal(err)
+	}
+	result, err := stored.GetVerifyResult(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GasUsed != 100 || result.CumulativeGasUsed != 200 || !bytes.Equal(result.ContractAddress, []byte{3}) || !bytes.Equal(result.Bloom, []byte{4}) || !bytes.Equal(result.RevertData, []byte{5}) {
+		t.Errorf("stored receipt %v", result)
+	}
+}
@@ -16,6 +16,9 @@ var (
 	// ErrBadStateRoot is returned when the computed assets merkle root
 	// disagrees with the one declared in a block header.
 	ErrBadStateRoot = errors.New("invalid state merkle root")
+	// ErrStatePruned is returned when the state of a block is queried after
+	// its trie nodes have been pruned from the db.
+	ErrStatePruned = errors.New("state of the block has been pruned")
 )
 
 // BlockExist check is a block in chain or orphan
@@ -88,7 +91,7 @@ func (c *Chain) connectBlock(block *types.Block) (err error) {
 	}
 
 	node := c.index.GetNode(&bcBlock.ID)
-	if err := c.setState(node, utxoView); err != nil {
+	if err := c.setState(node, utxoView, nil, []*state.BlockNode{node}); err != nil {
 		return err
 	}
 
@@ -99,25 +102,37 @@ func (c *Chain) connectBlock(block *types.Block) (err error) {
 }
 
 func (c *Chain) reorganizeChain(node *state.BlockNode) error {
+	utxoView, err := c.utxoViewAt(node)
+	if err != nil {
+		return err
+	}
+
+	attachNodes, detachNodes := c.calcReorganizeNodes(node)
+	return c.setState(node, utxoView, detachNodes, attachNodes)
+}
+
+// utxoViewAt returns the UTXO view which moves the best chain to the node,
+// the outputs missing from the view are kept by the store
+func (c *Chain) utxoViewAt(node *state.BlockNode) (*state.UtxoViewpoint, error) {
 	attachNodes, detachNodes := c.calcReorganizeNodes(node)
 	utxoView := state.NewUtxoViewpoint()
 
 	for _, detachNode := range detachNodes {
 		b, err := c.store.GetBlock(&detachNode.Hash)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		detachBlock := types.MapBlock(b)
 		if err := c.store.GetTransactionsUtxo(utxoView, detachBlock.Transactions); err != nil {
-			return err
+			return nil, err
 		}
 		txStatus, err := c.GetTransactionStatus(&detachBlock.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := utxoView.DetachBlock(detachBlock, txStatus); err != nil {
-			return err
+			return nil, err
 		}
 
 		log.WithFields(log.Fields{"height": node.Height, "hash": node.Hash.String()}).Debug("detach from mainchain")
@@ -126,25 +141,38 @@ func (c *Chain) reorganizeChain(node *state.BlockNode) error {
 	for _, attachNode := range attachNodes {
 		b, err := c.store.GetBlock(&attachNode.Hash)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		attachBlock := types.MapBlock(b)
 		if err := c.store.GetTransactionsUtxo(utxoView, attachBlock.Transactions); err != nil {
-			return err
+			return nil, err
 		}
 		txStatus, err := c.GetTransactionStatus(&attachBlock.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if err := utxoView.ApplyBlock(attachBlock, txStatus); err != nil {
-			return err
+			return nil, err
 		}
 
 		log.WithFields(log.Fields{"height": node.Height, "hash": node.Hash.String()}).Debug("attach from mainchain")
 	}
+	return utxoView, nil
+}
 
-	return c.setState(node, utxoView)
+// blockUtxoView returns the UTXO view of the node loaded with the outputs the
+// txs spend, it reads the best chain and the UTXO store so it must run on the
+// block processor
+func (c *Chain) blockUtxoView(node *state.BlockNode, txs []*bc.Tx) (*state.UtxoViewpoint, error) {
+	utxoView, err := c.utxoViewAt(node)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.store.GetTransactionsUtxo(utxoView, txs); err != nil {
+		return nil, err
+	}
+	return utxoView, nil
 }
 
 // SaveBlock will validate and save block into storage
@@ -157,7 +185,13 @@ func (c *Chain) saveBlock(block *types.Block) error {
 		return err
 	}
 
-	if err := validation.ValidateBlock(bcBlock, parent, c, stateDB); err != nil {
+	// the contracts of the block see the UTXOs of its parent
+	utxoView, err := c.blockUtxoView(parent, bcBlock.Transactions)
+	if err != nil {
+		return err
+	}
+
+	if err := validation.ValidateBlock(bcBlock, parent, c, utxoView, stateDB); err != nil {
 		return errors.Sub(ErrBadBlock, err)
 	}
 	if err := c.store.SaveBlock(block, bcBlock.TransactionStatus); err != nil {
@@ -213,8 +247,10 @@ type processBlockResponse struct {
 }
 
 type processBlockMsg struct {
-	block *types.Block
-	reply chan processBlockResponse
+	block    *types.Block
+	txStatus *bc.TransactionStatus
+	run      func() error
+	reply    chan processBlockResponse
 }
 
 // ProcessBlock is the entry for chain update
@@ -225,8 +261,36 @@ func (c *Chain) ProcessBlock(block *types.Block) (bool, error) {
 	return response.isOrphan, response.err
 }
 
+// ProcessBlockWithoutState is the entry for the blocks below the pivot of the
+// state sync, the block is saved with the given transaction status instead of
+// executing the transactions, so the state of the block is missing
+func (c *Chain) ProcessBlockWithoutState(block *types.Block, txStatus *bc.TransactionStatus) error {
+	reply := make(chan processBlockResponse, 1)
+	c.processBlockCh <- &processBlockMsg{block: block, txStatus: txStatus, reply: reply}
+	response := <-reply
+	return response.err
+}
+
+// runOnProcessor runs the function on the block processor between the blocks,
+// so that no block moves the best chain and the UTXO store meanwhile
+func (c *Chain) runOnProcessor(run func() error) error {
+	reply := make(chan processBlockResponse, 1)
+	c.processBlockCh <- &processBlockMsg{run: run, reply: reply}
+	response := <-reply
+	return response.err
+}
+
 func (c *Chain) blockProcesser() {
 	for msg := range c.processBlockCh {
+		if msg.run != nil {
+			msg.reply <- processBlockResponse{err: msg.run()}
+			continue
+		}
+		if msg.txStatus != nil {
+			msg.reply <- processBlockResponse{err: c.processBlockWithoutState(msg.block, msg.txStatus)}
+			continue
+		}
+
 		isOrphan, err := c.processBlock(msg.block)
 		msg.reply <- processBlockResponse{isOrphan: isOrphan, err: err}
 	}
@@ -264,3 +328,25 @@ func (c *Chain) processBlock(block *types.Block) (bool, error) {
 	}
 	return false, nil
 }
+
+func (c *Chain) processBlockWithoutState(block *types.Block, txStatus *bc.TransactionStatus) error {
+	if block.PreviousBlockHash != c.bestNode.Hash {
+		return errors.WithDetailf(ErrBadBlock, "block %d not extend the best block %d", block.Height, c.bestNode.Height)
+	}
+
+	bcBlock := types.MapBlock(block)
+	if err := validation.ValidateBlockWithoutState(bcBlock, c.bestNode, txStatus); err != nil {
+		return errors.Sub(ErrBadBlock, err)
+	}
+	if err := c.store.SaveBlock(block, bcBlock.TransactionStatus); err != nil {
+		return err
+	}
+
+	node, err := state.NewBlockNode(&block.BlockHeader, c.bestNode)
+	if err != nil {
+		return err
+	}
+
+	c.index.AddNode(node)
+	return c.connectBlock(block)
+}
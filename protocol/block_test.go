@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/doslink/doslink/config"
@@ -54,3 +55,15 @@ func TestCalcReorganizeNodes(t *testing.T) {
 		t.Errorf("detach nodes want %v but get %v", wantDetachNodes, getDetachNodes)
 	}
 }
+
+func TestRunOnProcessor(t *testing.T) {
+	c := &Chain{processBlockCh: make(chan *processBlockMsg, maxProcessBlockChSize)}
+	go c.blockProcesser()
+	defer close(c.processBlockCh)
+
+	ran := false
+	wantErr := errors.New("run error")
+	if err := c.runOnProcessor(func() error { ran = true; return wantErr }); err != wantErr || !ran {
+		t.Errorf("got ran %v and error %v, want ran and error %v", ran, err, wantErr)
+	}
+}
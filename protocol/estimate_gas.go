@@ -0,0 +1,107 @@
+package protocol
+
+import (
+	"math"
+	"math/big"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+	evm_types "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol/bc/types"
+	"github.com/doslink/doslink/protocol/vm"
+	"github.com/doslink/doslink/protocol/vm/evm"
+	"github.com/doslink/doslink/protocol/vm/state"
+)
+
+// ErrGasLimitExceeded is returned when the message fails even with the max gas of a tx
+var ErrGasLimitExceeded = errors.New("gas required exceeds the max gas of a transaction")
+
+// EstimateGas binary searches the lowest gas limit the message succeeds with
+// on the state of the best block, to is nil for a contract creation. The
+// search starts from the intrinsic gas of the message and every try runs on
+// a snapshot of the state which is reverted afterwards.
+func (c *Chain) EstimateGas(from, to []byte, value uint64, data []byte) (uint64, error) {
+	header := c.BestBlockHeader()
+	stateDB, err := c.StateAt(header)
+	if err != nil {
+		return 0, err
+	}
+
+	intrinsicGas, err := state.IntrinsicGas(data, to == nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sender := evm_common.BytesToAddress(from)
+	amount := new(big.Int).SetUint64(value)
+	stateDB.AddBalance(sender, amount)
+
+	var receiver *evm_common.Address
+	if to != nil {
+		address := evm_common.BytesToAddress(to)
+		receiver = &address
+	}
+
+	// try runs the message with the gas limit, vmErr is the failure of the
+	// message and err the failure of the estimation
+	try := func(gas uint64) (ret []byte, vmErr error, err error) {
+		revision := stateDB.Snapshot()
+		defer stateDB.RevertToSnapshot(revision)
+
+		msg := evm_types.NewMessage(sender, receiver, stateDB.GetNonce(sender), amount, gas, evm_common.Big0, data, false)
+		ret, _, failed, vmErr := applyMessage(c, header, stateDB, msg)
+		if dbErr := stateDB.Error(); dbErr != nil {
+			return nil, nil, StateError(dbErr, header)
+		}
+		if vmErr == evm.ErrInsufficientBalance {
+			return nil, nil, vmErr
+		}
+		if vmErr == nil && failed {
+			vmErr = ErrExecutionReverted
+		}
+		return ret, vmErr, nil
+	}
+
+	lo, hi := intrinsicGas-1, uint64(consensus.MaxGasAmount)
+	ret, vmErr, err := try(hi)
+	if err != nil {
+		return 0, err
+	}
+	if vmErr != nil {
+		return 0, estimateError(ret, vmErr)
+	}
+
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		_, vmErr, err := try(mid)
+		if err != nil {
+			return 0, err
+		}
+
+		if vmErr == nil {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+func applyMessage(c *Chain, header *types.BlockHeader, stateDB *evm_state.StateDB, msg evm_types.Message) ([]byte, uint64, bool, error) {
+	author := msg.From()
+	evmContext := vm.NewEVMContext(msg, header.Height, header.Timestamp, header.Bits, c, &author)
+	evmEnv := evm.NewEVM(evmContext, stateDB, evm.Config{})
+	gp := new(state.GasPool).AddGas(math.MaxUint64)
+	return state.ApplyMessage(evmEnv, msg, gp)
+}
+
+// estimateError describes the failure of the message with the max gas
+func estimateError(ret []byte, vmErr error) error {
+	if vmErr == evm.ErrOutOfGas {
+		return ErrGasLimitExceeded
+	}
+	return RevertError(ret, vmErr)
+}
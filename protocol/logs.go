@@ -0,0 +1,184 @@
+package protocol
+
+import (
+	"bytes"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/state"
+)
+
+const (
+	// MaxLogScanRange is the max number of blocks a log filter without
+	// address and topic can scan block by block.
+	MaxLogScanRange = 1000
+	// MaxLogIndexRange is the max number of blocks a log filter with address
+	// or topic can cover, such filters are served by the log index.
+	MaxLogIndexRange = 100000
+
+	logIndexBackfillSize = 1000
+)
+
+var (
+	// ErrBadLogFilter is returned when the log filter block range is invalid
+	ErrBadLogFilter = errors.New("invalid log filter")
+	// ErrLogRangeTooLarge is returned when a log filter covers more blocks
+	// than MaxLogScanRange or MaxLogIndexRange allows
+	ErrLogRangeTooLarge = errors.New("log filter block range too large")
+)
+
+// LogFilter selects the contract logs within a main chain block range.
+// A log matches if it is emitted by any of the Addresses, and for every
+// position i of Topics, its i-th topic is any of Topics[i]. Empty Addresses
+// or an empty Topics[i] matches anything.
+type LogFilter struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Addresses  [][]byte
+	Topics     [][][]byte
+}
+
+// Match reports whether the log satisfies the filter
+func (f *LogFilter) Match(log *bc.TxLog) bool {
+	if len(f.Addresses) > 0 && !containsBytes(f.Addresses, log.Address) {
+		return false
+	}
+
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range f.Topics {
+		if len(alternatives) > 0 && !containsBytes(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *LogFilter) indexed() bool {
+	if len(f.Addresses) > 0 {
+		return true
+	}
+	for _, alternatives := range f.Topics {
+		if len(alternatives) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func containsBytes(list [][]byte, b []byte) bool {
+	for _, item := range list {
+		if bytes.Equal(item, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// LogEntry is a contract log located in the main chain
+type LogEntry struct {
+	Log         *bc.TxLog
+	BlockHash   bc.Hash
+	BlockHeight uint64
+	TxID        bc.Hash
+	TxIndex     uint32
+	LogIndex    uint32
+}
+
+// GetLogs return the main chain logs matching the filter in chain order
+func (c *Chain) GetLogs(filter *LogFilter) ([]*LogEntry, error) {
+	if filter.FromHeight > filter.ToHeight {
+		return nil, errors.WithDetailf(ErrBadLogFilter, "from height %d is above to height %d", filter.FromHeight, filter.ToHeight)
+	}
+
+	nodes := []*state.BlockNode{}
+	if filter.indexed() {
+		if filter.ToHeight-filter.FromHeight >= MaxLogIndexRange {
+			return nil, errors.WithDetailf(ErrLogRangeTooLarge, "max %d blocks with address or topic", MaxLogIndexRange)
+		}
+
+		hashes, err := c.store.GetLogBlocks(filter.Addresses, filter.Topics, filter.FromHeight, filter.ToHeight)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hash := range hashes {
+			if c.InMainChain(*hash) {
+				nodes = append(nodes, c.index.GetNode(hash))
+			}
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Height < nodes[j].Height })
+	} else {
+		if filter.ToHeight-filter.FromHeight >= MaxLogScanRange {
+			return nil, errors.WithDetailf(ErrLogRangeTooLarge, "max %d blocks without address or topic", MaxLogScanRange)
+		}
+
+		for height := filter.FromHeight; height <= filter.ToHeight; height++ {
+			node := c.index.NodeByHeight(height)
+			if node == nil {
+				break
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	entries := []*LogEntry{}
+	for _, node := range nodes {
+		block, err := c.store.GetBlock(&node.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		txStatus, err := c.store.GetTransactionStatus(&node.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		logIndex := uint32(0)
+		for i, result := range txStatus.VerifyStatus {
+			for _, log := range result.Logs {
+				if filter.Match(log) {
+					entries = append(entries, &LogEntry{
+						Log:         log,
+						BlockHash:   node.Hash,
+						BlockHeight: node.Height,
+						TxID:        block.Transactions[i].ID,
+						TxIndex:     uint32(i),
+						LogIndex:    logIndex,
+					})
+				}
+				logIndex++
+			}
+		}
+	}
+	return entries, nil
+}
+
+// backfillLogIndex indexes the logs of the main chain blocks saved before the
+// log index existed. It runs once before the chain processes blocks, and
+// resumes from the last saved batch if the node stopped halfway.
+func (c *Chain) backfillLogIndex() error {
+	indexState := c.store.GetLogIndexState()
+	if indexState == nil {
+		indexState = &LogIndexState{}
+	}
+
+	for !indexState.Done {
+		nodes := []*state.BlockNode{}
+		height := indexState.Height
+		for ; height <= c.bestNode.Height && len(nodes) < logIndexBackfillSize; height++ {
+			nodes = append(nodes, c.index.NodeByHeight(height))
+		}
+
+		indexState = &LogIndexState{Height: height, Done: height > c.bestNode.Height}
+		if err := c.store.BackfillLogIndex(nodes, indexState); err != nil {
+			return err
+		}
+		log.WithField("height", height).Debug("log index backfilled")
+	}
+	return nil
+}
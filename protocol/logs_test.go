@@ -0,0 +1,119 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/config"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/state"
+	"github.com/doslink/doslink/testutil"
+)
+
+func TestLogFilterMatch(t *testing.T) {
+	log := &bc.TxLog{
+		Address: []byte("contract-a"),
+		Topics:  [][]byte{[]byte("transfer"), []byte("alice"), []byte("bob")},
+	}
+
+	cases := []struct {
+		filter *LogFilter
+		match  bool
+	}{
+		{filter: &LogFilter{}, match: true},
+		{filter: &LogFilter{Addresses: [][]byte{[]byte("contract-b"), []byte("contract-a")}}, match: true},
+		{filter: &LogFilter{Addresses: [][]byte{[]byte("contract-b")}}, match: false},
+		{filter: &LogFilter{Topics: [][][]byte{{[]byte("transfer")}}}, match: true},
+		{filter: &LogFilter{Topics: [][][]byte{{}, {[]byte("bob"), []byte("alice")}}}, match: true},
+		{filter: &LogFilter{Topics: [][][]byte{{}, {[]byte("bob")}}}, match: false},
+		{filter: &LogFilter{Topics: [][][]byte{{}, {}, {}, {}}}, match: false},
+	}
+
+	for i, c := range cases {
+		if got := c.filter.Match(log); got != c.match {
+			t.Errorf("case %d: got %v, want %v", i, got, c.match)
+		}
+	}
+}
+
+func TestGetLogsRange(t *testing.T) {
+	chain := &Chain{}
+	cases := []struct {
+		filter *LogFilter
+		err    error
+	}{
+		{filter: &LogFilter{FromHeight: 2, ToHeight: 1}, err: ErrBadLogFilter},
+		{filter: &LogFilter{ToHeight: MaxLogScanRange}, err: ErrLogRangeTooLarge},
+		{filter: &LogFilter{ToHeight: MaxLogIndexRange, Addresses: [][]byte{[]byte("contract-a")}}, err: ErrLogRangeTooLarge},
+		{filter: &LogFilter{ToHeight: MaxLogIndexRange, Topics: [][][]byte{{[]byte("transfer")}}}, err: ErrLogRangeTooLarge},
+	}
+
+	for i, c := range cases {
+		if _, err := chain.GetLogs(c.filter); errors.Root(err) != c.err {
+			t.Errorf("case %d: got error %v, want %v", i, err, c.err)
+		}
+	}
+}
+
+type backfillStore struct {
+	mockStore
+	indexState *LogIndexState
+	batches    [][]uint64
+}
+
+func (s *backfillStore) GetLogIndexState() *LogIndexState { return s.indexState }
+
+func (s *backfillStore) BackfillLogIndex(nodes []*state.BlockNode, lis *LogIndexState) error {
+	heights := []uint64{}
+	for _, node := range nodes {
+		heights = append(heights, node.Height)
+	}
+	s.batches = append(s.batches, heights)
+	s.indexState = lis
+	return nil
+}
+
+func TestBackfillLogIndex(t *testing.T) {
+	chain := &Chain{index: state.NewBlockIndex()}
+	header := config.GenesisBlock().BlockHeader
+	var node *state.BlockNode
+	for i := 0; i <= 2*logIndexBackfillSize; i++ {
+		header.Height = uint64(i)
+		var err error
+		if node, err = state.NewBlockNode(&header, node); err != nil {
+			t.Fatal(err)
+		}
+		chain.index.AddNode(node)
+	}
+	chain.bestNode = node
+	chain.index.SetMainChain(node)
+
+	// the first and last heights of every backfilled batch
+	cases := []struct {
+		indexState *LogIndexState
+		batches    [][2]uint64
+	}{
+		{indexState: nil, batches: [][2]uint64{{0, 999}, {1000, 1999}, {2000, 2000}}},
+		{indexState: &LogIndexState{Height: 1500}, batches: [][2]uint64{{1500, 2000}}},
+		{indexState: &LogIndexState{Height: 2001, Done: true}, batches: [][2]uint64{}},
+	}
+
+	for i, c := range cases {
+		store := &backfillStore{indexState: c.indexState}
+		chain.store = store
+		if err := chain.backfillLogIndex(); err != nil {
+			t.Fatal(err)
+		}
+
+		if want := (&LogIndexState{Height: 2001, Done: true}); !testutil.DeepEqual(store.indexState, want) {
+			t.Errorf("case %d: got log index state %v, want %v", i, store.indexState, want)
+		}
+		got := [][2]uint64{}
+		for _, heights := range store.batches {
+			got = append(got, [2]uint64{heights[0], heights[len(heights)-1]})
+		}
+		if !testutil.DeepEqual(got, c.batches) {
+			t.Errorf("case %d: got batches %v, want %v", i, got, c.batches)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package protocol
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/doslink/doslink/protocol/bc/types"
+)
+
+var mempoolPrefix = []byte("MEMPOOL:")
+
+// savedTx is a pool transaction saved to the db at the shutdown, the orphans
+// keep their expiration
+type savedTx struct {
+	Tx         *types.Tx `json:"tx"`
+	Added      time.Time `json:"added"`
+	Orphan     bool      `json:"orphan"`
+	Expiration time.Time `json:"expiration"`
+}
+
+// Save writes the transactions and the orphans of the pool into the db, they
+// are reloaded by Chain.RestoreTxPool on the next start
+func (tp *TxPool) Save() error {
+	tp.mtx.RLock()
+	defer tp.mtx.RUnlock()
+
+	db := tp.store.DB()
+	batch := db.NewBatch()
+	iter := db.IteratorPrefix(mempoolPrefix)
+	for iter.Next() {
+		batch.Delete(iter.Key())
+	}
+	iter.Release()
+
+	save := func(saved *savedTx) error {
+		data, err := json.Marshal(saved)
+		if err != nil {
+			return err
+		}
+
+		batch.Set(append(append([]byte{}, mempoolPrefix...), saved.Tx.ID.Bytes()...), data)
+		return nil
+	}
+
+	for _, txD := range tp.pool {
+		if err := save(&savedTx{Tx: txD.Tx, Added: txD.Added}); err != nil {
+			return err
+		}
+	}
+	for _, orphan := range tp.orphans {
+		if err := save(&savedTx{Tx: orphan.Tx, Added: orphan.Added, Orphan: true, Expiration: orphan.expiration}); err != nil {
+			return err
+		}
+	}
+
+	batch.Write()
+	log.WithFields(log.Fields{"txs": len(tp.pool), "orphans": len(tp.orphans)}).Info("save the transaction pool")
+	return nil
+}
+
+// loadSaved return the saved transactions in the order they were added and
+// deletes them from the db
+func (tp *TxPool) loadSaved() ([]*savedTx, error) {
+	db := tp.store.DB()
+	batch := db.NewBatch()
+	iter := db.IteratorPrefix(mempoolPrefix)
+	defer iter.Release()
+
+	txs := []*savedTx{}
+	for iter.Next() {
+		batch.Delete(iter.Key())
+		saved := &savedTx{}
+		if err := json.Unmarshal(iter.Value(), saved); err != nil {
+			return nil, err
+		}
+		txs = append(txs, saved)
+	}
+
+	batch.Write()
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Added.Before(txs[j].Added) })
+	return txs, nil
+}
+
+func (tp *TxPool) setOrphanExpiration(saved *savedTx) {
+	tp.mtx.Lock()
+	defer tp.mtx.Unlock()
+
+	if orphan, ok := tp.orphans[saved.Tx.ID]; ok {
+		orphan.expiration = saved.Expiration
+	}
+}
+
+// RestoreTxPool validates the transactions saved by TxPool.Save against the
+// current best block and adds the valid ones back to the pool. The restored
+// transactions are announced as new pool transactions, the expired orphans
+// are dropped.
+func (c *Chain) RestoreTxPool() error {
+	txs, err := c.txPool.loadSaved()
+	if err != nil {
+		return err
+	}
+
+	now, restored := time.Now(), 0
+	for _, saved := range txs {
+		if saved.Orphan && saved.Expiration.Before(now) {
+			continue
+		}
+
+		acceptable, height, gasStatus, err := c.ValidateTx(saved.Tx)
+		if !acceptable {
+			log.WithFields(log.Fields{"tx_id": saved.Tx.ID.String(), "err": err}).Debug("drop saved tx of the transaction pool")
+			continue
+		}
+
+		isOrphan, err := c.txPool.ProcessTransaction(saved.Tx, err != nil, height, gasStatus.AssetValue)
+		if err != nil {
+			log.WithFields(log.Fields{"tx_id": saved.Tx.ID.String(), "err": err}).Debug("drop saved tx of the transaction pool")
+			continue
+		}
+
+		if isOrphan && saved.Orphan {
+			c.txPool.setOrphanExpiration(saved)
+		}
+		restored++
+	}
+
+	log.WithFields(log.Fields{"saved": len(txs), "restored": restored}).Info("restore the transaction pool")
+	return nil
+}
@@ -0,0 +1,50 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/protocol/bc"
+)
+
+func TestSaveTxPool(t *testing.T) {
+	tp := NewTxPool(&stateStore{db: dbm.NewMemDB()})
+	if err := tp.addTransaction(&TxDesc{Tx: testTxs[2]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tp.addTransaction(&TxDesc{Tx: testTxs[3]}); err != nil {
+		t.Fatal(err)
+	}
+	expiration := time.Unix(1633489701, 0)
+	if err := tp.addOrphan(&TxDesc{Tx: testTxs[4]}, []*bc.Hash{&testTxs[4].SpentOutputIDs[0]}); err != nil {
+		t.Fatal(err)
+	}
+	tp.orphans[testTxs[4].ID].expiration = expiration
+
+	if err := tp.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := tp.loadSaved()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 3 {
+		t.Fatalf("got %d saved txs, want 3", len(saved))
+	}
+	for _, s := range saved {
+		if s.Tx.ID == testTxs[4].ID {
+			if !s.Orphan || !s.Expiration.Equal(expiration) {
+				t.Errorf("saved orphan %v", s)
+			}
+		} else if s.Orphan || !tp.IsTransactionInPool(&s.Tx.ID) {
+			t.Errorf("saved tx %v", s)
+		}
+	}
+
+	if saved, err := tp.loadSaved(); err != nil || len(saved) != 0 {
+		t.Errorf("saved txs are not deleted after load: %v, %v", saved, err)
+	}
+}
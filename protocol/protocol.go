@@ -15,10 +15,12 @@ import (
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/state"
+	"github.com/doslink/doslink/protocol/vm/evm"
 	vm_state "github.com/doslink/doslink/protocol/vm/state"
 
 	evm_common "github.com/ethereum/go-ethereum/common"
 	evm_state "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
 const maxProcessBlockChSize = 1024
@@ -60,6 +62,10 @@ func NewChain(store Store, txPool *TxPool) (*Chain, error) {
 
 	c.bestNode = c.index.GetNode(storeStatus.Hash)
 	c.index.SetMainChain(c.bestNode)
+	if err := c.backfillLogIndex(); err != nil {
+		return nil, err
+	}
+
 	go c.blockProcesser()
 	return c, nil
 }
@@ -122,7 +128,7 @@ func (c *Chain) initChainStatus() error {
 	if err != nil {
 		return err
 	}
-	return c.store.SaveChainStatus(node, utxoView)
+	return c.store.SaveChainStatus(node, utxoView, nil, []*state.BlockNode{node})
 }
 
 // BestBlockHeight returns the current height of the blockchain.
@@ -169,8 +175,8 @@ func (c *Chain) CalcNextBits(preBlock *bc.Hash) (uint64, error) {
 }
 
 // This function must be called with mu lock in above level
-func (c *Chain) setState(node *state.BlockNode, view *state.UtxoViewpoint) error {
-	if err := c.store.SaveChainStatus(node, view); err != nil {
+func (c *Chain) setState(node *state.BlockNode, view *state.UtxoViewpoint, detachNodes, attachNodes []*state.BlockNode) error {
+	if err := c.store.SaveChainStatus(node, view, detachNodes, attachNodes); err != nil {
 		return err
 	}
 
@@ -223,6 +229,25 @@ func (c *Chain) CurrentState() (*evm_state.StateDB, error) {
 	return NewState(&c.BestBlockHeader().StateRoot, c)
 }
 
+// StateAt open the state of the given block, ErrStatePruned is returned when
+// the trie nodes of the block state are no longer in the db
+func (c *Chain) StateAt(header *types.BlockHeader) (*evm_state.StateDB, error) {
+	stateDB, err := NewState(&header.StateRoot, c)
+	if err != nil {
+		return nil, StateError(err, header)
+	}
+	return stateDB, nil
+}
+
+// StateError convert the missing trie node error met when reading the state
+// of the given block to ErrStatePruned, the other errors are returned as is
+func StateError(err error, header *types.BlockHeader) error {
+	if _, ok := err.(*trie.MissingNodeError); ok {
+		return errors.WithDetailf(ErrStatePruned, "block height %d, %s", header.Height, err.Error())
+	}
+	return err
+}
+
 func (c *Chain) GetAccountNonce(address []byte) (uint64, error) {
 	stateDB, err := c.CurrentState()
 	if err != nil {
@@ -239,12 +264,14 @@ func (c *Chain) GetAccountBalance(address []byte) (*big.Int, error) {
 	return stateDB.GetBalance(evm_common.BytesToAddress(address)), nil
 }
 
-func (c *Chain) CanTransfer(address []byte, amount *big.Int) (bool, error) {
+// CanTransfer checks whether the EVM account of the address holds enough of the
+// asset to withdraw the amount
+func (c *Chain) CanTransfer(address []byte, assetID *bc.AssetID, amount *big.Int) (bool, error) {
 	stateDB, err := c.CurrentState()
 	if err != nil {
 		return false, err
 	}
-	return stateDB.GetBalance(evm_common.BytesToAddress(address)).Cmp(amount) >= 0, nil
+	return evm.CanTransferAsset(stateDB, evm_common.Hash(assetID.Byte32()), evm_common.BytesToAddress(address), amount), nil
 }
 
 func (c *Chain) BestBlockInfo() (height, timestamp, difficulty uint64) {
@@ -253,6 +280,25 @@ func (c *Chain) BestBlockInfo() (height, timestamp, difficulty uint64) {
 	return c.bestNode.Height, c.bestNode.Timestamp, c.bestNode.Bits
 }
 
+// HasUtxo reports whether the output is unspent on the best chain
+func (c *Chain) HasUtxo(outputID [32]byte) bool {
+	hash := bc.NewHash(outputID)
+	entry, err := c.store.GetUtxo(&hash)
+	return err == nil && !entry.Spent
+}
+
+// GetAncestorHash returns the hash of the ancestor at the height of the block,
+// it is zero once the block is unknown or below the height
+func (c *Chain) GetAncestorHash(blockHash [32]byte, height uint64) [32]byte {
+	hash := bc.NewHash(blockHash)
+	for node := c.index.GetNode(&hash); node != nil && node.Height >= height; node = node.Parent {
+		if node.Height == height {
+			return node.Hash.Byte32()
+		}
+	}
+	return bc.Hash{}.Byte32()
+}
+
 func (c *Chain) GetBlockHashByHeight(height uint64) [32]byte {
 	if header, _ := c.GetHeaderByHeight(height); header != nil {
 		return header.Hash().Byte32()
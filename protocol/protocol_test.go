@@ -11,7 +11,10 @@ import (
 	evm_common "github.com/ethereum/go-ethereum/common"
 	evm_state "github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
 	"bytes"
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/bc/types"
 )
 
 func TestChain_initChainStatus(t *testing.T) {
@@ -68,3 +71,18 @@ func TestChain_initChainStatus(t *testing.T) {
 		}
 	}
 }
+
+func TestStateError(t *testing.T) {
+	header := &types.BlockHeader{Height: 10}
+	if err := StateError(&trie.MissingNodeError{}, header); errors.Root(err) != ErrStatePruned {
+		t.Errorf("missing trie node got %v, want %v", err, ErrStatePruned)
+	}
+
+	otherErr := errors.New("other error")
+	if err := StateError(otherErr, header); err != otherErr {
+		t.Errorf("other error got %v, want %v", err, otherErr)
+	}
+	if err := StateError(nil, header); err != nil {
+		t.Errorf("nil error got %v", err)
+	}
+}
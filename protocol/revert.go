@@ -0,0 +1,24 @@
+package protocol
+
+import (
+	"encoding/hex"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/vm/state"
+)
+
+// ErrExecutionReverted is returned when the contract reverts the message
+var ErrExecutionReverted = errors.New("execution reverted")
+
+// RevertError describes the failed contract message, the decoded revert
+// reason or else the raw return data is given as the detail. A failure
+// without return data is returned as is.
+func RevertError(ret []byte, vmErr error) error {
+	if reason, ok := state.UnpackRevert(ret); ok {
+		return errors.WithDetailf(ErrExecutionReverted, "reason: %s", reason)
+	}
+	if len(ret) > 0 {
+		return errors.WithDetailf(ErrExecutionReverted, "return data: %s", hex.EncodeToString(ret))
+	}
+	return vmErr
+}
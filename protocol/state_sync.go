@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+	vm_state "github.com/doslink/doslink/protocol/vm/state"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrBadStateNode is returned when a state node of the state sync is not
+// requested by the sync, which means it is not part of the synced state.
+var ErrBadStateNode = errors.New("invalid state node")
+
+// StateSync downloads the EVM state trie of a block, every node is verified
+// by its hash against the trie rooted at the state root of the block header
+type StateSync struct {
+	sched   *trie.Sync
+	db      *vm_state.EvmDbWrapper
+	pending map[bc.Hash]bool
+	retry   []bc.Hash
+}
+
+// NewStateSync create the state sync of the block
+func (c *Chain) NewStateSync(header *types.BlockHeader) *StateSync {
+	db := vm_state.NewEvmDbWrapper(c.store.DB())
+	return &StateSync{
+		sched:   evm_state.NewStateSync(evm_common.Hash(header.StateRoot.Byte32()), db),
+		db:      db,
+		pending: make(map[bc.Hash]bool),
+	}
+}
+
+// Missing return at most max hashes of the state nodes to download, the
+// requested nodes which are not delivered yet are returned again
+func (s *StateSync) Missing(max int) []bc.Hash {
+	hashes := s.retry
+	if len(hashes) > max {
+		hashes, s.retry = hashes[:max], hashes[max:]
+	} else {
+		s.retry = nil
+	}
+
+	if len(hashes) < max {
+		for _, hash := range s.sched.Missing(max - len(hashes)) {
+			hashes = append(hashes, bc.NewHash(hash))
+		}
+	}
+	for _, hash := range hashes {
+		s.pending[hash] = true
+	}
+	return hashes
+}
+
+// Pending return the count of the state nodes waiting for download
+func (s *StateSync) Pending() int {
+	return s.sched.Pending()
+}
+
+// Process verifies the downloaded state nodes and writes them into the db,
+// the nodes requested by Missing but not delivered are scheduled again
+func (s *StateSync) Process(nodes [][]byte) error {
+	results := make([]trie.SyncResult, 0, len(nodes))
+	for _, node := range nodes {
+		hash := crypto.Keccak256Hash(node)
+		if !s.pending[bc.NewHash(hash)] {
+			return errors.WithDetailf(ErrBadStateNode, "state node %x is not requested", hash.Bytes())
+		}
+
+		delete(s.pending, bc.NewHash(hash))
+		results = append(results, trie.SyncResult{Hash: hash, Data: node})
+	}
+
+	if _, i, err := s.sched.Process(results); err != nil {
+		return errors.WithDetailf(ErrBadStateNode, "state node %x: %v", results[i].Hash.Bytes(), err)
+	}
+
+	batch := s.db.NewBatch()
+	if _, err := s.sched.Commit(batch); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	for hash := range s.pending {
+		s.retry = append(s.retry, hash)
+	}
+	s.pending = make(map[bc.Hash]bool)
+	return nil
+}
+
+// GetStateNode return the encoded state trie node or contract code of the hash
+func (c *Chain) GetStateNode(hash *bc.Hash) ([]byte, error) {
+	stateDB := evm_state.NewDatabase(vm_state.NewEvmDbWrapper(c.store.DB()))
+	return stateDB.TrieDB().Node(evm_common.Hash(hash.Byte32()))
+}
+
+// HasState check whether the state trie of the block is in the db, the state
+// is missing for the blocks saved by ProcessBlockWithoutState until the state
+// sync of the last one is finished
+func (c *Chain) HasState(header *types.BlockHeader) bool {
+	_, err := NewState(&header.StateRoot, c)
+	return err == nil
+}
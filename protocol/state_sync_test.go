@@ -0,0 +1,96 @@
+package protocol
+
+import (
+	"math/big"
+	"testing"
+
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+	vm_state "github.com/doslink/doslink/protocol/vm/state"
+
+	evm_common "github.com/ethereum/go-ethereum/common"
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+)
+
+type stateStore struct {
+	mockStore
+	db dbm.DB
+}
+
+func (s *stateStore) DB() dbm.DB { return s.db }
+
+func TestStateSync(t *testing.T) {
+	source := &Chain{store: &stateStore{db: dbm.NewMemDB()}}
+	stateDB, err := evm_state.New(evm_common.Hash{}, evm_state.NewDatabase(vm_state.NewEvmDbWrapper(source.store.DB())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(1); i <= 100; i++ {
+		address := evm_common.BytesToAddress([]byte{i})
+		stateDB.AddBalance(address, big.NewInt(int64(i)))
+		if i%10 == 0 {
+			stateDB.SetCode(address, []byte{0x60, i})
+			stateDB.SetState(address, evm_common.Hash{i}, evm_common.Hash{i})
+		}
+	}
+	root, err := stateDB.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stateDB.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+
+	header := &types.BlockHeader{StateRoot: bc.NewHash(root)}
+	target := &Chain{store: &stateStore{db: dbm.NewMemDB()}}
+	if target.HasState(header) {
+		t.Fatal("target has the state before sync")
+	}
+
+	stateSync := target.NewStateSync(header)
+	if err := stateSync.Process([][]byte{{0x01}}); errors.Root(err) != ErrBadStateNode {
+		t.Fatalf("process unrequested node error = %v, want %v", err, ErrBadStateNode)
+	}
+
+	for stateSync.Pending() > 0 {
+		// deliver half of the nodes to check the missing ones are requested again
+		hashes := stateSync.Missing(16)
+		nodes := [][]byte{}
+		for i, hash := range hashes {
+			if i%2 == 1 && len(hashes) > 1 {
+				continue
+			}
+
+			node, err := source.GetStateNode(&hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nodes = append(nodes, node)
+		}
+
+		if err := stateSync.Process(nodes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !target.HasState(header) {
+		t.Fatal("target miss the state after sync")
+	}
+	syncedDB, err := NewState(&header.StateRoot, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := evm_common.BytesToAddress([]byte{50})
+	if balance := syncedDB.GetBalance(address); balance.Int64() != 50 {
+		t.Errorf("synced balance %v, want 50", balance)
+	}
+	if code := syncedDB.GetCode(address); len(code) != 2 || code[1] != 50 {
+		t.Errorf("synced code %x", code)
+	}
+	if value := syncedDB.GetState(address, evm_common.Hash{50}); value != (evm_common.Hash{50}) {
+		t.Errorf("synced storage %x", value)
+	}
+}
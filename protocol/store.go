@@ -13,14 +13,18 @@ type Store interface {
 	BlockExist(*bc.Hash) bool
 
 	GetBlock(*bc.Hash) (*types.Block, error)
+	GetLogBlocks(addresses [][]byte, topics [][][]byte, fromHeight, toHeight uint64) ([]*bc.Hash, error)
+	GetLogIndexState() *LogIndexState
 	GetStoreStatus() *BlockStoreState
 	GetTransactionStatus(*bc.Hash) (*bc.TransactionStatus, error)
+	GetTransactionLocations(*bc.Hash) ([]*TxLocation, error)
 	GetTransactionsUtxo(*state.UtxoViewpoint, []*bc.Tx) error
 	GetUtxo(*bc.Hash) (*storage.UtxoEntry, error)
 
 	LoadBlockIndex() (*state.BlockIndex, error)
 	SaveBlock(*types.Block, *bc.TransactionStatus) error
-	SaveChainStatus(*state.BlockNode, *state.UtxoViewpoint) error
+	SaveChainStatus(node *state.BlockNode, view *state.UtxoViewpoint, detachNodes, attachNodes []*state.BlockNode) error
+	BackfillLogIndex([]*state.BlockNode, *LogIndexState) error
 
 	DB() dbm.DB
 }
@@ -30,3 +34,16 @@ type BlockStoreState struct {
 	Height uint64
 	Hash   *bc.Hash
 }
+
+// LogIndexState represents how far the log index has been backfilled with
+// the main chain blocks saved before it existed
+type LogIndexState struct {
+	Height uint64
+	Done   bool
+}
+
+// TxLocation is the position of a transaction in a stored block
+type TxLocation struct {
+	BlockHash bc.Hash
+	Position  uint32
+}
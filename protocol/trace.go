@@ -0,0 +1,97 @@
+package protocol
+
+import (
+	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/bc/types"
+	"github.com/doslink/doslink/protocol/state"
+	"github.com/doslink/doslink/protocol/validation"
+	"github.com/doslink/doslink/protocol/vm/evm"
+)
+
+// TxTrace is the result of re-executing a mined transaction
+type TxTrace struct {
+	BlockHash  bc.Hash
+	Position   uint32
+	GasUsed    uint64
+	StatusFail bool
+	Err        error // the validation error of a status fail tx
+}
+
+// TraceTransaction re-executes the main chain tx on the state of its parent
+// block, the former txs of the block are replayed first. newTracer builds the
+// tracer of the tx from the state the tx runs on, the state is left at the
+// result of the tx so that the tracer can compare it once the call returns.
+func (c *Chain) TraceTransaction(txID *bc.Hash, newTracer func(evm.StateDB) evm.Tracer) (*TxTrace, error) {
+	location, err := c.GetTransactionLocation(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := c.GetBlockByHash(&location.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if block.Height == 0 {
+		return nil, errors.WithDetail(ErrTxNotFound, "the genesis transactions can't be traced")
+	}
+
+	parent, err := c.GetHeaderByHash(&block.PreviousBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	stateDB, err := c.StateAt(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	bcBlock := types.MapBlock(block)
+	var utxoView *state.UtxoViewpoint
+	if err := c.runOnProcessor(func() (err error) {
+		utxoView, err = c.blockUtxoView(c.index.GetNode(&block.PreviousBlockHash), bcBlock.Transactions)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	chain := validation.NewUtxoViewChain(c, utxoView)
+	gasPool := validation.NewBlockGasPool()
+	for i, tx := range bcBlock.Transactions[:location.Position+1] {
+		evmConfig := evm.Config{}
+		if i == int(location.Position) {
+			evmConfig = evm.Config{Debug: true, Tracer: newTracer(stateDB)}
+		}
+
+		revision := stateDB.Snapshot()
+		stateDB.Prepare(tx.ID.Byte32(), bcBlock.ID.Byte32(), i)
+		vs, err := validation.TraceTx(tx, bcBlock, chain, stateDB, gasPool, evmConfig)
+		if !vs.GasState().GasValid {
+			return nil, errors.Wrapf(err, "replay of transaction %d of %d", i, len(bcBlock.Transactions))
+		}
+		if err != nil {
+			stateDB.RevertToSnapshot(revision)
+		}
+		stateDB.Finalise(true)
+
+		if dbErr := stateDB.Error(); dbErr != nil {
+			return nil, StateError(dbErr, parent)
+		}
+		if i > 0 {
+			if applyErr := utxoView.ApplyTransaction(bcBlock, tx, err != nil); applyErr != nil {
+				return nil, applyErr
+			}
+		}
+
+		if i == int(location.Position) {
+			return &TxTrace{
+				BlockHash:  location.BlockHash,
+				Position:   location.Position,
+				GasUsed:    uint64(vs.GasState().GasUsed),
+				StatusFail: err != nil,
+				Err:        err,
+			}, nil
+		}
+	}
+	return nil, errors.WithDetailf(ErrTxNotFound, "transaction %s", txID.String())
+}
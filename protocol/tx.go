@@ -8,14 +8,34 @@ import (
 	"github.com/doslink/doslink/protocol/validation"
 )
 
-// ErrBadTx is returned for transactions failing validation
-var ErrBadTx = errors.New("invalid transaction")
+var (
+	// ErrBadTx is returned for transactions failing validation
+	ErrBadTx = errors.New("invalid transaction")
+	// ErrTxNotFound is returned when no main chain block includes the tx
+	ErrTxNotFound = errors.New("can't find the transaction in the main chain")
+)
 
 // GetTransactionStatus return the transaction status of give block
 func (c *Chain) GetTransactionStatus(hash *bc.Hash) (*bc.TransactionStatus, error) {
 	return c.store.GetTransactionStatus(hash)
 }
 
+// GetTransactionLocation return the position of the tx in the main chain,
+// blocks of side chains that also include the tx are skipped
+func (c *Chain) GetTransactionLocation(txID *bc.Hash) (*TxLocation, error) {
+	locations, err := c.store.GetTransactionLocations(txID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, location := range locations {
+		if c.InMainChain(location.BlockHash) {
+			return location, nil
+		}
+	}
+	return nil, errors.WithDetailf(ErrTxNotFound, "transaction %s", txID.String())
+}
+
 // GetTransactionsUtxo return all the utxos that related to the txs' inputs
 func (c *Chain) GetTransactionsUtxo(view *state.UtxoViewpoint, txs []*bc.Tx) error {
 	return c.store.GetTransactionsUtxo(view, txs)
@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"errors"
+	"math/big"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -22,10 +23,12 @@ const (
 )
 
 var (
-	maxCachedErrTxs = 1000
-	maxMsgChSize    = 1000
-	maxNewTxNum     = 10000
-	maxOrphanNum    = 2000
+	maxCachedErrTxs    = 1000
+	maxMsgChSize       = 1000
+	maxNewTxNum        = 10000
+	maxOrphanNum       = 2000
+	maxAccountTxNum    = 100
+	replaceFeeRateBump = uint64(10)
 
 	orphanTTL                = 10 * time.Minute
 	orphanExpireScanInterval = 3 * time.Minute
@@ -33,8 +36,13 @@ var (
 	// ErrTransactionNotExist is the pre-defined error message
 	ErrTransactionNotExist = errors.New("transaction are not existed in the mempool")
 	// ErrPoolIsFull indicates the pool is full
-	ErrPoolIsFull = errors.New("transaction pool reach the max number")
+	ErrPoolIsFull          = errors.New("transaction pool reach the max number")
 	ErrTransactionIsInPool = errors.New("transaction is existed in the mempool")
+	// ErrAccountLimit indicates the account has too many transactions in the pool
+	ErrAccountLimit = errors.New("account reach the max number of transactions in the pool")
+	// ErrReplaceUnderpriced indicates the fee rate of a replacement is not
+	// high enough to replace the transaction of the same sender nonce
+	ErrReplaceUnderpriced = errors.New("replacement transaction underpriced")
 )
 
 // TxDesc store tx and related info for mining strategy
@@ -47,6 +55,69 @@ type TxDesc struct {
 	Fee        uint64
 }
 
+// CmpFeeRate compares the fee per weight of the two transactions
+func (txD *TxDesc) CmpFeeRate(other *TxDesc) int {
+	return cmpFeeRate(txD, other, 0)
+}
+
+func (txD *TxDesc) weight() uint64 {
+	if txD.Weight == 0 {
+		return 1
+	}
+	return txD.Weight
+}
+
+// cmpFeeRate compares the fee rate of a with the fee rate of b raised by the
+// bump percent
+func cmpFeeRate(a, b *TxDesc, bump uint64) int {
+	x := new(big.Int).Mul(new(big.Int).SetUint64(a.Fee), new(big.Int).SetUint64(b.weight()))
+	x.Mul(x, big.NewInt(100))
+	y := new(big.Int).Mul(new(big.Int).SetUint64(b.Fee), new(big.Int).SetUint64(a.weight()))
+	y.Mul(y, new(big.Int).SetUint64(100+bump))
+	return x.Cmp(y)
+}
+
+// SenderNonce return the sender and the nonce of a contract transaction, ok
+// is false for the other transactions
+func (txD *TxDesc) SenderNonce() (sender string, nonce uint64, ok bool) {
+	key, ok := txSenderNonce(txD.Tx)
+	return key.sender, key.nonce, ok
+}
+
+// senderNonce identifies the contract transactions of a sender, only one of
+// them is kept in the pool for each nonce
+type senderNonce struct {
+	sender string
+	nonce  uint64
+}
+
+// txAccount return the control program of the first input, it is the
+// account the pool limits the transactions of
+func txAccount(tx *types.Tx) string {
+	if len(tx.Inputs) == 0 {
+		return ""
+	}
+	if key, ok := txSenderNonce(tx); ok {
+		return key.sender
+	}
+	return string(tx.Inputs[0].ControlProgram())
+}
+
+// txSenderNonce return the sender nonce of the first contract input
+func txSenderNonce(tx *types.Tx) (senderNonce, bool) {
+	for _, input := range tx.Inputs {
+		switch inp := input.TypedInput.(type) {
+		case *types.CreationInput:
+			return senderNonce{sender: string(inp.ControlProgram), nonce: inp.Nonce}, true
+		case *types.CallInput:
+			return senderNonce{sender: string(inp.ControlProgram), nonce: inp.Nonce}, true
+		case *types.ContractInput:
+			return senderNonce{sender: string(inp.ControlProgram), nonce: inp.Nonce}, true
+		}
+	}
+	return senderNonce{}, false
+}
+
 // TxPoolMsg is use for notify pool changes
 type TxPoolMsg struct {
 	*TxDesc
@@ -67,6 +138,8 @@ type TxPool struct {
 	utxo          map[bc.Hash]*types.Tx
 	orphans       map[bc.Hash]*orphanTx
 	orphansByPrev map[bc.Hash]map[bc.Hash]*orphanTx
+	accountTxs    map[string]int
+	nonceTxs      map[senderNonce]*TxDesc
 	errCache      *lru.Cache
 	msgCh         chan *TxPoolMsg
 }
@@ -80,6 +153,8 @@ func NewTxPool(store Store) *TxPool {
 		utxo:          make(map[bc.Hash]*types.Tx),
 		orphans:       make(map[bc.Hash]*orphanTx),
 		orphansByPrev: make(map[bc.Hash]map[bc.Hash]*orphanTx),
+		accountTxs:    make(map[string]int),
+		nonceTxs:      make(map[senderNonce]*TxDesc),
 		errCache:      lru.New(maxCachedErrTxs),
 		msgCh:         make(chan *TxPoolMsg, maxMsgChSize),
 	}
@@ -129,19 +204,9 @@ func (tp *TxPool) RemoveTransaction(txHash *bc.Hash) {
 	tp.mtx.Lock()
 	defer tp.mtx.Unlock()
 
-	txD, ok := tp.pool[*txHash]
-	if !ok {
-		return
-	}
-
-	for _, output := range txD.Tx.ResultIds {
-		delete(tp.utxo, *output)
+	if txD, ok := tp.pool[*txHash]; ok {
+		tp.removeTransaction(txD)
 	}
-	delete(tp.pool, *txHash)
-
-	atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
-	tp.msgCh <- &TxPoolMsg{TxDesc: txD, MsgType: MsgRemoveTx}
-	log.WithField("tx_id", txHash).Debug("remove tx from mempool")
 }
 
 // GetTransaction return the TxDesc by hash
@@ -237,14 +302,47 @@ func (tp *TxPool) addOrphan(txD *TxDesc, requireParents []*bc.Hash) error {
 	return nil
 }
 
+// addTransaction adds the transaction to the pool. A contract transaction
+// replaces the one of the same sender nonce if its fee rate is high enough,
+// and the transaction with the lowest fee rate is evicted once the pool is
+// full.
 func (tp *TxPool) addTransaction(txD *TxDesc) error {
+	tx := txD.Tx
+	key, hasNonce := txSenderNonce(tx)
+	var replaced *TxDesc
+	if hasNonce {
+		replaced = tp.nonceTxs[key]
+	}
+	if replaced != nil && cmpFeeRate(txD, replaced, replaceFeeRateBump) < 0 {
+		return ErrReplaceUnderpriced
+	}
+
+	account := txAccount(tx)
+	if tp.accountTxs[account] >= maxAccountTxNum && (replaced == nil || txAccount(replaced.Tx) != account) {
+		return ErrAccountLimit
+	}
+
+	if replaced != nil {
+		tp.evictTransaction(replaced)
+		log.WithFields(log.Fields{"tx_id": replaced.Tx.ID.String(), "replacement": tx.ID.String()}).Debug("replace tx in mempool")
+	}
+
 	if len(tp.pool) >= maxNewTxNum {
-		return ErrPoolIsFull
+		lowest := tp.lowestFeeRateTx(tp.poolAncestors(tx))
+		if lowest == nil || cmpFeeRate(txD, lowest, 0) <= 0 {
+			return ErrPoolIsFull
+		}
+
+		tp.evictTransaction(lowest)
+		log.WithField("tx_id", lowest.Tx.ID.String()).Debug("evict tx from mempool")
 	}
 
-	tx := txD.Tx
 	txD.Added = time.Now()
 	tp.pool[tx.ID] = txD
+	tp.accountTxs[account]++
+	if hasNonce {
+		tp.nonceTxs[key] = txD
+	}
 	for _, id := range tx.ResultIds {
 		output, err := tx.Output(*id)
 		if err != nil {
@@ -262,6 +360,88 @@ func (tp *TxPool) addTransaction(txD *TxDesc) error {
 	return nil
 }
 
+// evictTransaction removes the transaction and all the pool transactions
+// depend on it
+func (tp *TxPool) evictTransaction(txD *TxDesc) {
+	tp.removeTransaction(txD)
+	for _, output := range txD.Tx.ResultIds {
+		for _, desc := range tp.pool {
+			for _, spent := range desc.Tx.SpentOutputIDs {
+				if spent == *output {
+					tp.evictTransaction(desc)
+					break
+				}
+			}
+		}
+	}
+}
+
+// poolAncestors return the pool transactions the transaction depends on, the
+// ones spent by it and the contract transactions of the lower sender nonces
+func (tp *TxPool) poolAncestors(tx *types.Tx) map[bc.Hash]bool {
+	ancestors := make(map[bc.Hash]bool)
+	for queue := []*types.Tx{tx}; len(queue) > 0; queue = queue[1:] {
+		parents := []*types.Tx{}
+		for _, spent := range queue[0].SpentOutputIDs {
+			if parent, ok := tp.utxo[spent]; ok {
+				parents = append(parents, parent)
+			}
+		}
+		if key, ok := txSenderNonce(queue[0]); ok && key.nonce > 0 {
+			if parent, ok := tp.nonceTxs[senderNonce{sender: key.sender, nonce: key.nonce - 1}]; ok {
+				parents = append(parents, parent.Tx)
+			}
+		}
+
+		for _, parent := range parents {
+			if !ancestors[parent.ID] {
+				ancestors[parent.ID] = true
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return ancestors
+}
+
+// lowestFeeRateTx return the pool transaction with the lowest fee rate, the
+// excluded ones are skipped
+func (tp *TxPool) lowestFeeRateTx(excluded map[bc.Hash]bool) *TxDesc {
+	var lowest *TxDesc
+	for _, txD := range tp.pool {
+		if excluded[txD.Tx.ID] {
+			continue
+		}
+		if lowest == nil || txD.CmpFeeRate(lowest) < 0 {
+			lowest = txD
+		}
+	}
+	return lowest
+}
+
+func (tp *TxPool) removeTransaction(txD *TxDesc) {
+	tx := txD.Tx
+	if _, ok := tp.pool[tx.ID]; !ok {
+		return
+	}
+
+	for _, output := range tx.ResultIds {
+		delete(tp.utxo, *output)
+	}
+	delete(tp.pool, tx.ID)
+
+	account := txAccount(tx)
+	if tp.accountTxs[account]--; tp.accountTxs[account] <= 0 {
+		delete(tp.accountTxs, account)
+	}
+	if key, ok := txSenderNonce(tx); ok && tp.nonceTxs[key] == txD {
+		delete(tp.nonceTxs, key)
+	}
+
+	atomic.StoreInt64(&tp.lastUpdated, time.Now().Unix())
+	tp.msgCh <- &TxPoolMsg{TxDesc: txD, MsgType: MsgRemoveTx}
+	log.WithField("tx_id", tx.ID.String()).Debug("remove tx from mempool")
+}
+
 func (tp *TxPool) checkOrphanUtxos(tx *types.Tx) ([]*bc.Hash, error) {
 	view := state.NewUtxoViewpoint()
 	if err := tp.store.GetTransactionsUtxo(view, []*bc.Tx{tx.Tx}); err != nil {
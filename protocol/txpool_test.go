@@ -72,12 +72,25 @@ func (s *mockStore) BlockExist(hash *bc.Hash) bool
 func (s *mockStore) GetBlock(*bc.Hash) (*types.Block, error)                      { return nil, nil }
 func (s *mockStore) GetStoreStatus() *BlockStoreState                             { return nil }
 func (s *mockStore) GetTransactionStatus(*bc.Hash) (*bc.TransactionStatus, error) { return nil, nil }
+func (s *mockStore) GetTransactionLocations(*bc.Hash) ([]*TxLocation, error)      { return nil, nil }
 func (s *mockStore) GetTransactionsUtxo(*state.UtxoViewpoint, []*bc.Tx) error     { return nil }
 func (s *mockStore) GetUtxo(*bc.Hash) (*storage.UtxoEntry, error)                 { return nil, nil }
 func (s *mockStore) LoadBlockIndex() (*state.BlockIndex, error)                   { return nil, nil }
 func (s *mockStore) SaveBlock(*types.Block, *bc.TransactionStatus) error          { return nil }
-func (s *mockStore) SaveChainStatus(*state.BlockNode, *state.UtxoViewpoint) error { return nil }
-func (s *mockStore) DB() dbm.DB { return nil }
+func (s *mockStore) DB() dbm.DB                                                   { return nil }
+func (s *mockStore) GetLogIndexState() *LogIndexState                             { return nil }
+
+func (s *mockStore) SaveChainStatus(*state.BlockNode, *state.UtxoViewpoint, []*state.BlockNode, []*state.BlockNode) error {
+	return nil
+}
+
+func (s *mockStore) BackfillLogIndex([]*state.BlockNode, *LogIndexState) error {
+	return nil
+}
+
+func (s *mockStore) GetLogBlocks([][]byte, [][][]byte, uint64, uint64) ([]*bc.Hash, error) {
+	return nil, nil
+}
 
 func TestAddOrphan(t *testing.T) {
 	cases := []struct {
@@ -214,9 +227,11 @@ func TestAddTransaction(t *testing.T) {
 	}{
 		{
 			before: &TxPool{
-				pool:  map[bc.Hash]*TxDesc{},
-				utxo:  map[bc.Hash]*types.Tx{},
-				msgCh: make(chan *TxPoolMsg, 1),
+				pool:       map[bc.Hash]*TxDesc{},
+				utxo:       map[bc.Hash]*types.Tx{},
+				accountTxs: map[string]int{},
+				nonceTxs:   map[senderNonce]*TxDesc{},
+				msgCh:      make(chan *TxPoolMsg, 1),
 			},
 			after: &TxPool{
 				pool: map[bc.Hash]*TxDesc{
@@ -237,9 +252,11 @@ func TestAddTransaction(t *testing.T) {
 		},
 		{
 			before: &TxPool{
-				pool:  map[bc.Hash]*TxDesc{},
-				utxo:  map[bc.Hash]*types.Tx{},
-				msgCh: make(chan *TxPoolMsg, 1),
+				pool:       map[bc.Hash]*TxDesc{},
+				utxo:       map[bc.Hash]*types.Tx{},
+				accountTxs: map[string]int{},
+				nonceTxs:   map[senderNonce]*TxDesc{},
+				msgCh:      make(chan *TxPoolMsg, 1),
 			},
 			after: &TxPool{
 				pool: map[bc.Hash]*TxDesc{
@@ -342,8 +359,10 @@ func TestProcessOrphans(t *testing.T) {
 	}{
 		{
 			before: &TxPool{
-				pool: map[bc.Hash]*TxDesc{},
-				utxo: map[bc.Hash]*types.Tx{},
+				pool:       map[bc.Hash]*TxDesc{},
+				utxo:       map[bc.Hash]*types.Tx{},
+				accountTxs: map[string]int{},
+				nonceTxs:   map[senderNonce]*TxDesc{},
 				orphans: map[bc.Hash]*orphanTx{
 					testTxs[3].ID: &orphanTx{
 						TxDesc: &TxDesc{
@@ -373,6 +392,9 @@ func TestProcessOrphans(t *testing.T) {
 					*testTxs[3].ResultIds[0]: testTxs[3],
 					*testTxs[3].ResultIds[1]: testTxs[3],
 				},
+				accountTxs: map[string]int{
+					string([]byte{0x61}): 1,
+				},
 				orphans:       map[bc.Hash]*orphanTx{},
 				orphansByPrev: map[bc.Hash]map[bc.Hash]*orphanTx{},
 			},
@@ -380,8 +402,10 @@ func TestProcessOrphans(t *testing.T) {
 		},
 		{
 			before: &TxPool{
-				pool: map[bc.Hash]*TxDesc{},
-				utxo: map[bc.Hash]*types.Tx{},
+				pool:       map[bc.Hash]*TxDesc{},
+				utxo:       map[bc.Hash]*types.Tx{},
+				accountTxs: map[string]int{},
+				nonceTxs:   map[senderNonce]*TxDesc{},
 				orphans: map[bc.Hash]*orphanTx{
 					testTxs[3].ID: &orphanTx{
 						TxDesc: &TxDesc{
@@ -429,6 +453,10 @@ func TestProcessOrphans(t *testing.T) {
 					*testTxs[4].ResultIds[0]: testTxs[4],
 					*testTxs[4].ResultIds[1]: testTxs[4],
 				},
+				accountTxs: map[string]int{
+					string([]byte{0x61}): 1,
+					string([]byte{0x62}): 1,
+				},
 				orphans:       map[bc.Hash]*orphanTx{},
 				orphansByPrev: map[bc.Hash]map[bc.Hash]*orphanTx{},
 			},
@@ -557,3 +585,103 @@ func TestRemoveOrphan(t *testing.T) {
 		}
 	}
 }
+
+func mockFeeTx(program byte, nonce uint64, fee uint64) *TxDesc {
+	tx := types.NewTx(types.TxData{
+		Inputs: []*types.TxInput{
+			types.NewCallInput([]byte{program}, nonce, []byte{0x01}, nil, nil),
+			types.NewSpendInput(nil, bc.NewHash([32]byte{program, byte(nonce), byte(fee)}), *consensus.NativeAssetID, fee, 0, []byte{program}),
+		},
+		Outputs: []*types.TxOutput{
+			types.NewTxOutput(*consensus.NativeAssetID, 1, []byte{program}),
+		},
+	})
+	return &TxDesc{Tx: tx, Weight: 100, Fee: fee}
+}
+
+func TestAddTransactionFeeRate(t *testing.T) {
+	defer func(poolSize, accountSize int) {
+		maxNewTxNum, maxAccountTxNum = poolSize, accountSize
+	}(maxNewTxNum, maxAccountTxNum)
+	maxNewTxNum, maxAccountTxNum = 3, 2
+
+	tp := NewTxPool(&mockStore{})
+	for _, txD := range []*TxDesc{mockFeeTx(0x51, 0, 100), mockFeeTx(0x51, 1, 200), mockFeeTx(0x52, 0, 300)} {
+		if err := tp.addTransaction(txD); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tp.addTransaction(mockFeeTx(0x51, 2, 400)); err != ErrAccountLimit {
+		t.Errorf("account limit error = %v, want %v", err, ErrAccountLimit)
+	}
+	if err := tp.addTransaction(mockFeeTx(0x51, 1, 210)); err != ErrReplaceUnderpriced {
+		t.Errorf("replace underpriced error = %v, want %v", err, ErrReplaceUnderpriced)
+	}
+
+	replacement := mockFeeTx(0x51, 1, 250)
+	if err := tp.addTransaction(replacement); err != nil {
+		t.Fatal(err)
+	}
+	if len(tp.pool) != 3 || tp.nonceTxs[senderNonce{sender: string([]byte{0x51}), nonce: 1}] != replacement {
+		t.Errorf("pool after replacement %v", tp.pool)
+	}
+
+	if err := tp.addTransaction(mockFeeTx(0x53, 0, 50)); err != ErrPoolIsFull {
+		t.Errorf("pool full error = %v, want %v", err, ErrPoolIsFull)
+	}
+	evicted := mockFeeTx(0x51, 0, 100)
+	if err := tp.addTransaction(mockFeeTx(0x53, 0, 150)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tp.pool) != 3 || tp.IsTransactionInPool(&evicted.Tx.ID) || tp.accountTxs[string([]byte{0x51})] != 1 {
+		t.Errorf("pool after eviction %v", tp.pool)
+	}
+}
+
+func TestAddTransactionEvictSkipAncestors(t *testing.T) {
+	defer func(poolSize int) { maxNewTxNum = poolSize }(maxNewTxNum)
+	maxNewTxNum = 2
+
+	// the contract tx of the next nonce depends on the cheapest one
+	tp := NewTxPool(&mockStore{})
+	parent, other := mockFeeTx(0x51, 0, 100), mockFeeTx(0x52, 0, 200)
+	for _, txD := range []*TxDesc{parent, other} {
+		if err := tp.addTransaction(txD); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tp.addTransaction(mockFeeTx(0x51, 1, 300)); err != nil {
+		t.Fatal(err)
+	}
+	if !tp.IsTransactionInPool(&parent.Tx.ID) || tp.IsTransactionInPool(&other.Tx.ID) {
+		t.Errorf("pool after nonce child added %v", tp.pool)
+	}
+
+	// the tx spending the output of the cheapest one
+	tp = NewTxPool(&mockStore{})
+	parent, other = mockFeeTx(0x51, 0, 100), mockFeeTx(0x52, 0, 200)
+	for _, txD := range []*TxDesc{parent, other} {
+		if err := tp.addTransaction(txD); err != nil {
+			t.Fatal(err)
+		}
+	}
+	output, err := parent.Tx.Output(*parent.Tx.ResultIds[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := types.NewTx(types.TxData{
+		Inputs: []*types.TxInput{
+			types.NewSpendInput(nil, *output.Source.Ref, *consensus.NativeAssetID, 1, 0, []byte{0x51}),
+		},
+		Outputs: []*types.TxOutput{
+			types.NewTxOutput(*consensus.NativeAssetID, 1, []byte{0x53}),
+		},
+	})
+	if err := tp.addTransaction(&TxDesc{Tx: child, Weight: 100, Fee: 300}); err != nil {
+		t.Fatal(err)
+	}
+	if !tp.IsTransactionInPool(&parent.Tx.ID) || tp.IsTransactionInPool(&other.Tx.ID) {
+		t.Errorf("pool after utxo child added %v", tp.pool)
+	}
+}
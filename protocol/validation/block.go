@@ -75,21 +75,64 @@ func ValidateBlockHeader(b *bc.Block, parent *state.BlockNode) error {
 	return nil
 }
 
-// ValidateBlock validates a block and the transactions within.
-func ValidateBlock(b *bc.Block, parent *state.BlockNode, chain vm.ChainContext, stateDB *evm_state.StateDB) error {
+// ValidateBlockWithoutState validates a block of the state sync against the
+// given transaction status, the transactions are not executed.
+func ValidateBlockWithoutState(b *bc.Block, parent *state.BlockNode, txStatus *bc.TransactionStatus) error {
+	if err := ValidateBlockHeader(b, parent); err != nil {
+		return err
+	}
+	if len(txStatus.VerifyStatus) != len(b.Transactions) {
+		return errors.WithDetailf(errMismatchedMerkleRoot, "transaction status count %d, transaction count %d", len(txStatus.VerifyStatus), len(b.Transactions))
+	}
+
+	txMerkleRoot, err := types.TxMerkleRoot(b.Transactions)
+	if err != nil {
+		return errors.Wrap(err, "computing transaction id merkle root")
+	}
+	if txMerkleRoot != *b.TransactionsRoot {
+		return errors.WithDetailf(errMismatchedMerkleRoot, "transaction id merkle root")
+	}
+
+	txStatusHash, err := types.TxStatusMerkleRoot(txStatus.VerifyStatus)
+	if err != nil {
+		return errors.Wrap(err, "computing transaction status merkle root")
+	}
+	if txStatusHash != *b.TransactionStatusHash {
+		return errors.WithDetailf(errMismatchedMerkleRoot, "transaction status merkle root")
+	}
+
+	b.TransactionStatus = committedTxStatus(txStatus)
+	return nil
+}
+
+// committedTxStatus copies the fields of the transaction status committed by
+// the status hash, the receipt fields of a peer are not trusted.
+func committedTxStatus(txStatus *bc.TransactionStatus) *bc.TransactionStatus {
+	committed := &bc.TransactionStatus{Version: txStatus.Version}
+	for _, tvr := range txStatus.VerifyStatus {
+		committed.VerifyStatus = append(committed.VerifyStatus, &bc.TxVerifyResult{StatusFail: tvr.StatusFail, Logs: tvr.Logs})
+	}
+	return committed
+}
+
+// ValidateBlock validates a block and the transactions within, the view
+// holds the UTXOs of the parent block which the block spends.
+func ValidateBlock(b *bc.Block, parent *state.BlockNode, chain vm.ChainContext, view *state.UtxoViewpoint, stateDB *evm_state.StateDB) error {
 	if err := ValidateBlockHeader(b, parent); err != nil {
 		return err
 	}
 
 	blockGasSum := uint64(0)
+	gasPool := NewBlockGasPool()
 	coinbaseAmount := consensus.BlockSubsidy(b.BlockHeader.Height)
 	b.TransactionStatus = bc.NewTransactionStatus()
+	chain = NewUtxoViewChain(chain, view)
 
 	for i, tx := range b.Transactions {
 		gasOnlyTx := false
 		revision := stateDB.Snapshot()
 		stateDB.Prepare(tx.ID.Byte32(), b.ID.Byte32(), i)
-		vs, err := ValidateTx(tx, b, chain, stateDB)
+		vs, err := ValidateBlockTx(tx, b, chain, stateDB, gasPool)
 		gasStatus := vs.gasStatus
 		if !gasStatus.GasValid {
 			return errors.Wrapf(err, "validate of transaction %d of %d", i, len(b.Transactions))
@@ -103,27 +146,22 @@ func ValidateBlock(b *bc.Block, parent *state.BlockNode, chain vm.ChainContext,
 		}
 		stateDB.Finalise(true)
 
-		var txLogs []*bc.TxLog
-		for _, log := range stateDB.GetLogs(tx.ID.Byte32()) {
-			var topics [][]byte
-			for _, topic := range log.Topics {
-				topics = append(topics, topic.Bytes())
+		// the outputs of the coinbase are immature in the block, and they are
+		// unknown to the miner before the fees of the block are summed
+		if i > 0 {
+			if err := view.ApplyTransaction(b, tx, gasOnlyTx); err != nil {
+				return errors.Wrapf(err, "apply transaction %d of %d", i, len(b.Transactions))
 			}
-			txLogs = append(txLogs,
-				&bc.TxLog{
-					Address: log.Address.Bytes(),
-					Topics:  topics,
-					Data:    log.Data,
-				},
-			)
 		}
 
-		b.TransactionStatus.SetLogs(i, txLogs)
-		b.TransactionStatus.SetStatus(i, gasOnlyTx)
 		coinbaseAmount += gasStatus.AssetValue
 		if blockGasSum += uint64(gasStatus.GasUsed); blockGasSum > consensus.MaxBlockGas {
 			return errOverBlockLimit
 		}
+
+		if err := SetTxReceipt(b.TransactionStatus, i, tx, stateDB, gasOnlyTx, uint64(gasStatus.GasUsed), blockGasSum, vs); err != nil {
+			return err
+		}
 	}
 
 	if err := checkCoinbaseAmount(b, coinbaseAmount); err != nil {
@@ -153,3 +191,24 @@ func ValidateBlock(b *bc.Block, parent *state.BlockNode, chain vm.ChainContext,
 
 	return nil
 }
+
+// utxoViewChain looks up the UTXOs of the chain context in the view of the
+// block being validated first
+type utxoViewChain struct {
+	vm.ChainContext
+	view *state.UtxoViewpoint
+}
+
+// NewUtxoViewChain returns the chain context of the txs of a block, HasUtxo
+// resolves against the view which the former txs of the block are applied to
+// and the outputs missing from the view fall back to the chain.
+func NewUtxoViewChain(chain vm.ChainContext, view *state.UtxoViewpoint) vm.ChainContext {
+	return &utxoViewChain{ChainContext: chain, view: view}
+}
+
+func (c *utxoViewChain) HasUtxo(outputID [32]byte) bool {
+	if entry, ok := c.view.Entries[bc.NewHash(outputID)]; ok {
+		return !entry.Spent
+	}
+	return c.ChainContext.HasUtxo(outputID)
+}
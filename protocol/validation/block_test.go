@@ -4,7 +4,7 @@ import (
 	"testing"
 
 	"github.com/doslink/doslink/consensus"
-	"github.com/doslink/doslink/mining/tensority"
+	"github.com/doslink/doslink/database/storage"
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/state"
@@ -91,12 +91,6 @@ func TestCheckCoinbaseAmount(t *testing.T) {
 }
 
 func TestValidateBlockHeader(t *testing.T) {
-	// add (hash, seed) --> (tensority hash) to the  tensority cache for avoid
-	// real matrix calculate cost.
-	tensority.AIHash.AddCache(&bc.Hash{V0: 0}, &bc.Hash{}, testutil.MaxHash)
-	tensority.AIHash.AddCache(&bc.Hash{V0: 1}, &bc.Hash{}, testutil.MinHash)
-	tensority.AIHash.AddCache(&bc.Hash{V0: 1}, consensus.InitialSeed, testutil.MinHash)
-
 	cases := []struct {
 		block  *bc.Block
 		parent *state.BlockNode
@@ -162,8 +156,9 @@ func TestValidateBlockHeader(t *testing.T) {
 			err: errWorkProof,
 		},
 		{
+			// sha3(id || seed) of the block is within the target of the bits
 			block: &bc.Block{
-				ID: bc.Hash{V0: 1},
+				ID: bc.Hash{V0: 32},
 				BlockHeader: &bc.BlockHeader{
 					Height:          1,
 					Timestamp:       1523352601,
@@ -188,3 +183,96 @@ func TestValidateBlockHeader(t *testing.T) {
 		}
 	}
 }
+
+// utxoChain keeps the UTXO set of the best chain
+type utxoChain struct {
+	mockChain
+	utxos map[[32]byte]bool
+}
+
+func (c utxoChain) HasUtxo(outputID [32]byte) bool {
+	return c.utxos[outputID]
+}
+
+func TestUtxoViewChain(t *testing.T) {
+	spent := types.NewSpendInput(nil, bc.Hash{V0: 1}, *consensus.NativeAssetID, 100, 0, []byte{0x51})
+	tx := types.NewTx(types.TxData{
+		Version: 1,
+		Inputs:  []*types.TxInput{spent},
+		Outputs: []*types.TxOutput{types.NewTxOutput(*consensus.NativeAssetID, 90, []byte{0x51})},
+	})
+	spentID := tx.SpentOutputIDs[0]
+	outputID := *tx.ResultIds[0]
+	detachedID := bc.Hash{V0: 2}
+	bestID := bc.Hash{V0: 3}
+
+	// the view moves the best chain to the parent of the block, the detached
+	// output is spent on the parent even if it is unspent on the best chain
+	view := state.NewUtxoViewpoint()
+	view.Entries[spentID] = storage.NewUtxoEntry(false, 1, false)
+	view.Entries[detachedID] = storage.NewUtxoEntry(false, 0, true)
+	chain := NewUtxoViewChain(utxoChain{utxos: map[[32]byte]bool{
+		detachedID.Byte32(): true,
+		bestID.Byte32():     true,
+	}}, view)
+
+	cases := []struct {
+		outputID bc.Hash
+		before   bool
+		after    bool
+	}{
+		{outputID: spentID, before: true, after: false},
+		{outputID: outputID, before: false, after: true},
+		{outputID: detachedID, before: false, after: false},
+		{outputID: bestID, before: true, after: true},
+		{outputID: bc.Hash{V0: 4}, before: false, after: false},
+	}
+
+	for i, c := range cases {
+		if got := chain.HasUtxo(c.outputID.Byte32()); got != c.before {
+			t.Errorf("case %d: got HasUtxo %v before the tx, want %v", i, got, c.before)
+		}
+	}
+
+	block := &bc.Block{BlockHeader: &bc.BlockHeader{Height: 2}}
+	if err := view.ApplyTransaction(block, tx.Tx, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, c := range cases {
+		if got := chain.HasUtxo(c.outputID.Byte32()); got != c.after {
+			t.Errorf("case %d: got HasUtxo %v after the tx, want %v", i, got, c.after)
+		}
+	}
+}
+
+func TestCommittedTxStatus(t *testing.T) {
+	logs := []*bc.TxLog{{Address: []byte{1}, Data: []byte{2}}}
+	txStatus := &bc.TransactionStatus{
+		Version: 1,
+		VerifyStatus: []*bc.TxVerifyResult{
+			{StatusFail: true},
+			{Logs: logs, GasUsed: 21000, CumulativeGasUsed: 21000, ContractAddress: []byte{3}, Bloom: []byte{4}, RevertData: []byte{5}},
+		},
+	}
+
+	got := committedTxStatus(txStatus)
+	want := &bc.TransactionStatus{
+		Version: 1,
+		VerifyStatus: []*bc.TxVerifyResult{
+			{StatusFail: true},
+			{Logs: logs},
+		},
+	}
+	if !testutil.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	wantHash, err := types.TxStatusMerkleRoot(txStatus.VerifyStatus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotHash, err := types.TxStatusMerkleRoot(got.VerifyStatus); err != nil || gotHash != wantHash {
+		t.Errorf("got status hash %x, want %x", gotHash.Bytes(), wantHash.Bytes())
+	}
+}
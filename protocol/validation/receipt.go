@@ -0,0 +1,71 @@
+package validation
+
+import (
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+	evm_types "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/doslink/doslink/protocol/bc"
+	"github.com/doslink/doslink/protocol/vm"
+)
+
+// SetTxReceipt records the logs and the evm receipt of the i-th transaction
+// of a block into the transaction status. It must be called after the tx
+// has been applied to the stateDB, cumulativeGasUsed includes the gas used
+// by the tx itself. The revert data is kept for a status fail tx and for a
+// tx whose contract message failed.
+func SetTxReceipt(ts *bc.TransactionStatus, i int, tx *bc.Tx, stateDB *evm_state.StateDB, statusFail bool, gasUsed, cumulativeGasUsed uint64, vs *ValidationState) error {
+	evmLogs := stateDB.GetLogs(tx.ID.Byte32())
+
+	var txLogs []*bc.TxLog
+	for _, log := range evmLogs {
+		var topics [][]byte
+		for _, topic := range log.Topics {
+			topics = append(topics, topic.Bytes())
+		}
+		txLogs = append(txLogs,
+			&bc.TxLog{
+				Address: log.Address.Bytes(),
+				Topics:  topics,
+				Data:    log.Data,
+			},
+		)
+	}
+
+	if err := ts.SetLogs(i, txLogs); err != nil {
+		return err
+	}
+	if err := ts.SetStatus(i, statusFail); err != nil {
+		return err
+	}
+
+	var bloom []byte
+	if len(evmLogs) > 0 {
+		bloom = evm_types.BytesToBloom(evm_types.LogsBloom(evmLogs).Bytes()).Bytes()
+	}
+
+	var contractAddress []byte
+	revertData := vs.RevertData()
+	if !statusFail && !vs.MessageFailed() {
+		contractAddress, revertData = createdContract(tx), nil
+	}
+	if err := ts.SetReceipt(i, gasUsed, cumulativeGasUsed, contractAddress, bloom); err != nil {
+		return err
+	}
+	return ts.SetRevertData(i, revertData)
+}
+
+// createdContract returns the address of the contract deployed by the tx,
+// or nil if the tx deploys no contract.
+func createdContract(tx *bc.Tx) []byte {
+	for _, id := range tx.InputIDs {
+		switch e := tx.Entries[id].(type) {
+		case *bc.Creation:
+			return vm.ContractAddress(e.From.Code, e.Nonce)
+		case *bc.Contract:
+			if len(e.To) == 0 {
+				return vm.ContractAddress(e.From.Code, e.Nonce)
+			}
+		}
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/vm"
 	"github.com/doslink/doslink/protocol/vm/evm"
+	"github.com/doslink/doslink/protocol/vm/state"
 
 	evm_common "github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
@@ -117,12 +118,28 @@ type ValidationState struct {
 	sourcePos uint64            // The source position, for validate ValueSources
 	destPos   uint64            // The destination position, for validate ValueDestinations
 	cache     map[bc.Hash]error // Memoized per-entry validation results
+	evmConfig evm.Config        // The config of the EVM run by the contract entries
+	gasPool   *state.GasPool    // The EVM gas left in the block
+	revert    []byte            // The return data of the failed contract message
+	failed    bool              // Whether a contract message of the tx failed
 }
 
 func (vs *ValidationState) GasState() *GasState {
 	return vs.gasStatus
 }
 
+// RevertData returns the return data of the failed contract message of the
+// tx, nil when no contract message failed or it failed without data
+func (vs *ValidationState) RevertData() []byte {
+	return vs.revert
+}
+
+// MessageFailed reports whether a contract message of the tx failed, the tx
+// still pays the gas of the failed message
+func (vs *ValidationState) MessageFailed() bool {
+	return vs.failed
+}
+
 func checkValid(vs *ValidationState, e bc.Entry) (err error) {
 	var ok bool
 	entryID := bc.EntryID(e)
@@ -276,6 +293,9 @@ func checkValid(vs *ValidationState, e bc.Entry) (err error) {
 		if err = checkValidDest(&destVS, e.WitnessDestination); err != nil {
 			return errors.Wrap(err, "checking issuance destination")
 		}
+		if vs.block.BlockHeader.GetHeight() >= consensus.ActiveNetParams.ChainDataHeight {
+			evm.SetAssetDefinition(vs.stateDB, evm_common.Hash(e.Value.AssetId.Byte32()), evm_common.Hash(e.WitnessAssetDefinition.Data.Byte32()))
+		}
 
 	case *bc.Creation:
 		_, gasLeft, err := vm.Verify(NewTxVMContext(vs, e, e.From, e.WitnessArguments), vs.gasStatus.GasLeft)
@@ -713,8 +733,30 @@ func checkTimeRange(tx *bc.Tx, block *bc.Block) error {
 	return nil
 }
 
-// ValidateTx validates a transaction.
+// ValidateTx validates a transaction, its EVM messages may use the gas of a
+// whole block.
 func ValidateTx(tx *bc.Tx, block *bc.Block, chain vm.ChainContext, stateDB evm.StateDB) (*ValidationState, error) {
+	return validateTx(tx, block, chain, stateDB, NewBlockGasPool(), evm.Config{})
+}
+
+// ValidateBlockTx validates a transaction of the block, its EVM messages buy
+// their gas from the gas pool shared by the transactions of the block.
+func ValidateBlockTx(tx *bc.Tx, block *bc.Block, chain vm.ChainContext, stateDB evm.StateDB, gp *state.GasPool) (*ValidationState, error) {
+	return validateTx(tx, block, chain, stateDB, gp, evm.Config{})
+}
+
+// TraceTx validates a transaction like ValidateBlockTx, the EVM of the contract
+// entries runs with the given config so that a tracer can follow it.
+func TraceTx(tx *bc.Tx, block *bc.Block, chain vm.ChainContext, stateDB evm.StateDB, gp *state.GasPool, evmConfig evm.Config) (*ValidationState, error) {
+	return validateTx(tx, block, chain, stateDB, gp, evmConfig)
+}
+
+// NewBlockGasPool returns the EVM gas pool of a block
+func NewBlockGasPool() *state.GasPool {
+	return new(state.GasPool).AddGas(consensus.MaxBlockGas)
+}
+
+func validateTx(tx *bc.Tx, block *bc.Block, chain vm.ChainContext, stateDB evm.StateDB, gp *state.GasPool, evmConfig evm.Config) (*ValidationState, error) {
 	gasStatus := &GasState{GasValid: false}
 
 	vs := &ValidationState{
@@ -725,6 +767,8 @@ func ValidateTx(tx *bc.Tx, block *bc.Block, chain vm.ChainContext, stateDB evm.S
 		entryID:   tx.ID,
 		gasStatus: gasStatus,
 		cache:     make(map[bc.Hash]error),
+		evmConfig: evmConfig,
+		gasPool:   gp,
 	}
 
 	if block.Version == 1 && tx.Version != 1 {
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
+	evm_common "github.com/ethereum/go-ethereum/common"
+	evm_state "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
 
 	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/basis/crypto/sha3pool"
@@ -12,6 +15,7 @@ import (
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/vm"
+	"github.com/doslink/doslink/protocol/vm/evm"
 	"github.com/doslink/doslink/protocol/vmutil"
 	"github.com/doslink/doslink/testutil"
 )
@@ -66,7 +70,7 @@ func TestGasStatus(t *testing.T) {
 				AssetValue: 0,
 			},
 			output: &GasState{
-				GasLeft:    200000,
+				GasLeft:    consensus.MaxGasAmount,
 				GasUsed:    0,
 				AssetValue: 80000000000,
 			},
@@ -201,7 +205,7 @@ func TestOverflow(t *testing.T) {
 
 	for i, c := range cases {
 		tx := newTx(c.inputs, c.outputs)
-		if _, err := ValidateTx(tx, mockBlock()); rootErr(err) != c.err {
+		if _, err := ValidateTx(tx, mockBlock(), mockChain{}, mockStateDB()); rootErr(err) != c.err {
 			t.Fatalf("case %d test failed, want %s, have %s", i, c.err, rootErr(err))
 		}
 	}
@@ -235,7 +239,7 @@ func TestTxValidation(t *testing.T) {
 			Value:    &txOutput.AssetAmount,
 			Position: uint64(len(tx.ResultIds)),
 		}
-		prog := &bc.Program{txOutput.VMVersion, txOutput.ControlProgram}
+		prog := &bc.Program{VmVersion: txOutput.VMVersion, Code: txOutput.ControlProgram}
 		output := bc.NewOutput(src, prog, uint64(len(tx.ResultIds)))
 		outputID := bc.EntryID(output)
 		tx.Entries[outputID] = output
@@ -356,11 +360,11 @@ func TestTxValidation(t *testing.T) {
 			err: ErrMismatchedValue,
 		},
 		{
+			// the version 1 tx without results is checked through its mux
 			desc: "empty tx results",
 			f: func() {
 				tx.ResultIds = nil
 			},
-			err: ErrEmptyResults,
 		},
 		{
 			desc: "empty tx results, but that's OK",
@@ -541,6 +545,8 @@ func TestTxValidation(t *testing.T) {
 			fixture = sample(t, nil)
 			tx = types.NewTx(*fixture.tx).Tx
 			vs = &ValidationState{
+				chain:   mockChain{},
+				stateDB: mockStateDB(),
 				block:   mockBlock(),
 				tx:      tx,
 				entryID: tx.ID,
@@ -587,13 +593,13 @@ func TestCoinbase(t *testing.T) {
 	}
 
 	for i, c := range cases {
-		gasStatus, err := ValidateTx(c.tx, c.block)
+		gasStatus, err := ValidateTx(c.tx, c.block, mockChain{}, mockStateDB())
 
 		if rootErr(err) != c.err {
 			t.Errorf("#%d got error %s, want %s", i, err, c.err)
 		}
-		if c.GasValid != gasStatus.GasValid {
-			t.Errorf("#%d got GasValid %t, want %t", i, gasStatus.GasValid, c.GasValid)
+		if c.GasValid != gasStatus.GasState().GasValid {
+			t.Errorf("#%d got GasValid %t, want %t", i, gasStatus.GasState().GasValid, c.GasValid)
 		}
 	}
 }
@@ -641,7 +647,7 @@ func TestTimeRange(t *testing.T) {
 
 	for i, c := range cases {
 		tx.TimeRange = c.timeRange
-		if _, err := ValidateTx(tx, block); (err != nil) != c.err {
+		if _, err := ValidateTx(tx, block, mockChain{}, mockStateDB()); (err != nil) != c.err {
 			t.Errorf("#%d got error %t, want %t", i, !c.err, c.err)
 		}
 	}
@@ -671,9 +677,10 @@ type txFixture struct {
 // subsequent call to make iterative refinements to a test object.
 //
 // The default transaction produced is valid and has three inputs:
-//  - an issuance of 10 units
-//  - a spend of 20 units
-//  - a spend of 40 units
+//   - an issuance of 10 units
+//   - a spend of 20 units
+//   - a spend of 40 units
+//
 // and two outputs, one of 25 units and one of 45 units.
 // All amounts are denominated in the same asset.
 //
@@ -759,6 +766,50 @@ func sample(tb testing.TB, in *txFixture) *txFixture {
 	return &result
 }
 
+func TestIssuanceAssetDefinition(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+
+	fixture := sample(t, nil)
+	fixture.tx.SerializedSize = 1
+	tx := types.MapTx(fixture.tx)
+	assetID := evm_common.Hash(fixture.assetID.Byte32())
+
+	cases := []struct {
+		forkHeight uint64
+		recorded   bool
+	}{
+		{forkHeight: 666, recorded: true},
+		{forkHeight: 667, recorded: false},
+	}
+	for _, c := range cases {
+		consensus.ActiveNetParams = consensus.Params{Name: "test", ChainDataHeight: c.forkHeight}
+
+		stateDB := mockStateDB()
+		if _, err := ValidateTx(tx, mockBlock(), mockChain{}, stateDB); err != nil {
+			t.Fatal(err)
+		}
+		if recorded := evm.GetAssetDefinition(stateDB, assetID) != (evm_common.Hash{}); recorded != c.recorded {
+			t.Errorf("fork height %d: got asset definition recorded %v, want %v", c.forkHeight, recorded, c.recorded)
+		}
+	}
+}
+
+// mockChain is the chain context of the transactions run without a chain
+type mockChain struct{}
+
+func (mockChain) BestBlockInfo() (height, timestamp, difficulty uint64) { return 0, 0, 0 }
+func (mockChain) GetBlockHashByHeight(uint64) [32]byte                  { return [32]byte{} }
+func (mockChain) GetAncestorHash([32]byte, uint64) [32]byte             { return [32]byte{} }
+func (mockChain) HasUtxo([32]byte) bool                                 { return false }
+
+func mockStateDB() *evm_state.StateDB {
+	stateDB, err := evm_state.New(evm_common.Hash{}, evm_state.NewDatabase(ethdb.NewMemDatabase()))
+	if err != nil {
+		panic(err)
+	}
+	return stateDB
+}
+
 func mockBlock() *bc.Block {
 	return &bc.Block{
 		BlockHeader: &bc.BlockHeader{
@@ -16,6 +16,8 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 	var (
 		tx          = vs.tx
 		blockHeight = vs.block.BlockHeader.GetHeight()
+		blockTime   = vs.block.BlockHeader.GetTimestamp()
+		blockBits   = vs.block.BlockHeader.GetBits()
 		numResults  = uint64(len(tx.ResultIds))
 		txData      = tx.Data.Bytes()
 		entryID     = bc.EntryID(entry) // TODO(bobg): pass this in, don't recompute it
@@ -34,7 +36,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		amount = &e.Value.Amount
 		destPos = &e.WitnessDestination.Position
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Spend:
@@ -45,7 +47,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		destPos = &e.WitnessDestination.Position
 		s := e.SpentOutputId.Bytes()
 		spentOutputID = &s
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Creation:
@@ -53,7 +55,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		a2 := uint64(0)
 		amount = &a2
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Call:
@@ -61,7 +63,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		a2 := uint64(0)
 		amount = &a2
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Contract:
@@ -69,7 +71,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		a2 := uint64(0)
 		amount = &a2
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Deposit:
@@ -77,7 +79,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		a2 := e.Source.Value.Amount
 		amount = &a2
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	case *bc.Withdrawal:
@@ -85,7 +87,7 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		assetID = &a1
 		amount = &e.Value.Amount
 		destPos = &e.WitnessDestination.Position
-		c1 := witnessProgram(prog.Code)
+		c1 := witnessProgram(prog.Code, blockHeight)
 		code = &c1
 
 	}
@@ -115,14 +117,20 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 	result := &vm.Context{
 		Chain:     vs.chain,
 		StateDB:   vs.stateDB,
+		GasPool:   vs.gasPool,
+		EVMConfig: vs.evmConfig,
 		VMVersion: prog.VmVersion,
 		Code:      *code,
 		Arguments: args,
 
 		EntryID: entryID.Bytes(),
 
-		TxVersion:   &tx.Version,
-		BlockHeight: &blockHeight,
+		TxVersion:      &tx.Version,
+		BlockHeight:    &blockHeight,
+		BlockTimestamp: &blockTime,
+		BlockBits:      &blockBits,
+		Coinbase:       blockCoinbase(vs.block),
+		PrevBlockHash:  blockPrevHash(vs.block),
 
 		TxSigHash:     txSigHashFn,
 		NumResults:    &numResults,
@@ -133,13 +141,50 @@ func NewTxVMContext(vs *ValidationState, entry bc.Entry, prog *bc.Program, args
 		DestPos:       destPos,
 		SpentOutputID: spentOutputID,
 		CheckOutput:   ec.checkOutput,
+		RevertData:    func(data []byte) { vs.revert, vs.failed = data, true },
 	}
 
 	return result
 }
 
-func witnessProgram(prog []byte) []byte {
-	if segwit.IsP2WSHScript(prog) {
+// blockCoinbase returns the address paid by the coinbase of the block, it is
+// nil once the block has no coinbase or it pays a non standard program.
+func blockCoinbase(block *bc.Block) []byte {
+	if len(block.Transactions) == 0 {
+		return nil
+	}
+
+	coinbase := block.Transactions[0]
+	if coinbase == nil || len(coinbase.ResultIds) == 0 {
+		return nil
+	}
+
+	output, ok := coinbase.Entries[*coinbase.ResultIds[0]].(*bc.Output)
+	if !ok {
+		return nil
+	}
+
+	hash, err := segwit.GetHashFromStandardProg(output.ControlProgram.Code)
+	if err != nil {
+		return nil
+	}
+	return hash
+}
+
+// blockPrevHash returns the hash of the parent of the block, it is nil for the
+// blocks made up without a parent.
+func blockPrevHash(block *bc.Block) []byte {
+	if prevHash := block.BlockHeader.GetPreviousBlockId(); prevHash != nil {
+		return prevHash.Bytes()
+	}
+	return nil
+}
+
+// witnessProgram converts the witness program into the program it commits
+// to, the version 1 programs of the public key hash are only converted from
+// the SM2Height of the network and run as they are before it
+func witnessProgram(prog []byte, height uint64) []byte {
+	if segwit.IsP2WSHScript(prog) && (prog[0] == byte(vm.OP_0) || height >= consensus.ActiveNetParams.SM2Height) {
 		if witnessProg, err := segwit.ConvertP2SHProgram([]byte(prog)); err == nil {
 			return witnessProg
 		}
@@ -1,13 +1,21 @@
 package validation
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"testing"
 
+	evm_common "github.com/ethereum/go-ethereum/common"
+
 	"github.com/doslink/doslink/basis/errors"
+	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 	"github.com/doslink/doslink/protocol/vm"
+	"github.com/doslink/doslink/protocol/vm/evm"
+	vmstate "github.com/doslink/doslink/protocol/vm/state"
+	"github.com/doslink/doslink/protocol/vmutil"
 )
 
 func TestCheckOutput(t *testing.T) {
@@ -89,3 +97,326 @@ func TestCheckOutput(t *testing.T) {
 		})
 	}
 }
+
+// ancestorChain only knows the ancestors of the parent of the block being
+// validated, the best chain lookups of mockChain return zero hashes
+type ancestorChain struct {
+	mockChain
+	prevHash  [32]byte
+	ancestors map[uint64][32]byte
+}
+
+func (c *ancestorChain) GetAncestorHash(blockHash [32]byte, height uint64) [32]byte {
+	if blockHash != c.prevHash {
+		return [32]byte{}
+	}
+	return c.ancestors[height]
+}
+
+var (
+	miner   = bytes.Repeat([]byte{0x11}, 20)
+	creator = bytes.Repeat([]byte{0x22}, 20)
+)
+
+// minedBlock returns the block at height 5 whose coinbase pays the miner
+func minedBlock(t *testing.T, prevHash bc.Hash) *bc.Block {
+	minerProgram, err := vmutil.P2WSHProgram(miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	coinbase := types.NewTx(types.TxData{
+		Inputs:  []*types.TxInput{types.NewCoinbaseInput(nil)},
+		Outputs: []*types.TxOutput{types.NewTxOutput(*consensus.NativeAssetID, 100, minerProgram)},
+	})
+	return &bc.Block{
+		BlockHeader: &bc.BlockHeader{
+			Height:          5,
+			Timestamp:       1523352600,
+			Bits:            2305843009214532812,
+			PreviousBlockId: &prevHash,
+		},
+		Transactions: []*bc.Tx{coinbase.Tx},
+	}
+}
+
+func TestBlockContext(t *testing.T) {
+	prevHash := bc.Hash{V0: 4}
+	block := minedBlock(t, prevHash)
+	chain := &ancestorChain{
+		prevHash:  prevHash.Byte32(),
+		ancestors: map[uint64][32]byte{3: {3}, 4: {4}},
+	}
+
+	// the init code returns COINBASE, NUMBER, TIMESTAMP, DIFFICULTY and
+	// BLOCKHASH(3) as the code of the contract
+	initCode := []byte{
+		0x41, 0x60, 0x00, 0x52, // COINBASE PUSH1 0 MSTORE
+		0x43, 0x60, 0x20, 0x52, // NUMBER PUSH1 32 MSTORE
+		0x42, 0x60, 0x40, 0x52, // TIMESTAMP PUSH1 64 MSTORE
+		0x44, 0x60, 0x60, 0x52, // DIFFICULTY PUSH1 96 MSTORE
+		0x60, 0x03, 0x40, 0x60, 0x80, 0x52, // PUSH1 3 BLOCKHASH PUSH1 128 MSTORE
+		0x60, 0xa0, 0x60, 0x00, 0xf3, // PUSH1 160 PUSH1 0 RETURN
+	}
+
+	active := [][]byte{
+		evm_common.LeftPadBytes(miner, 32),
+		evm_common.LeftPadBytes(big.NewInt(5).Bytes(), 32),
+		evm_common.LeftPadBytes(new(big.Int).SetUint64(1523352600).Bytes(), 32),
+		evm_common.LeftPadBytes(new(big.Int).SetUint64(2305843009214532812).Bytes(), 32),
+		bytes.Repeat([]byte{0}, 32),
+	}
+	active[4][0] = 3
+
+	// the blocks below the fork see the best block of the chain and the sender
+	inactive := [][]byte{
+		evm_common.LeftPadBytes(creator, 32),
+		bytes.Repeat([]byte{0}, 32),
+		bytes.Repeat([]byte{0}, 32),
+		bytes.Repeat([]byte{0}, 32),
+		bytes.Repeat([]byte{0}, 32),
+	}
+
+	createProgram, err := vmutil.CreateContractProgram(initCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+	cases := []struct {
+		forkHeight uint64
+		want       [][]byte
+	}{
+		{forkHeight: 5, want: active},
+		{forkHeight: 6, want: inactive},
+	}
+
+	for _, c := range cases {
+		consensus.ActiveNetParams = consensus.Params{Name: "test", BlockContextHeight: c.forkHeight}
+
+		vs := creationState(block, chain, mockStateDB(), createProgram)
+		if err := runCreation(vs); err != nil {
+			t.Fatal(err)
+		}
+
+		got := vs.stateDB.GetCode(evm_common.BytesToAddress(vm.ContractAddress(creator, 0)))
+		if want := bytes.Join(c.want, nil); !bytes.Equal(got, want) {
+			t.Errorf("fork height %d: got block context %x, want %x", c.forkHeight, got, want)
+		}
+	}
+}
+
+func TestMessageGas(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+
+	const (
+		balance  = 1000000
+		gasLimit = 100000
+		gasPrice = 2
+	)
+
+	cases := []struct {
+		desc      string
+		params    consensus.Params
+		initCode  []byte
+		poolGas   uint64
+		err       error
+		failed    bool
+		usedAll   bool
+		deployed  bool
+		unchanged bool
+	}{
+		{
+			desc:     "the message deploys the contract",
+			params:   consensus.SoloNetParams,
+			initCode: []byte{0x60, 0x01, 0x60, 0x00, 0xf3}, // PUSH1 1 PUSH1 0 RETURN
+			poolGas:  consensus.MaxBlockGas,
+			deployed: true,
+		},
+		{
+			desc:     "the reverted message pays the gas it used",
+			params:   consensus.SoloNetParams,
+			initCode: []byte{0x60, 0x00, 0x60, 0x00, 0xfd}, // PUSH1 0 PUSH1 0 REVERT
+			poolGas:  consensus.MaxBlockGas,
+			failed:   true,
+		},
+		{
+			desc:     "the invalid message pays all of its gas",
+			params:   consensus.SoloNetParams,
+			initCode: []byte{0xfe}, // INVALID
+			poolGas:  consensus.MaxBlockGas,
+			failed:   true,
+			usedAll:  true,
+		},
+		{
+			desc:      "the gas pool of the block runs out",
+			params:    consensus.SoloNetParams,
+			initCode:  []byte{0x60, 0x01, 0x60, 0x00, 0xf3},
+			poolGas:   gasLimit - 1,
+			err:       vmstate.ErrGasLimitReached,
+			unchanged: true,
+		},
+		{
+			desc:      "the gas price program is not active",
+			params:    consensus.Params{Name: "test", GasPriceHeight: 6},
+			initCode:  []byte{0x60, 0x01, 0x60, 0x00, 0xf3},
+			poolGas:   consensus.MaxBlockGas,
+			err:       vm.ErrUnknownVersion,
+			unchanged: true,
+		},
+	}
+
+	for _, c := range cases {
+		consensus.ActiveNetParams = c.params
+
+		createProgram, err := vmutil.EVMGasProgram(vm.OP_CREATE, c.initCode, gasLimit, gasPrice)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stateDB := mockStateDB()
+		from := evm_common.BytesToAddress(creator)
+		stateDB.AddBalance(from, big.NewInt(balance))
+
+		vs := creationState(minedBlock(t, bc.Hash{}), mockChain{}, stateDB, createProgram)
+		vs.gasPool = new(vmstate.GasPool).AddGas(c.poolGas)
+		if err := runCreation(vs); errors.Root(err) != c.err {
+			t.Errorf("%s: got error %v, want %v", c.desc, err, c.err)
+			continue
+		}
+
+		gasUsed := c.poolGas - vs.gasPool.Gas()
+		paid := stateDB.GetBalance(evm_common.BytesToAddress(miner)).Uint64()
+		switch {
+		case c.unchanged:
+			if gasUsed != 0 || paid != 0 || stateDB.GetBalance(from).Uint64() != balance {
+				t.Errorf("%s: got gas used %d and coinbase paid %d, want the gas untouched", c.desc, gasUsed, paid)
+			}
+			continue
+		case gasUsed == 0 || gasUsed > gasLimit:
+			t.Errorf("%s: got gas used %d, want within (0, %d]", c.desc, gasUsed, gasLimit)
+		case c.usedAll != (gasUsed == gasLimit):
+			t.Errorf("%s: got gas used %d, want all of the gas limit %v", c.desc, gasUsed, c.usedAll)
+		}
+
+		if paid != gasUsed*gasPrice {
+			t.Errorf("%s: got coinbase paid %d, want %d", c.desc, paid, gasUsed*gasPrice)
+		}
+		if got := stateDB.GetBalance(from).Uint64(); got != balance-gasUsed*gasPrice {
+			t.Errorf("%s: got creator balance %d, want the gas refunded to %d", c.desc, got, balance-gasUsed*gasPrice)
+		}
+		if vs.MessageFailed() != c.failed {
+			t.Errorf("%s: got message failed %v, want %v", c.desc, vs.MessageFailed(), c.failed)
+		}
+
+		code := stateDB.GetCode(evm_common.BytesToAddress(vm.ContractAddress(creator, 0)))
+		if deployed := len(code) > 0; deployed != c.deployed {
+			t.Errorf("%s: got contract deployed %v, want %v", c.desc, deployed, c.deployed)
+		}
+	}
+}
+
+func TestMessageBeforeGasPrice(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+	consensus.ActiveNetParams = consensus.Params{Name: "test", GasPriceHeight: 6}
+
+	cases := []struct {
+		desc     string
+		initCode []byte
+		failed   bool
+	}{
+		{
+			desc:     "the message runs on a gas pool of its own",
+			initCode: []byte{0x60, 0x01, 0x60, 0x00, 0xf3}, // PUSH1 1 PUSH1 0 RETURN
+		},
+		{
+			desc:     "the reverted message fails the op",
+			initCode: []byte{0x60, 0x00, 0x60, 0x00, 0xfd}, // PUSH1 0 PUSH1 0 REVERT
+			failed:   true,
+		},
+	}
+
+	for _, c := range cases {
+		createProgram, err := vmutil.CreateContractProgram(c.initCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stateDB := mockStateDB()
+		vs := creationState(minedBlock(t, bc.Hash{}), mockChain{}, stateDB, createProgram)
+		vs.gasPool = new(vmstate.GasPool).AddGas(1)
+		if err := runCreation(vs); (err != nil) != c.failed {
+			t.Errorf("%s: got error %v, want failed %v", c.desc, err, c.failed)
+		}
+		if vs.gasPool.Gas() != 1 {
+			t.Errorf("%s: got gas pool %d, want the gas pool of the block untouched", c.desc, vs.gasPool.Gas())
+		}
+
+		code := stateDB.GetCode(evm_common.BytesToAddress(vm.ContractAddress(creator, 0)))
+		if deployed := len(code) > 0; deployed == c.failed {
+			t.Errorf("%s: got contract deployed %v, want %v", c.desc, deployed, !c.failed)
+		}
+	}
+}
+
+// creationState returns the validation state of the tx which creates a
+// contract of the creator by the create program in the block
+func creationState(block *bc.Block, chain vm.ChainContext, stateDB evm.StateDB, createProgram []byte) *ValidationState {
+	creatorProgram, err := vmutil.P2WSHProgram(creator)
+	if err != nil {
+		panic(err)
+	}
+
+	tx := types.NewTx(types.TxData{
+		Version:        1,
+		SerializedSize: 1,
+		Inputs:         []*types.TxInput{types.NewCreationInput(creatorProgram, 0, createProgram, nil)},
+	}).Tx
+	return &ValidationState{
+		chain:     chain,
+		stateDB:   stateDB,
+		block:     block,
+		tx:        tx,
+		entryID:   tx.ID,
+		gasStatus: &GasState{GasLeft: consensus.MaxGasAmount},
+		cache:     make(map[bc.Hash]error),
+		gasPool:   NewBlockGasPool(),
+	}
+}
+
+// runCreation runs the create program of the creation entry of the tx
+func runCreation(vs *ValidationState) error {
+	creation := vs.tx.Entries[vs.tx.InputIDs[0]].(*bc.Creation)
+	args := [][]byte{creator, {}}
+	_, _, err := vm.Verify(NewTxVMContext(vs, creation, creation.Input, args), vs.gasStatus.GasLeft)
+	return err
+}
+
+func TestWitnessProgram(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+	consensus.ActiveNetParams = consensus.Params{Name: "test", SM2Height: 100}
+
+	hash := bytes.Repeat([]byte{0x33}, 20)
+	p2wsh, _ := vmutil.P2WSHProgram(hash)
+	p2wpkh, _ := vmutil.P2WPKHProgram(hash)
+	p2sh, _ := vmutil.P2SHProgram(hash)
+	p2pkh, _ := vmutil.P2PKHSigProgram(hash)
+
+	cases := []struct {
+		prog   []byte
+		height uint64
+		want   []byte
+	}{
+		{prog: p2wsh, height: 99, want: p2sh},
+		{prog: p2wsh, height: 100, want: p2sh},
+		{prog: p2wpkh, height: 99, want: p2wpkh},
+		{prog: p2wpkh, height: 100, want: p2pkh},
+		{prog: p2sh, height: 100, want: p2sh},
+	}
+
+	for i, c := range cases {
+		if got := witnessProgram(c.prog, c.height); !bytes.Equal(got, c.want) {
+			t.Errorf("case %d: got program %x, want %x", i, got, c.want)
+		}
+	}
+}
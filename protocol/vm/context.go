@@ -2,6 +2,7 @@ package vm
 
 import (
 	"github.com/doslink/doslink/protocol/vm/evm"
+	"github.com/doslink/doslink/protocol/vm/state"
 )
 
 // Context contains the execution context for the virtual machine.
@@ -16,6 +17,8 @@ import (
 type Context struct {
 	Chain     ChainContext
 	StateDB   evm.StateDB
+	GasPool   *state.GasPool // EVM gas left in the block, nil for no limit
+	EVMConfig evm.Config     // config of the EVM run by the contract opcodes
 	VMVersion uint64
 	Code      []byte
 	Arguments [][]byte
@@ -27,6 +30,13 @@ type Context struct {
 	TxVersion   *uint64
 	BlockHeight *uint64
 
+	// BlockTimestamp, BlockBits, Coinbase and PrevBlockHash describe the
+	// block of the transaction to the EVM run by the contract opcodes.
+	BlockTimestamp *uint64
+	BlockBits      *uint64
+	Coinbase       []byte
+	PrevBlockHash  []byte
+
 	// Fields below this point are required by particular opcodes when
 	// verifying transaction components.
 
@@ -40,4 +50,8 @@ type Context struct {
 
 	TxSigHash   func() []byte
 	CheckOutput func(index uint64, amount uint64, assetID []byte, vmVersion uint64, code []byte, expansion bool) (bool, error)
+
+	// RevertData receives the return data of a failed contract message, it is
+	// called even if the message failed without data
+	RevertData func(data []byte)
 }
@@ -6,36 +6,14 @@ import (
 	evm_crypto "github.com/ethereum/go-ethereum/crypto"
 	"math/big"
 	"github.com/doslink/doslink/protocol/vm/evm"
-	"github.com/doslink/doslink/protocol/vm/state"
 	log "github.com/sirupsen/logrus"
 	"bytes"
 	"github.com/doslink/doslink/consensus"
 	"encoding/hex"
-	"math"
-	"errors"
 )
 
 func IsOpContract(prog []byte) bool {
-	insts, err := ParseProgram(prog)
-	if err != nil {
-		return false
-	}
-	if len(insts) != 3 {
-		return false
-	}
-
-	version := insts[0]
-	input := insts[1]
-
-	if version.Op > OP_16 {
-		return false
-	}
-
-	if input.Op < OP_DATA_1 || input.Op > OP_PUSHDATA4 {
-		return false
-	}
-
-	return insts[len(insts)-1].Op == OP_CONTRACT
+	return isEVMProgram(prog, OP_CONTRACT)
 }
 
 func opContract(vm *virtualMachine) error {
@@ -48,15 +26,11 @@ func opContract(vm *virtualMachine) error {
 		to       *evm_common.Address = nil
 		nonce    uint64
 		amount   = evm_common.Big0
-		gasLimit = uint64(vm.runLimit)
-		gasPrice = evm_common.Big0
 
 		msg      evm_types.Message
 		author   *evm_common.Address
 		stateDB  = vm.context.StateDB
-		vmConfig = evm.Config{}
-
-		height, timestamp, difficulty = vm.context.Chain.BestBlockInfo()
+		vmConfig = vm.context.EVMConfig
 	)
 
 	// get params from dataStack
@@ -65,14 +39,10 @@ func opContract(vm *virtualMachine) error {
 		return err
 	}
 
-	versionBytes, err := vm.pop(false)
+	version, gasLimit, gasPrice, err := vm.popVersion()
 	if err != nil {
 		return err
 	}
-	version := new(big.Int).SetBytes(versionBytes).Uint64()
-	if version > 0 {
-		return errors.New("unknown version number")
-	}
 
 	contractAddress, err := vm.pop(false)
 	if err != nil {
@@ -98,32 +68,28 @@ func opContract(vm *virtualMachine) error {
 	if bytes.Compare(assetID, consensus.NativeAssetID.Bytes()) == 0 {
 		amount = new(big.Int).SetUint64(assetAmount)
 		stateDB.AddBalance(from, amount)
+	} else if to != nil {
+		vm.depositAsset(assetID, assetAmount, *to)
+	} else {
+		vm.depositAsset(assetID, assetAmount, evm_crypto.CreateAddress(from, nonce))
 	}
 
-	gasLimit = uint64(vm.runLimit)
 	log.WithFields(log.Fields{"amount": amount, "gasLimit": gasLimit, "execBalance": stateDB.GetBalance(from)}).Infoln("check balance")
 
-	author = &from
+	height, timestamp, difficulty, coinbase := vm.blockContext(from)
+	author = &coinbase
 
 	log.WithFields(log.Fields{"sender": from.Hex(), "nonce": nonce, "stateNonce": stateDB.GetNonce(from)}).Infoln("check nonce")
 	msg = evm_types.NewMessage(from, to, nonce, amount, gasLimit, gasPrice, data, true)
 	//fmt.Printf("msg=%v\n", msg)
 	//fmt.Printf("header=%v\n", header)
 	evmContext := NewEVMContext(msg, height, timestamp, difficulty, chain, author)
+	evmContext.GetHash = vm.getHashFn()
+	evmContext.Rules = NewRules(vm.blockHeight())
 	//fmt.Printf("evmContext=%v\n", evmContext)
 	evmEnv := evm.NewEVM(evmContext, stateDB, vmConfig)
 	//fmt.Printf("evmEnv=%v\n", evmEnv)
-	gp := new(state.GasPool).AddGas(math.MaxUint64)
-	//fmt.Printf("GasPool=%v\n", gp)
-
-	ret, gas, _, err := state.ApplyMessage(evmEnv, msg, gp)
-
-	if err != nil {
-		log.WithField("error", err).Error("ApplyMessage to evm failed")
-		return err
-	}
-
-	err = vm.applyCost(int64(gas))
+	ret, err := vm.applyMessage(evmEnv, msg, version)
 	if err != nil {
 		return err
 	}
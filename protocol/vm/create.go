@@ -6,35 +6,13 @@ import (
 	evm_crypto "github.com/ethereum/go-ethereum/crypto"
 	"math/big"
 	"github.com/doslink/doslink/protocol/vm/evm"
-	"github.com/doslink/doslink/protocol/vm/state"
 	log "github.com/sirupsen/logrus"
 	"bytes"
 	"github.com/doslink/doslink/consensus"
-	"math"
-	"errors"
 )
 
 func IsOpCreate(prog []byte) bool {
-	insts, err := ParseProgram(prog)
-	if err != nil {
-		return false
-	}
-	if len(insts) != 3 {
-		return false
-	}
-
-	version := insts[0]
-	contract := insts[1]
-
-	if version.Op > OP_16 {
-		return false
-	}
-
-	if contract.Op < OP_DATA_1 || contract.Op > OP_PUSHDATA4 {
-		return false
-	}
-
-	return insts[len(insts)-1].Op == OP_CREATE
+	return isEVMProgram(prog, OP_CREATE)
 }
 
 func opCreate(vm *virtualMachine) error {
@@ -47,15 +25,11 @@ func opCreate(vm *virtualMachine) error {
 		to       *evm_common.Address = nil
 		nonce    uint64
 		amount   = evm_common.Big0
-		gasLimit = uint64(vm.runLimit)
-		gasPrice = evm_common.Big0
 
 		msg      evm_types.Message
 		author   *evm_common.Address
 		stateDB  = vm.context.StateDB
-		vmConfig = evm.Config{}
-
-		height, timestamp, difficulty = vm.context.Chain.BestBlockInfo()
+		vmConfig = vm.context.EVMConfig
 	)
 
 	// get params from dataStack
@@ -64,14 +38,10 @@ func opCreate(vm *virtualMachine) error {
 		return err
 	}
 
-	versionBytes, err := vm.pop(false)
+	version, gasLimit, gasPrice, err := vm.popVersion()
 	if err != nil {
 		return err
 	}
-	version := new(big.Int).SetBytes(versionBytes).Uint64()
-	if version > 0 {
-		return errors.New("unknown version number")
-	}
 
 	nonceBytes, err := vm.pop(false)
 	if err != nil {
@@ -87,33 +57,26 @@ func opCreate(vm *virtualMachine) error {
 	if bytes.Compare(assetID, consensus.NativeAssetID.Bytes()) == 0 {
 		amount = new(big.Int).SetUint64(assetAmount)
 		stateDB.AddBalance(from, amount)
+	} else {
+		vm.depositAsset(assetID, assetAmount, evm_crypto.CreateAddress(from, nonce))
 	}
 
-	gasLimit = uint64(vm.runLimit)
 	log.WithFields(log.Fields{"amount": amount, "gasLimit": gasLimit, "execBalance": stateDB.GetBalance(from)}).Infoln("check balance")
 
-	author = &from
+	height, timestamp, difficulty, coinbase := vm.blockContext(from)
+	author = &coinbase
 
 	log.WithFields(log.Fields{"creator": from.Hex(), "nonce": nonce, "stateNonce": stateDB.GetNonce(from)}).Infoln("check nonce")
 	msg = evm_types.NewMessage(from, to, nonce, amount, gasLimit, gasPrice, data, true)
 	//fmt.Printf("msg=%v\n", msg)
 	//fmt.Printf("header=%v\n", header)
 	evmContext := NewEVMContext(msg, height, timestamp, difficulty, chain, author)
+	evmContext.GetHash = vm.getHashFn()
+	evmContext.Rules = NewRules(vm.blockHeight())
 	//fmt.Printf("evmContext=%v\n", evmContext)
 	evmEnv := evm.NewEVM(evmContext, stateDB, vmConfig)
 	//fmt.Printf("evmEnv=%v\n", evmEnv)
-	gp := new(state.GasPool).AddGas(math.MaxUint64)
-	//fmt.Printf("GasPool=%v\n", gp)
-
-	_, gas, _, err := state.ApplyMessage(evmEnv, msg, gp)
-
-	if err != nil {
-		log.WithField("error", err).Error("ApplyMessage to evm failed")
-		return err
-	}
-
-	err = vm.applyCost(int64(gas))
-	if err != nil {
+	if _, err := vm.applyMessage(evmEnv, msg, version); err != nil {
 		return err
 	}
 
@@ -8,8 +8,10 @@ import (
 
 	"github.com/doslink/doslink/basis/crypto"
 	"github.com/doslink/doslink/basis/crypto/ed25519"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/basis/crypto/sm3"
 	"github.com/doslink/doslink/basis/math/checked"
+	"github.com/doslink/doslink/consensus"
 )
 
 func opSha256(vm *virtualMachine) error {
@@ -61,10 +63,36 @@ func opCheckSig(vm *virtualMachine) error {
 	if len(msg) != 32 {
 		return ErrBadValue
 	}
-	if len(pubkeyBytes) != ed25519.PublicKeySize {
+	if !vm.validPubkey(pubkeyBytes) {
 		return vm.pushBool(false, true)
 	}
-	return vm.pushBool(ed25519.Verify(ed25519.PublicKey(pubkeyBytes), msg, sig), true)
+	return vm.pushBool(verifySig(pubkeyBytes, msg, sig), true)
+}
+
+// validPubkey checks the size of the pubkey is the one of the ed25519 or the
+// SM2 compressed public keys, the size selects the signature scheme. The SM2
+// public keys are only valid from the SM2Height of the network.
+func (vm *virtualMachine) validPubkey(pubkey []byte) bool {
+	if len(pubkey) == sm2kd.PublicKeySize {
+		return vm.sm2Active()
+	}
+	return len(pubkey) == ed25519.PublicKeySize
+}
+
+// sm2Active reports whether the SM2 public keys are active at the height of
+// the block
+func (vm *virtualMachine) sm2Active() bool {
+	ctx := vm.context
+	return ctx != nil && ctx.BlockHeight != nil && *ctx.BlockHeight >= consensus.ActiveNetParams.SM2Height
+}
+
+// verifySig verifies the sig by the scheme of the pubkey, which is checked by
+// validPubkey first
+func verifySig(pubkey, msg, sig []byte) bool {
+	if len(pubkey) == sm2kd.PublicKeySize {
+		return sm2kd.Verify(pubkey, msg, sig)
+	}
+	return ed25519.Verify(ed25519.PublicKey(pubkey), msg, sig)
 }
 
 func opCheckMultiSig(vm *virtualMachine) error {
@@ -111,16 +139,15 @@ func opCheckMultiSig(vm *virtualMachine) error {
 		sigs = append(sigs, sig)
 	}
 
-	pubkeys := make([]ed25519.PublicKey, 0, numPubkeys)
 	for _, p := range pubkeyByteses {
-		if len(p) != ed25519.PublicKeySize {
+		if !vm.validPubkey(p) {
 			return vm.pushBool(false, true)
 		}
-		pubkeys = append(pubkeys, ed25519.PublicKey(p))
 	}
 
+	pubkeys := pubkeyByteses
 	for len(sigs) > 0 && len(pubkeys) > 0 {
-		if ed25519.Verify(pubkeys[0], msg, sigs[0]) {
+		if verifySig(pubkeys[0], msg, sigs[0]) {
 			sigs = sigs[1:]
 		}
 		pubkeys = pubkeys[1:]
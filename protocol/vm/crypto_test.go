@@ -1,8 +1,11 @@
 package vm
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
+	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/testutil"
 )
 
@@ -84,6 +87,106 @@ func TestCheckSig(t *testing.T) {
 	}
 }
 
+func TestSM2CheckSig(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+	consensus.ActiveNetParams = consensus.Params{Name: "test", SM2Height: 100}
+
+	const (
+		msg      = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+		edSig    = "0x26ced30b1942b89ef5332a9f22f1a61e5a6a3f8a5bc33b2fc58b1daf78c81bf1d5c8add19cea050adeb37da3a7bf8f813c6a6922b42934a6441fa6bb1c7fc208"
+		edPubkey = "0xdbca6fb13badb7cfdf76510070ffad15b85f9934224a9e11202f5e8f86b584a6"
+	)
+
+	xprv := sm2kd.RootXPrv([]byte("sm2 checksig"))
+	sig, err := xprv.Sign([]byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+		0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18, 0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey := xprv.XPub().PublicKey()
+	otherPubkey := sm2kd.RootXPrv([]byte("other")).XPub().PublicKey()
+
+	cases := []struct {
+		desc   string
+		prog   string
+		height uint64
+		ok     bool
+	}{
+		{
+			desc:   "sm2 signature",
+			prog:   fmt.Sprintf("0x%x %s 0x%x CHECKSIG", sig, msg, pubkey),
+			height: 100,
+			ok:     true,
+		},
+		{
+			desc:   "sm2 signature before the sm2 height",
+			prog:   fmt.Sprintf("0x%x %s 0x%x CHECKSIG", sig, msg, pubkey),
+			height: 99,
+		},
+		{
+			desc:   "sm2 signature of another message",
+			prog:   fmt.Sprintf("0x%x 0x%064x 0x%x CHECKSIG", sig, 1, pubkey),
+			height: 100,
+		},
+		{
+			desc:   "sm2 signature of another key",
+			prog:   fmt.Sprintf("0x%x %s 0x%x CHECKSIG", sig, msg, otherPubkey),
+			height: 100,
+		},
+		{
+			desc:   "ed25519 signature before the sm2 height",
+			prog:   fmt.Sprintf("%s %s %s CHECKSIG", edSig, msg, edPubkey),
+			height: 99,
+			ok:     true,
+		},
+		{
+			desc:   "ed25519 and sm2 signatures",
+			prog:   fmt.Sprintf("%s 0x%x %s %s 0x%x 2 2 CHECKMULTISIG", edSig, sig, msg, edPubkey, pubkey),
+			height: 100,
+			ok:     true,
+		},
+		{
+			desc:   "ed25519 and sm2 signatures before the sm2 height",
+			prog:   fmt.Sprintf("%s 0x%x %s %s 0x%x 2 2 CHECKMULTISIG", edSig, sig, msg, edPubkey, pubkey),
+			height: 99,
+		},
+		{
+			desc:   "sm2 signature of 1 of 2 keys",
+			prog:   fmt.Sprintf("0x%x %s %s 0x%x 1 2 CHECKMULTISIG", sig, msg, edPubkey, pubkey),
+			height: 100,
+			ok:     true,
+		},
+		{
+			desc:   "sm2 signature of 1 of 2 other keys",
+			prog:   fmt.Sprintf("0x%x %s %s 0x%x 1 2 CHECKMULTISIG", sig, msg, edPubkey, otherPubkey),
+			height: 100,
+		},
+	}
+
+	for _, c := range cases {
+		prog, err := Assemble(c.prog)
+		if err != nil {
+			t.Fatalf("%s: %s", c.desc, err)
+		}
+
+		height := c.height
+		vm := &virtualMachine{
+			program:  prog,
+			runLimit: 50000,
+			context:  &Context{BlockHeight: &height},
+		}
+		if err := vm.run(); err != nil {
+			t.Errorf("%s: got error %s", c.desc, err)
+			continue
+		}
+		if vm.falseResult() == c.ok {
+			t.Errorf("%s: got ok result %v, want %v", c.desc, !vm.falseResult(), c.ok)
+		}
+	}
+}
+
 func TestCryptoOps(t *testing.T) {
 	OP_SM3 := Op(0xb0)
 	ops[OP_SM3] = opInfo{OP_SM3, "SM3", opSm3}
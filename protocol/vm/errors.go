@@ -17,6 +17,7 @@ var (
 	ErrShortProgram       = errors.New("unexpected end of program")
 	ErrToken              = errors.New("unrecognized token")
 	ErrUnexpected         = errors.New("unexpected error")
+	ErrUnknownVersion     = errors.New("unknown version number")
 	ErrUnsupportedVM      = errors.New("unsupported VM")
 	ErrVerifyFailed       = errors.New("VERIFY failed")
 )
@@ -17,19 +17,33 @@
 package vm
 
 import (
+	"bytes"
+	"math"
 	"math/big"
 
+	log "github.com/sirupsen/logrus"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/doslink/doslink/protocol/vm/evm"
+	"github.com/doslink/doslink/protocol/vm/state"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/doslink/doslink/consensus"
 )
 
+// GasPriceVersion is the version of the EVM programs which declare the gas
+// limit and the gas price of the message, the message buys its gas from the
+// native asset balance of the sender, the programs are valid from the
+// GasPriceHeight of the network. The version 0 programs run with the gas left
+// of the vm at no gas price.
+const GasPriceVersion = 1
+
 // ChainContext supports retrieving headers and consensus parameters from the
 // current blockchain to be used during transaction processing.
 type ChainContext interface {
 	BestBlockInfo() (height, timestamp, difficulty uint64)
 	GetBlockHashByHeight(uint64) ([32]byte)
+	GetAncestorHash(blockHash [32]byte, height uint64) [32]byte
+	HasUtxo(outputID [32]byte) bool
 }
 
 // NewEVMContext creates a new context for use in the EVM.
@@ -40,6 +54,7 @@ func NewEVMContext(msg core.Message, height, timestamp, difficulty uint64, chain
 		CanTransfer: CanTransfer,
 		Transfer:    Transfer,
 		GetHash:     GetHashFn(chain),
+		HasUtxo:     HasUtxoFn(chain),
 		Origin:      msg.From(),
 		Coinbase:    beneficiary,
 		BlockNumber: new(big.Int).SetUint64(height),
@@ -47,6 +62,7 @@ func NewEVMContext(msg core.Message, height, timestamp, difficulty uint64, chain
 		Difficulty:  new(big.Int).SetUint64(difficulty),
 		GasLimit:    consensus.MaxBlockGas,
 		GasPrice:    new(big.Int).Set(msg.GasPrice()),
+		Rules:       NewRules(height),
 	}
 }
 
@@ -57,14 +73,198 @@ func GetHashFn(chain ChainContext) func(n uint64) common.Hash {
 	}
 }
 
+// HasUtxoFn returns a HasUtxoFunc which looks up the UTXO set of the chain
+func HasUtxoFn(chain ChainContext) func(outputID common.Hash) bool {
+	return func(outputID common.Hash) bool {
+		return chain.HasUtxo(outputID)
+	}
+}
+
 // CanTransfer checks whether there are enough funds in the address' account to make a transfer.
 // This does not take the necessary gas in to account to make the transfer valid.
 func CanTransfer(db evm.StateDB, addr common.Address, amount *big.Int) bool {
 	return db.GetBalance(addr).Cmp(amount) >= 0
 }
 
+// depositAsset credits the issued asset spent by the EVM op to the recipient of
+// the message, the native asset is carried by the value of the message instead
+func (vm *virtualMachine) depositAsset(assetID []byte, amount uint64, recipient common.Address) {
+	if amount == 0 || !vm.assetActive(assetID) {
+		return
+	}
+	evm.AddAssetBalance(vm.context.StateDB, common.BytesToHash(assetID), recipient, new(big.Int).SetUint64(amount))
+}
+
+// assetActive reports whether the balances of the asset are kept in the EVM
+// at the block of the context, the issued assets are kept from the
+// MultiAssetHeight
+func (vm *virtualMachine) assetActive(assetID []byte) bool {
+	return bytes.Equal(assetID, consensus.NativeAssetID.Bytes()) || vm.forkActive(consensus.ActiveNetParams.MultiAssetHeight)
+}
+
 // Transfer subtracts amount from sender and adds amount to recipient using the given Db
 func Transfer(db evm.StateDB, sender, recipient common.Address, amount *big.Int) {
 	db.SubBalance(sender, amount)
 	db.AddBalance(recipient, amount)
 }
+
+// blockContext returns the block seen by the EVM, the contexts without the
+// block of the transaction and the blocks below the BlockContextHeight fall
+// back to the best block and the sender.
+func (vm *virtualMachine) blockContext(sender common.Address) (height, timestamp, difficulty uint64, coinbase common.Address) {
+	ctx := vm.context
+	if ctx.BlockHeight == nil || ctx.BlockTimestamp == nil || ctx.BlockBits == nil || !vm.forkActive(consensus.ActiveNetParams.BlockContextHeight) {
+		height, timestamp, difficulty = ctx.Chain.BestBlockInfo()
+		return height, timestamp, difficulty, sender
+	}
+	return *ctx.BlockHeight, *ctx.BlockTimestamp, *ctx.BlockBits, common.BytesToAddress(ctx.Coinbase)
+}
+
+// isEVMProgram reports whether the program runs the EVM message of the op, it
+// is "<version> <input> op" or "<gas price> <gas limit> <version> <input> op"
+func isEVMProgram(prog []byte, op Op) bool {
+	insts, err := ParseProgram(prog)
+	if err != nil {
+		return false
+	}
+
+	switch len(insts) {
+	case 3:
+	case 5:
+		if insts[0].Op > OP_16 || insts[1].Op > OP_16 || insts[2].Op != OP_1 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	version := insts[len(insts)-3]
+	input := insts[len(insts)-2]
+
+	if version.Op > OP_16 {
+		return false
+	}
+
+	if input.Op < OP_DATA_1 || input.Op > OP_PUSHDATA4 {
+		return false
+	}
+
+	return insts[len(insts)-1].Op == op
+}
+
+// popVersion pops the version of the EVM program, the gas limit and the gas
+// price are popped as well for the GasPriceVersion program
+func (vm *virtualMachine) popVersion() (version, gasLimit uint64, gasPrice *big.Int, err error) {
+	versionBytes, err := vm.pop(false)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	versionNum := new(big.Int).SetBytes(versionBytes)
+	switch {
+	case versionNum.Sign() == 0:
+		return 0, uint64(vm.runLimit), common.Big0, nil
+	case versionNum.Cmp(big.NewInt(GasPriceVersion)) != 0 || !vm.gasPriceActive():
+		return 0, 0, nil, ErrUnknownVersion
+	}
+
+	gasLimitBytes, err := vm.pop(false)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	gasPriceBytes, err := vm.pop(false)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	gasLimitNum := new(big.Int).SetBytes(gasLimitBytes)
+	if !gasLimitNum.IsUint64() {
+		return 0, 0, nil, ErrBadValue
+	}
+	return GasPriceVersion, gasLimitNum.Uint64(), new(big.Int).SetBytes(gasPriceBytes), nil
+}
+
+// gasPriceActive reports whether the GasPriceVersion programs are active at
+// the height of the block
+func (vm *virtualMachine) gasPriceActive() bool {
+	return vm.forkActive(consensus.ActiveNetParams.GasPriceHeight)
+}
+
+// forkActive reports whether the fork of the height is active for the block
+// of the context
+func (vm *virtualMachine) forkActive(forkHeight uint64) bool {
+	return vm.blockHeight() >= forkHeight
+}
+
+// blockHeight returns the height of the block of the context, the contexts
+// without a block run on the best block
+func (vm *virtualMachine) blockHeight() uint64 {
+	if vm.context.BlockHeight != nil {
+		return *vm.context.BlockHeight
+	}
+	height, _, _ := vm.context.Chain.BestBlockInfo()
+	return height
+}
+
+// NewRules returns the forks of the EVM active at the height.
+func NewRules(height uint64) evm.Rules {
+	return evm.Rules{
+		IsMultiAsset: height >= consensus.ActiveNetParams.MultiAssetHeight,
+		IsChainData:  height >= consensus.ActiveNetParams.ChainDataHeight,
+	}
+}
+
+// applyMessage runs the message of the op, the gas used by the version 0
+// message is charged to the vm since it pays no gas price. From the
+// GasPriceHeight the message runs on the gas pool of the block and a failed
+// message keeps its gas, only the consensus errors fail the op. Before it the
+// message runs on a gas pool of its own and any error fails the op.
+func (vm *virtualMachine) applyMessage(evmEnv *evm.EVM, msg core.Message, version uint64) ([]byte, error) {
+	active := vm.gasPriceActive()
+	gp := vm.context.GasPool
+	if gp == nil || !active {
+		gp = new(state.GasPool).AddGas(math.MaxUint64)
+	}
+
+	// a failed message keeps its gas purchase, the payment of the coinbase and
+	// the gas used
+	ret, gas, failed, err := state.ApplyMessage(evmEnv, msg, gp)
+	if err != nil && (!failed || !active) {
+		log.WithField("error", err).Error("ApplyMessage to evm failed")
+		vm.revert(ret)
+		return ret, err
+	}
+	if failed {
+		log.WithField("error", err).Debug("evm message failed")
+		vm.revert(ret)
+	}
+
+	if version == 0 {
+		if err := vm.applyCost(int64(gas)); err != nil {
+			return ret, err
+		}
+	}
+	return ret, nil
+}
+
+// getHashFn returns the GetHashFunc of the EVM, the contexts of a block from
+// the BlockContextHeight walk the ancestors of the block instead of the best
+// chain.
+func (vm *virtualMachine) getHashFn() func(n uint64) common.Hash {
+	ctx := vm.context
+	if ctx.PrevBlockHash == nil || !vm.forkActive(consensus.ActiveNetParams.BlockContextHeight) {
+		return GetHashFn(ctx.Chain)
+	}
+
+	prevHash := common.BytesToHash(ctx.PrevBlockHash)
+	return func(n uint64) common.Hash {
+		return common.Hash(ctx.Chain.GetAncestorHash(prevHash, n))
+	}
+}
+
+// revert hands the return data of a failed contract message to the context
+func (vm *virtualMachine) revert(ret []byte) {
+	if vm.context.RevertData != nil {
+		vm.context.RevertData(ret)
+	}
+}
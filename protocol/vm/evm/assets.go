@@ -0,0 +1,186 @@
+package evm
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/doslink/doslink/consensus"
+)
+
+// AssetContractAddress is the reserved address of the precompiled contract
+// to query and transfer the balances of the issued assets, the balances are
+// kept in the storage of the address.
+var AssetContractAddress = common.BytesToAddress([]byte{1, 0})
+
+var (
+	balanceOfSelector = crypto.Keccak256([]byte("balanceOf(bytes32,address)"))[:4]
+	transferSelector  = crypto.Keccak256([]byte("transfer(bytes32,address,uint256)"))[:4]
+
+	// AssetTransferTopic is the topic of the log emitted by the transfers of
+	// the asset contract
+	AssetTransferTopic = crypto.Keccak256Hash([]byte("Transfer(bytes32,address,address,uint256)"))
+)
+
+// StatefulPrecompiledContract is the native contract that reads and writes the
+// state of the EVM it runs on.
+type StatefulPrecompiledContract interface {
+	RequiredGas(input []byte) uint64
+	Run(evm *EVM, contract *Contract, input []byte) ([]byte, error)
+}
+
+var StatefulPrecompiledContracts = map[common.Address]StatefulPrecompiledContract{
+	AssetContractAddress:           &assetContract{},
+	AssetDefinitionContractAddress: &assetDefinition{},
+	UtxoContractAddress:            &utxoExists{},
+}
+
+// Rules tells the forks of the chain active at the block, the EVM of a block
+// below a fork runs without the contracts of the fork.
+type Rules struct {
+	IsMultiAsset bool // Enables the asset precompiled contract
+	IsChainData  bool // Enables the precompiled contracts of the chain data
+}
+
+// statefulPrecompile returns the stateful precompiled contract of the address
+// active at the block of the EVM.
+func (evm *EVM) statefulPrecompile(addr common.Address) StatefulPrecompiledContract {
+	switch addr {
+	case AssetContractAddress:
+		if !evm.Rules.IsMultiAsset {
+			return nil
+		}
+	case AssetDefinitionContractAddress, UtxoContractAddress:
+		if !evm.Rules.IsChainData {
+			return nil
+		}
+	}
+	return StatefulPrecompiledContracts[addr]
+}
+
+// RunStatefulPrecompiledContract runs and evaluates the output of a stateful
+// precompiled contract.
+func RunStatefulPrecompiledContract(evm *EVM, p StatefulPrecompiledContract, input []byte, contract *Contract) ([]byte, error) {
+	gas := p.RequiredGas(input)
+	if contract.UseGas(gas) {
+		return p.Run(evm, contract, input)
+	}
+	return nil, ErrOutOfGas
+}
+
+func isNativeAsset(assetID common.Hash) bool {
+	return bytes.Equal(assetID.Bytes(), consensus.NativeAssetID.Bytes())
+}
+
+func assetBalanceKey(assetID common.Hash, addr common.Address) common.Hash {
+	return crypto.Keccak256Hash(assetID.Bytes(), addr.Bytes())
+}
+
+// GetAssetBalance returns the balance of the asset held by the address, the
+// native asset is the balance of the account.
+func GetAssetBalance(db StateDB, assetID common.Hash, addr common.Address) *big.Int {
+	if isNativeAsset(assetID) {
+		return db.GetBalance(addr)
+	}
+	return db.GetState(AssetContractAddress, assetBalanceKey(assetID, addr)).Big()
+}
+
+// CanTransferAsset checks whether the address holds enough of the asset to
+// make a transfer.
+func CanTransferAsset(db StateDB, assetID common.Hash, addr common.Address, amount *big.Int) bool {
+	return GetAssetBalance(db, assetID, addr).Cmp(amount) >= 0
+}
+
+// AddAssetBalance adds the amount of the asset to the address.
+func AddAssetBalance(db StateDB, assetID common.Hash, addr common.Address, amount *big.Int) {
+	if isNativeAsset(assetID) {
+		db.AddBalance(addr, amount)
+		return
+	}
+	setAssetBalance(db, assetID, addr, new(big.Int).Add(GetAssetBalance(db, assetID, addr), amount))
+}
+
+// SubAssetBalance subtracts the amount of the asset from the address, the
+// caller checks the balance with CanTransferAsset first.
+func SubAssetBalance(db StateDB, assetID common.Hash, addr common.Address, amount *big.Int) {
+	if isNativeAsset(assetID) {
+		db.SubBalance(addr, amount)
+		return
+	}
+	setAssetBalance(db, assetID, addr, new(big.Int).Sub(GetAssetBalance(db, assetID, addr), amount))
+}
+
+func setAssetBalance(db StateDB, assetID common.Hash, addr common.Address, balance *big.Int) {
+	keepAccount(db, AssetContractAddress)
+	db.SetState(AssetContractAddress, assetBalanceKey(assetID, addr), common.BigToHash(balance))
+}
+
+// keepAccount sets the nonce of the precompiled contract which keeps its data
+// in its storage, it keeps the account from being deleted as an empty account
+func keepAccount(db StateDB, addr common.Address) {
+	if db.GetNonce(addr) == 0 {
+		db.SetNonce(addr, 1)
+	}
+}
+
+// assetContract implements balanceOf(bytes32 assetId, address owner) and
+// transfer(bytes32 assetId, address to, uint256 amount) of the issued assets.
+type assetContract struct{}
+
+func (c *assetContract) RequiredGas(input []byte) uint64 {
+	if len(input) >= 4 && bytes.Equal(input[:4], transferSelector) {
+		return 2*SstoreResetGas + LogGas + 3*LogTopicGas
+	}
+	return SloadGas
+}
+
+func (c *assetContract) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if len(input) < 4 {
+		return nil, errExecutionReverted
+	}
+
+	selector, args := input[:4], input[4:]
+	switch {
+	case bytes.Equal(selector, balanceOfSelector) && len(args) == 64:
+		assetID := common.BytesToHash(args[:32])
+		owner := common.BytesToAddress(args[32:64])
+		return common.BigToHash(GetAssetBalance(evm.StateDB, assetID, owner)).Bytes(), nil
+
+	case bytes.Equal(selector, transferSelector) && len(args) == 96:
+		return c.transfer(evm, contract, args)
+	}
+	return nil, errExecutionReverted
+}
+
+func (c *assetContract) transfer(evm *EVM, contract *Contract, args []byte) ([]byte, error) {
+	if evm.interpreter.IsReadOnly() {
+		return nil, errWriteProtection
+	}
+	// a delegate call or call code would spend the assets of its own caller
+	if contract.Address() != AssetContractAddress {
+		return nil, errExecutionReverted
+	}
+
+	var (
+		assetID = common.BytesToHash(args[:32])
+		from    = contract.Caller()
+		to      = common.BytesToAddress(args[32:64])
+		amount  = new(big.Int).SetBytes(args[64:96])
+	)
+	if !CanTransferAsset(evm.StateDB, assetID, from, amount) {
+		return nil, errExecutionReverted
+	}
+	SubAssetBalance(evm.StateDB, assetID, from, amount)
+	AddAssetBalance(evm.StateDB, assetID, to, amount)
+
+	evm.StateDB.AddLog(&types.Log{
+		Address:     AssetContractAddress,
+		Topics:      []common.Hash{AssetTransferTopic, assetID, from.Hash()},
+		Data:        append(to.Hash().Bytes(), args[64:96]...),
+		BlockNumber: evm.BlockNumber.Uint64(),
+	})
+	return common.BigToHash(common.Big1).Bytes(), nil
+}
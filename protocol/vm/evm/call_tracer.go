@@ -0,0 +1,177 @@
+package evm
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	errCallReverted = errors.New("execution reverted")
+	errCallFailed   = errors.New("call failed")
+)
+
+// CallFrame is a message call or a contract creation captured by CallTracer
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value,omitempty"`
+	Gas     hexutil.Uint64 `json:"gas"`
+	GasUsed hexutil.Uint64 `json:"gasUsed"`
+	Input   hexutil.Bytes  `json:"input"`
+	Output  hexutil.Bytes  `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*CallFrame   `json:"calls,omitempty"`
+
+	depth int    // interpreter depth the frame code runs at
+	gasIn uint64 // gas of the caller before the call opcode
+}
+
+// CallTracer is a Tracer building the tree of the message calls made during
+// the execution. The contract opcodes run every message on its own EVM, so
+// the tracer keeps one root frame per EVM execution.
+type CallTracer struct {
+	calls []*CallFrame
+	stack []*CallFrame
+}
+
+// NewCallTracer returns a new call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// Calls returns the root frames in execution order
+func (t *CallTracer) Calls() []*CallFrame { return t.calls }
+
+// CaptureStart implements the Tracer interface, it opens the root frame.
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	frame := &CallFrame{
+		Type:  "CALL",
+		From:  from,
+		To:    to,
+		Gas:   hexutil.Uint64(gas),
+		Input: common.CopyBytes(input),
+		depth: 1,
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	if value != nil {
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(value))
+	}
+
+	t.calls = append(t.calls, frame)
+	t.stack = []*CallFrame{frame}
+	return nil
+}
+
+// CaptureState implements the Tracer interface, it opens a frame on every
+// call opcode and closes the frames that returned to the caller.
+func (t *CallTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if err != nil {
+		return t.CaptureFault(env, pc, op, gas, cost, memory, stack, contract, depth, err)
+	}
+	if len(t.stack) == 0 {
+		return nil
+	}
+
+	t.exit(depth, gas, stack)
+	frame := &CallFrame{From: contract.Address(), Gas: hexutil.Uint64(gas - cost), depth: depth + 1, gasIn: gas}
+	switch op {
+	case CREATE, CREATE2:
+		frame.Type = "CREATE"
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(0)))
+		frame.Input = memory.Get(stack.Back(1).Int64(), stack.Back(2).Int64())
+
+	case CALL, CALLCODE:
+		frame.Type = op.String()
+		frame.To = common.BigToAddress(stack.Back(1))
+		frame.Value = (*hexutil.Big)(new(big.Int).Set(stack.Back(2)))
+		frame.Input = memory.Get(stack.Back(3).Int64(), stack.Back(4).Int64())
+
+	case DELEGATECALL, STATICCALL:
+		frame.Type = op.String()
+		frame.To = common.BigToAddress(stack.Back(1))
+		frame.Input = memory.Get(stack.Back(2).Int64(), stack.Back(3).Int64())
+
+	case RETURN, REVERT:
+		current := t.stack[len(t.stack)-1]
+		current.Output = memory.Get(stack.Back(0).Int64(), stack.Back(1).Int64())
+		if op == REVERT {
+			current.Error = errCallReverted.Error()
+		}
+		return nil
+
+	case SELFDESTRUCT:
+		current := t.stack[len(t.stack)-1]
+		current.Calls = append(current.Calls, &CallFrame{
+			Type:  op.String(),
+			From:  contract.Address(),
+			To:    common.BigToAddress(stack.Back(0)),
+			Value: (*hexutil.Big)(env.StateDB.GetBalance(contract.Address())),
+		})
+		return nil
+
+	default:
+		return nil
+	}
+
+	parent := t.stack[len(t.stack)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.stack = append(t.stack, frame)
+	return nil
+}
+
+// exit closes the frames deeper than the depth of the running code. The
+// frame returning to the caller reads its result from the caller stack, and
+// the gas used covers the call cost charged to the caller.
+func (t *CallTracer) exit(depth int, gas uint64, stack *Stack) {
+	for len(t.stack) > 1 && t.stack[len(t.stack)-1].depth > depth {
+		frame := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		if frame.depth != depth+1 || stack.len() == 0 {
+			continue
+		}
+
+		frame.GasUsed = hexutil.Uint64(frame.gasIn - gas)
+		ret := stack.peek()
+		if frame.Type == "CREATE" && ret.Sign() != 0 {
+			frame.To = common.BigToAddress(ret)
+		}
+		if ret.Sign() == 0 && frame.Error == "" {
+			frame.Error = errCallFailed.Error()
+		}
+	}
+}
+
+// CaptureFault implements the Tracer interface, it records the error on the
+// frame running at the depth.
+func (t *CallTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	for i := len(t.stack) - 1; i >= 0; i-- {
+		if t.stack[i].depth == depth {
+			t.stack[i].Error = err.Error()
+			break
+		}
+	}
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface, it closes the root frame.
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if len(t.stack) == 0 {
+		return nil
+	}
+
+	root := t.stack[0]
+	root.GasUsed = hexutil.Uint64(gasUsed)
+	root.Output = common.CopyBytes(output)
+	if err != nil {
+		root.Error = err.Error()
+	}
+	t.stack = nil
+	return nil
+}
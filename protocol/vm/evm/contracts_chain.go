@@ -0,0 +1,132 @@
+package evm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/doslink/doslink/basis/crypto/ed25519"
+	"github.com/doslink/doslink/basis/crypto/sha3pool"
+	"github.com/doslink/doslink/basis/crypto/sm3"
+)
+
+var (
+	// AssetDefinitionContractAddress is the reserved address of the precompiled
+	// contract to look up the definitions of the issued assets, the definition
+	// hashes are kept in the storage of the address.
+	AssetDefinitionContractAddress = common.BytesToAddress([]byte{1, 1})
+	// UtxoContractAddress is the reserved address of the precompiled contract
+	// to check the existence of the outputs in the UTXO set.
+	UtxoContractAddress = common.BytesToAddress([]byte{1, 2})
+)
+
+// ChainPrecompiledContracts are the precompiled contracts of the keys and the
+// hashes of the chain, they are active from the ChainDataHeight.
+var ChainPrecompiledContracts = map[common.Address]PrecompiledContract{
+	common.BytesToAddress([]byte{1, 3}): &ed25519Verify{},
+	common.BytesToAddress([]byte{1, 4}): &sm3hash{},
+}
+
+// precompile returns the precompiled contract of the address active at the
+// block of the EVM.
+func (evm *EVM) precompile(addr common.Address) PrecompiledContract {
+	if p := PrecompiledContracts[addr]; p != nil {
+		return p
+	}
+	if evm.Rules.IsChainData {
+		return ChainPrecompiledContracts[addr]
+	}
+	return nil
+}
+
+func boolBytes(b bool) []byte {
+	if b {
+		return true32Byte
+	}
+	return false32Byte
+}
+
+// SetAssetDefinition records the definition hash of the asset at its first
+// issuance, the later issuances carry the same definition of the asset ID.
+func SetAssetDefinition(db StateDB, assetID, definitionHash common.Hash) {
+	if db.GetState(AssetDefinitionContractAddress, assetID) != (common.Hash{}) {
+		return
+	}
+	keepAccount(db, AssetDefinitionContractAddress)
+	db.SetState(AssetDefinitionContractAddress, assetID, definitionHash)
+}
+
+// GetAssetDefinition returns the definition hash of the issued asset, it is
+// zero for the assets never issued.
+func GetAssetDefinition(db StateDB, assetID common.Hash) common.Hash {
+	return db.GetState(AssetDefinitionContractAddress, assetID)
+}
+
+// assetDefinition looks up the asset definitions implemented as a native
+// contract. The input of the asset ID returns the definition hash of the
+// asset, the input of the asset ID followed by the raw definition returns
+// whether the definition is the one of the asset.
+type assetDefinition struct{}
+
+func (c *assetDefinition) RequiredGas(input []byte) uint64 {
+	if len(input) <= 32 {
+		return AssetDefinitionGas
+	}
+	return uint64(len(input)-32+31)/32*AssetDefinitionWordGas + AssetDefinitionGas
+}
+
+func (c *assetDefinition) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	input = common.RightPadBytes(input, 32)
+	definitionHash := GetAssetDefinition(evm.StateDB, common.BytesToHash(input[:32]))
+	if len(input) == 32 {
+		return definitionHash.Bytes(), nil
+	}
+
+	var rawHash [32]byte
+	sha3pool.Sum256(rawHash[:], input[32:])
+	return boolBytes(definitionHash != common.Hash{} && rawHash == definitionHash), nil
+}
+
+// utxoExists checks the output ID of the input is unspent implemented as a
+// native contract.
+type utxoExists struct{}
+
+func (c *utxoExists) RequiredGas(input []byte) uint64 {
+	return UtxoExistsGas
+}
+
+func (c *utxoExists) Run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
+	if len(input) != 32 || evm.Context.HasUtxo == nil {
+		return false32Byte, nil
+	}
+	return boolBytes(evm.Context.HasUtxo(common.BytesToHash(input))), nil
+}
+
+// ed25519Verify verifies the ed25519 signatures implemented as a native
+// contract, the input is the public key, the signature and the message. The
+// signatures of the chainkd keys are verified with their derived public keys.
+type ed25519Verify struct{}
+
+func (c *ed25519Verify) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)+31)/32*Ed25519VerifyPerWordGas + Ed25519VerifyBaseGas
+}
+
+func (c *ed25519Verify) Run(input []byte) ([]byte, error) {
+	const signedInputLength = ed25519.PublicKeySize + ed25519.SignatureSize
+	if len(input) < signedInputLength {
+		return false32Byte, nil
+	}
+
+	pub := ed25519.PublicKey(input[:ed25519.PublicKeySize])
+	sig := input[ed25519.PublicKeySize:signedInputLength]
+	return boolBytes(ed25519.Verify(pub, input[signedInputLength:], sig)), nil
+}
+
+// SM3 implemented as a native contract.
+type sm3hash struct{}
+
+func (c *sm3hash) RequiredGas(input []byte) uint64 {
+	return uint64(len(input)+31)/32*Sm3PerWordGas + Sm3BaseGas
+}
+
+func (c *sm3hash) Run(input []byte) ([]byte, error) {
+	return sm3.Sm3Sum(input), nil
+}
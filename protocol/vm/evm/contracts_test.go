@@ -22,6 +22,10 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/doslink/doslink/basis/crypto/sha3pool"
 )
 
 // precompiledTest defines the input/output pairs for precompiled contract tests.
@@ -336,8 +340,50 @@ var bn256PairingTests = []precompiledTest{
 	},
 }
 
+// ed25519VerifyTests are the test and benchmark data for the ed25519 signature
+// verification precompiled contract, the key is of the zero seed.
+var ed25519VerifyTests = []precompiledTest{
+	{
+		input: "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29" +
+			"df5d75b33ec4e0e54996a6ff0cec78d8eb6f0c01c1d15df84347bc2041ed5804" +
+			"c518d4babd2d5b4598e9c2d580438a660671aa14611ed58b58a70a2a4e05b50a" +
+			"646f736c696e6b",
+		expected: "0000000000000000000000000000000000000000000000000000000000000001",
+		name:     "valid",
+	}, {
+		input: "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29" +
+			"df5d75b33ec4e0e54996a6ff0cec78d8eb6f0c01c1d15df84347bc2041ed5804" +
+			"c518d4babd2d5b4598e9c2d580438a660671aa14611ed58b58a70a2a4e05b50a" +
+			"646f736c696e6c",
+		expected:    "0000000000000000000000000000000000000000000000000000000000000000",
+		name:        "wrong_message",
+		noBenchmark: true,
+	}, {
+		input:       "3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+		expected:    "0000000000000000000000000000000000000000000000000000000000000000",
+		name:        "short_input",
+		noBenchmark: true,
+	},
+}
+
+// sm3Tests are the test and benchmark data for the SM3 precompiled contract.
+var sm3Tests = []precompiledTest{
+	{
+		input:    "616263",
+		expected: "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0",
+		name:     "abc",
+	},
+}
+
+// precompiledContract returns the precompiled contract of the address active
+// at all of the forks.
+func precompiledContract(addr common.Address) PrecompiledContract {
+	evm := NewEVM(Context{Rules: Rules{IsMultiAsset: true, IsChainData: true}}, nil, Config{})
+	return evm.precompile(addr)
+}
+
 func testPrecompiled(addr string, test precompiledTest, t *testing.T) {
-	p := PrecompiledContractsByzantium[common.HexToAddress(addr)]
+	p := precompiledContract(common.HexToAddress(addr))
 	in := common.Hex2Bytes(test.input)
 	contract := NewContract(AccountRef(common.HexToAddress("1337")),
 		nil, new(big.Int), p.RequiredGas(in))
@@ -354,7 +400,7 @@ func benchmarkPrecompiled(addr string, test precompiledTest, bench *testing.B) {
 	if test.noBenchmark {
 		return
 	}
-	p := PrecompiledContractsByzantium[common.HexToAddress(addr)]
+	p := precompiledContract(common.HexToAddress(addr))
 	in := common.Hex2Bytes(test.input)
 	reqGas := p.RequiredGas(in)
 	contract := NewContract(AccountRef(common.HexToAddress("1337")),
@@ -481,3 +527,114 @@ func BenchmarkPrecompiledBn256Pairing(bench *testing.B) {
 		benchmarkPrecompiled("08", test, bench)
 	}
 }
+
+// Tests the ed25519 signature verification of the keys of the chain.
+func TestPrecompiledEd25519Verify(t *testing.T) {
+	for _, test := range ed25519VerifyTests {
+		testPrecompiled("0103", test, t)
+	}
+}
+
+// Benchmarks the ed25519 signature verification of the keys of the chain.
+func BenchmarkPrecompiledEd25519Verify(bench *testing.B) {
+	for _, test := range ed25519VerifyTests {
+		benchmarkPrecompiled("0103", test, bench)
+	}
+}
+
+// Tests the sample inputs of the SM3 hash.
+func TestPrecompiledSm3(t *testing.T) {
+	for _, test := range sm3Tests {
+		testPrecompiled("0104", test, t)
+	}
+}
+
+func runStatefulPrecompiled(t *testing.T, evm *EVM, addr common.Address, input []byte) []byte {
+	p := StatefulPrecompiledContracts[addr]
+	contract := NewContract(AccountRef(common.HexToAddress("1337")),
+		AccountRef(addr), new(big.Int), p.RequiredGas(input))
+	ret, err := RunStatefulPrecompiledContract(evm, p, input, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestPrecompiledAssetDefinition(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	evm := NewEVM(Context{}, statedb, Config{})
+
+	rawDefinition := []byte(`{"name":"GOLD"}`)
+	var definitionHash common.Hash
+	sha3pool.Sum256(definitionHash[:], rawDefinition)
+	assetID := common.HexToHash("0xa0")
+	SetAssetDefinition(statedb, assetID, definitionHash)
+	SetAssetDefinition(statedb, assetID, common.HexToHash("0xff"))
+
+	cases := []struct {
+		input []byte
+		want  []byte
+	}{
+		{input: assetID.Bytes(), want: definitionHash.Bytes()},
+		{input: common.HexToHash("0xa1").Bytes(), want: false32Byte},
+		{input: append(assetID.Bytes(), rawDefinition...), want: true32Byte},
+		{input: append(assetID.Bytes(), `{"name":"SILVER"}`...), want: false32Byte},
+		{input: append(common.HexToHash("0xa1").Bytes(), rawDefinition...), want: false32Byte},
+	}
+	for i, c := range cases {
+		if got := runStatefulPrecompiled(t, evm, AssetDefinitionContractAddress, c.input); common.Bytes2Hex(got) != common.Bytes2Hex(c.want) {
+			t.Errorf("case %d: got %x, want %x", i, got, c.want)
+		}
+	}
+}
+
+func TestPrecompiledUtxoExists(t *testing.T) {
+	unspent := common.HexToHash("0x01")
+	evm := NewEVM(Context{HasUtxo: func(outputID common.Hash) bool { return outputID == unspent }}, nil, Config{})
+
+	cases := []struct {
+		input []byte
+		want  []byte
+	}{
+		{input: unspent.Bytes(), want: true32Byte},
+		{input: common.HexToHash("0x02").Bytes(), want: false32Byte},
+		{input: unspent.Bytes()[1:], want: false32Byte},
+	}
+	for i, c := range cases {
+		if got := runStatefulPrecompiled(t, evm, UtxoContractAddress, c.input); common.Bytes2Hex(got) != common.Bytes2Hex(c.want) {
+			t.Errorf("case %d: got %x, want %x", i, got, c.want)
+		}
+	}
+}
+
+func TestPrecompileRules(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	caller := AccountRef(common.HexToAddress("1337"))
+
+	cases := []struct {
+		rules  Rules
+		active bool
+	}{
+		{rules: Rules{IsMultiAsset: true}},
+		{rules: Rules{IsChainData: true}, active: true},
+	}
+	for _, c := range cases {
+		evm := NewEVM(Context{
+			CanTransfer: func(StateDB, common.Address, *big.Int) bool { return true },
+			Transfer:    func(StateDB, common.Address, common.Address, *big.Int) {},
+			HasUtxo:     func(common.Hash) bool { return true },
+			BlockNumber: new(big.Int),
+			Rules:       c.rules,
+		}, statedb, Config{})
+
+		for _, addr := range []common.Address{common.BytesToAddress([]byte{1, 4}), UtxoContractAddress} {
+			ret, _, err := evm.Call(caller, addr, make([]byte, 32), 100000, new(big.Int))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if active := len(ret) > 0; active != c.active {
+				t.Errorf("rules %+v: got contract %x active %v, want %v", c.rules, addr, active, c.active)
+			}
+		}
+	}
+}
@@ -37,15 +37,20 @@ type (
 	// GetHashFunc returns the nth block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
 	GetHashFunc func(uint64) common.Hash
+	// HasUtxoFunc reports whether the output is in the UTXO set of the chain
+	// and is used by the UTXO precompiled contract.
+	HasUtxoFunc func(common.Hash) bool
 )
 
 // run runs the given contract and takes care of running precompiles with a fallback to the byte code interpreter.
 func run(evm *EVM, contract *Contract, input []byte) ([]byte, error) {
 	if contract.CodeAddr != nil {
-		precompiles := PrecompiledContracts
-		if p := precompiles[*contract.CodeAddr]; p != nil {
+		if p := evm.precompile(*contract.CodeAddr); p != nil {
 			return RunPrecompiledContract(p, input, contract)
 		}
+		if p := evm.statefulPrecompile(*contract.CodeAddr); p != nil {
+			return RunStatefulPrecompiledContract(evm, p, input, contract)
+		}
 	}
 	for _, interpreter := range evm.interpreters {
 		if interpreter.CanRun(contract.Code) {
@@ -73,6 +78,8 @@ type Context struct {
 	Transfer TransferFunc
 	// GetHash returns the hash corresponding to n
 	GetHash GetHashFunc
+	// HasUtxo returns whether the output ID is unspent
+	HasUtxo HasUtxoFunc
 
 	// Message information
 	Origin   common.Address // Provides information for ORIGIN
@@ -84,6 +91,9 @@ type Context struct {
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
 	Difficulty  *big.Int       // Provides information for DIFFICULTY
+
+	// Fork information
+	Rules Rules // Provides the forks active at the block
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -169,8 +179,7 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		snapshot = evm.StateDB.Snapshot()
 	)
 	if !evm.StateDB.Exist(addr) {
-		precompiles := PrecompiledContracts
-		if precompiles[addr] == nil && value.Sign() == 0 {
+		if evm.precompile(addr) == nil && evm.statefulPrecompile(addr) == nil && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
@@ -90,6 +90,13 @@ const (
 	Bn256ScalarMulGas       uint64 = 40000  // Gas needed for an elliptic curve scalar multiplication
 	Bn256PairingBaseGas     uint64 = 100000 // Base price for an elliptic curve pairing check
 	Bn256PairingPerPointGas uint64 = 80000  // Per-point price for an elliptic curve pairing check
+	AssetDefinitionGas      uint64 = 200    // Gas needed for an asset definition lookup
+	AssetDefinitionWordGas  uint64 = 12     // Per-word price for hashing the raw asset definition
+	UtxoExistsGas           uint64 = 800    // Gas needed for a UTXO set lookup
+	Ed25519VerifyBaseGas    uint64 = 2000   // Base price for an ed25519 signature verification
+	Ed25519VerifyPerWordGas uint64 = 12     // Per-word price of the message of an ed25519 signature verification
+	Sm3BaseGas              uint64 = 60     // Base price for a SM3 operation
+	Sm3PerWordGas           uint64 = 12     // Per-word price for a SM3 operation
 )
 
 // calcGas returns the actual gas cost of the call.
@@ -0,0 +1,133 @@
+package evm
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// AccountState is the state of an account touched during the execution,
+// storage only holds the slots accessed by the execution.
+type AccountState struct {
+	Balance *hexutil.Big                `json:"balance,omitempty"`
+	Nonce   *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// PrestateTracer is a Tracer recording the state of every account and
+// storage slot before the execution first touches it. Diff compares the
+// recorded state with the state after the execution.
+type PrestateTracer struct {
+	stateDB StateDB
+	pre     map[common.Address]*AccountState
+}
+
+// NewPrestateTracer returns a prestate tracer reading the state being executed on
+func NewPrestateTracer(stateDB StateDB) *PrestateTracer {
+	return &PrestateTracer{
+		stateDB: stateDB,
+		pre:     make(map[common.Address]*AccountState),
+	}
+}
+
+func (t *PrestateTracer) accountState(addr common.Address) *AccountState {
+	nonce := hexutil.Uint64(t.stateDB.GetNonce(addr))
+	return &AccountState{
+		Balance: (*hexutil.Big)(new(big.Int).Set(t.stateDB.GetBalance(addr))),
+		Nonce:   &nonce,
+		Code:    common.CopyBytes(t.stateDB.GetCode(addr)),
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+func (t *PrestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.pre[addr]; !ok {
+		t.pre[addr] = t.accountState(addr)
+	}
+}
+
+func (t *PrestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+	if _, ok := t.pre[addr].Storage[key]; !ok {
+		t.pre[addr].Storage[key] = t.stateDB.GetState(addr, key)
+	}
+}
+
+// CaptureStart implements the Tracer interface, it records the sender and
+// the receiver of the message.
+func (t *PrestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+	return nil
+}
+
+// CaptureState implements the Tracer interface, it records the accounts and
+// the storage slots the opcode reads or writes.
+func (t *PrestateTracer) CaptureState(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+
+	switch op {
+	case SLOAD, SSTORE:
+		t.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)))
+	case BALANCE, EXTCODESIZE, EXTCODECOPY:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+	case CALL, CALLCODE, DELEGATECALL, STATICCALL:
+		t.lookupAccount(common.BigToAddress(stack.Back(1)))
+	case CREATE, CREATE2:
+		t.lookupAccount(contract.Address())
+	case SELFDESTRUCT:
+		t.lookupAccount(contract.Address())
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+	}
+	return nil
+}
+
+// CaptureFault implements the Tracer interface
+func (t *PrestateTracer) CaptureFault(env *EVM, pc uint64, op OpCode, gas, cost uint64, memory *Memory, stack *Stack, contract *Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements the Tracer interface
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// Prestate returns the recorded state of the touched accounts
+func (t *PrestateTracer) Prestate() map[common.Address]*AccountState { return t.pre }
+
+// Diff returns the touched accounts changed by the execution, pre holds
+// the fields before and post the fields after the execution. It must be
+// called once the execution has finished.
+func (t *PrestateTracer) Diff() (pre, post map[common.Address]*AccountState) {
+	pre = make(map[common.Address]*AccountState)
+	post = make(map[common.Address]*AccountState)
+	for addr, before := range t.pre {
+		after := t.accountState(addr)
+		preDiff, postDiff := &AccountState{Storage: make(map[common.Hash]common.Hash)}, &AccountState{Storage: make(map[common.Hash]common.Hash)}
+		changed := false
+		if before.Balance.ToInt().Cmp(after.Balance.ToInt()) != 0 {
+			preDiff.Balance, postDiff.Balance, changed = before.Balance, after.Balance, true
+		}
+		if *before.Nonce != *after.Nonce {
+			preDiff.Nonce, postDiff.Nonce, changed = before.Nonce, after.Nonce, true
+		}
+		if string(before.Code) != string(after.Code) {
+			preDiff.Code, postDiff.Code, changed = before.Code, after.Code, true
+		}
+		for key, value := range before.Storage {
+			if current := t.stateDB.GetState(addr, key); current != value {
+				preDiff.Storage[key], postDiff.Storage[key], changed = value, current, true
+			}
+		}
+
+		if changed {
+			pre[addr], post[addr] = preDiff, postDiff
+		}
+	}
+	return pre, post
+}
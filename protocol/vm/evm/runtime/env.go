@@ -35,6 +35,7 @@ func NewEnv(cfg *Config) *evm.EVM {
 		Difficulty:  cfg.Difficulty,
 		GasLimit:    cfg.GasLimit,
 		GasPrice:    cfg.GasPrice,
+		Rules:       cfg.Rules,
 	}
 
 	return evm.NewEVM(context, cfg.State, cfg.EVMConfig)
@@ -41,6 +41,7 @@ type Config struct {
 	Value       *big.Int
 	Debug       bool
 	EVMConfig   evm.Config
+	Rules       evm.Rules
 
 	State     *state.StateDB
 	GetHashFn func(n uint64) common.Hash
@@ -25,7 +25,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/doslink/doslink/protocol/vm/evm"
 )
 
 func TestDefaults(t *testing.T) {
@@ -116,6 +119,81 @@ func TestCall(t *testing.T) {
 	}
 }
 
+// tracedCall calls 0x0a, which calls 0x0b storing 1 at slot 0 and returning 42
+func tracedCall(t *testing.T, tracer evm.Tracer, statedb *state.StateDB) {
+	statedb.SetCode(common.HexToAddress("0x0a"), []byte{
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0,
+		byte(vm.PUSH1), 0x0b,
+		byte(vm.GAS),
+		byte(vm.CALL),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	})
+	statedb.SetCode(common.HexToAddress("0x0b"), []byte{
+		byte(vm.PUSH1), 1,
+		byte(vm.PUSH1), 0,
+		byte(vm.SSTORE),
+		byte(vm.PUSH1), 42,
+		byte(vm.PUSH1), 0,
+		byte(vm.MSTORE),
+		byte(vm.PUSH1), 32,
+		byte(vm.PUSH1), 0,
+		byte(vm.RETURN),
+	})
+
+	cfg := &Config{State: statedb, EVMConfig: evm.Config{Debug: true, Tracer: tracer}}
+	if _, _, err := Call(common.HexToAddress("0x0a"), nil, cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallTracer(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	tracer := evm.NewCallTracer()
+	tracedCall(t, tracer, statedb)
+
+	calls := tracer.Calls()
+	if len(calls) != 1 || len(calls[0].Calls) != 1 {
+		t.Fatalf("got %d root frames, want one root frame with one call", len(calls))
+	}
+
+	root, inner := calls[0], calls[0].Calls[0]
+	if root.To != common.HexToAddress("0x0a") || inner.From != root.To || inner.To != common.HexToAddress("0x0b") {
+		t.Errorf("got call %x -> %x, %x -> %x", root.From, root.To, inner.From, inner.To)
+	}
+	if inner.Type != "CALL" || inner.Error != "" || new(big.Int).SetBytes(inner.Output).Int64() != 42 {
+		t.Errorf("got inner call %s, output %x, error %q", inner.Type, []byte(inner.Output), inner.Error)
+	}
+	if inner.GasUsed == 0 || uint64(inner.GasUsed) > uint64(root.GasUsed) {
+		t.Errorf("got inner gas used %d, root gas used %d", inner.GasUsed, root.GasUsed)
+	}
+}
+
+func TestPrestateTracer(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	tracer := evm.NewPrestateTracer(statedb)
+	tracedCall(t, tracer, statedb)
+
+	callee := common.HexToAddress("0x0b")
+	if _, ok := tracer.Prestate()[callee]; !ok {
+		t.Fatal("callee not recorded in the prestate")
+	}
+
+	pre, post := tracer.Diff()
+	if len(pre) != 1 || len(post) != 1 {
+		t.Fatalf("got %d changed accounts, want 1", len(post))
+	}
+	slot := common.Hash{}
+	if pre[callee].Storage[slot] != (common.Hash{}) || post[callee].Storage[slot] != common.BigToHash(big.NewInt(1)) {
+		t.Errorf("got slot %x -> %x", pre[callee].Storage[slot], post[callee].Storage[slot])
+	}
+}
+
 func BenchmarkCall(b *testing.B) {
 	var definition = `[{"constant":true,"inputs":[],"name":"seller","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"abort","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"value","outputs":[{"name":"","type":"uint256"}],"type":"function"},{"constant":false,"inputs":[],"name":"refund","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"buyer","outputs":[{"name":"","type":"address"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmReceived","outputs":[],"type":"function"},{"constant":true,"inputs":[],"name":"state","outputs":[{"name":"","type":"uint8"}],"type":"function"},{"constant":false,"inputs":[],"name":"confirmPurchase","outputs":[],"type":"function"},{"inputs":[],"type":"constructor"},{"anonymous":false,"inputs":[],"name":"Aborted","type":"event"},{"anonymous":false,"inputs":[],"name":"PurchaseConfirmed","type":"event"},{"anonymous":false,"inputs":[],"name":"ItemReceived","type":"event"},{"anonymous":false,"inputs":[],"name":"Refunded","type":"event"}]`
 
@@ -148,3 +226,49 @@ func BenchmarkCall(b *testing.B) {
 		}
 	}
 }
+
+func TestAssetContract(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	var (
+		asset = common.HexToHash("0x1234")
+		from  = common.HexToAddress("0x0a")
+		to    = common.HexToAddress("0x0b")
+	)
+	evm.AddAssetBalance(statedb, asset, from, big.NewInt(100))
+
+	rules := evm.Rules{IsMultiAsset: true}
+	transfer := func(amount int64, rules evm.Rules) error {
+		input := append(crypto.Keccak256([]byte("transfer(bytes32,address,uint256)"))[:4], asset.Bytes()...)
+		input = append(input, to.Hash().Bytes()...)
+		input = append(input, common.BigToHash(big.NewInt(amount)).Bytes()...)
+		_, _, err := Call(evm.AssetContractAddress, input, &Config{State: statedb, Origin: from, Rules: rules})
+		return err
+	}
+	// the asset contract is an empty account below the fork
+	if err := transfer(40, evm.Rules{}); err != nil {
+		t.Fatal(err)
+	}
+	if balance := evm.GetAssetBalance(statedb, asset, from); balance.Int64() != 100 {
+		t.Fatalf("balance of the sender got %v before the fork, want 100", balance)
+	}
+	if err := transfer(40, rules); err != nil {
+		t.Fatal(err)
+	}
+	if err := transfer(61, rules); err == nil {
+		t.Fatal("transfer more than the balance succeeded")
+	}
+	statedb.Finalise(true)
+
+	input := append(crypto.Keccak256([]byte("balanceOf(bytes32,address)"))[:4], asset.Bytes()...)
+	input = append(input, to.Hash().Bytes()...)
+	ret, _, err := Call(evm.AssetContractAddress, input, &Config{State: statedb, Rules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := new(big.Int).SetBytes(ret); balance.Int64() != 40 {
+		t.Errorf("balance of the recipient got %v, want 40", balance)
+	}
+	if balance := evm.GetAssetBalance(statedb, asset, from); balance.Int64() != 60 {
+		t.Errorf("balance of the sender got %v, want 60", balance)
+	}
+}
@@ -0,0 +1,47 @@
+package vm
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol/vm/evm"
+)
+
+func TestDepositAsset(t *testing.T) {
+	defer func(params consensus.Params) { consensus.ActiveNetParams = params }(consensus.ActiveNetParams)
+	consensus.ActiveNetParams = consensus.Params{Name: "test", MultiAssetHeight: 100}
+
+	var (
+		assetID   = common.HexToHash("0x1234")
+		recipient = common.HexToAddress("0x0a")
+	)
+
+	cases := []struct {
+		height uint64
+		want   int64
+	}{
+		{height: 99, want: 0},
+		{height: 100, want: 5},
+	}
+
+	for _, c := range cases {
+		stateDB, err := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		height := c.height
+		vm := &virtualMachine{context: &Context{StateDB: stateDB, BlockHeight: &height}}
+		vm.depositAsset(assetID.Bytes(), 5, recipient)
+		if got := evm.GetAssetBalance(stateDB, assetID, recipient); got.Int64() != c.want {
+			t.Errorf("height %d: got balance %v, want %d", c.height, got, c.want)
+		}
+		if rules := NewRules(c.height); rules.IsMultiAsset != (c.want != 0) {
+			t.Errorf("height %d: got the asset contract active %v, want %v", c.height, rules.IsMultiAsset, c.want != 0)
+		}
+	}
+}
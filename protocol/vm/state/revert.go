@@ -0,0 +1,68 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// revertSelector is the selector of the solidity Error(string) revert payload
+	revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of the solidity Panic(uint256) revert payload
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the solidity descriptions of the panic codes
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assert(false)",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "enum overflow",
+	0x22: "invalid encoded storage byte array accessed",
+	0x31: "out-of-bounds array access; popping on an empty array",
+	0x32: "out-of-bounds access of an array or bytesN",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// UnpackRevert decodes the reason of the solidity Error(string) and
+// Panic(uint256) revert payloads, ok is false when the return data is none
+// of them.
+func UnpackRevert(ret []byte) (reason string, ok bool) {
+	if len(ret) < 4 {
+		return "", false
+	}
+
+	switch {
+	case bytes.Equal(ret[:4], revertSelector):
+		return unpackErrorString(ret[4:])
+
+	case bytes.Equal(ret[:4], panicSelector) && len(ret) == 4+32:
+		code := new(big.Int).SetBytes(ret[4:])
+		if desc, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			return fmt.Sprintf("panic: %s (0x%x)", desc, code), true
+		}
+		return fmt.Sprintf("panic: unknown code 0x%x", code), true
+	}
+	return "", false
+}
+
+func unpackErrorString(data []byte) (string, bool) {
+	if len(data) < 64 {
+		return "", false
+	}
+
+	offset := new(big.Int).SetBytes(data[:32])
+	if !offset.IsUint64() || offset.Uint64()+32 > uint64(len(data)) {
+		return "", false
+	}
+
+	start := offset.Uint64() + 32
+	size := new(big.Int).SetBytes(data[offset.Uint64():start])
+	if !size.IsUint64() || start+size.Uint64() > uint64(len(data)) {
+		return "", false
+	}
+	return string(data[start : start+size.Uint64()]), true
+}
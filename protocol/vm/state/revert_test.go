@@ -0,0 +1,45 @@
+package state
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestUnpackRevert(t *testing.T) {
+	cases := []struct {
+		ret    string
+		reason string
+		ok     bool
+	}{
+		{
+			// Error("not owner")
+			ret:    "08c379a0" + "0000000000000000000000000000000000000000000000000000000000000020" + "0000000000000000000000000000000000000000000000000000000000000009" + "6e6f74206f776e65720000000000000000000000000000000000000000000000",
+			reason: "not owner",
+			ok:     true,
+		},
+		{
+			// size out of the payload
+			ret: "08c379a0" + "0000000000000000000000000000000000000000000000000000000000000020" + "00000000000000000000000000000000000000000000000000000000000000ff" + "6e6f74206f776e65720000000000000000000000000000000000000000000000",
+		},
+		{
+			ret:    "4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011",
+			reason: "panic: arithmetic underflow or overflow (0x11)",
+			ok:     true,
+		},
+		{
+			ret:    "4e487b71" + "00000000000000000000000000000000000000000000000000000000000000ff",
+			reason: "panic: unknown code 0xff",
+			ok:     true,
+		},
+		{ret: "4e487b71" + "00"},
+		{ret: ""},
+	}
+
+	for i, c := range cases {
+		ret, _ := hex.DecodeString(c.ret)
+		reason, ok := UnpackRevert(ret)
+		if reason != c.reason || ok != c.ok {
+			t.Errorf("case %d: got (%q, %v), want (%q, %v)", i, reason, ok, c.reason, c.ok)
+		}
+	}
+}
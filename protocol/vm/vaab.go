@@ -1,12 +1,10 @@
 package vm
 
 import (
-	"bytes"
 	"encoding/hex"
 	"errors"
 	"math/big"
 
-	"github.com/doslink/doslink/consensus"
 	"github.com/doslink/doslink/protocol/vm/evm"
 
 	evm_common "github.com/ethereum/go-ethereum/common"
@@ -91,9 +89,8 @@ func opDeposit(vm *virtualMachine) error {
 		WithField("amount", assetAmount).
 		Infoln("Deposit")
 
-	if bytes.Compare(assetID, consensus.NativeAssetID.Bytes()) == 0 {
-		amount := new(big.Int).SetUint64(assetAmount)
-		stateDB.AddBalance(caller, amount)
+	if vm.assetActive(assetID) {
+		evm.AddAssetBalance(stateDB, evm_common.BytesToHash(assetID), caller, new(big.Int).SetUint64(assetAmount))
 	}
 
 	return vm.pushBool(true, false)
@@ -177,13 +174,14 @@ func opWithdraw(vm *virtualMachine) error {
 		WithField("amount", assetAmount).
 		Infoln("Withdraw")
 
-	if bytes.Compare(assetID, consensus.NativeAssetID.Bytes()) == 0 {
+	if vm.assetActive(assetID) {
+		asset := evm_common.BytesToHash(assetID)
 		amount := new(big.Int).SetUint64(assetAmount)
 		// Fail if we're trying to transfer more than the available balance
-		if !CanTransfer(stateDB, caller, amount) {
+		if !evm.CanTransferAsset(stateDB, asset, caller, amount) {
 			return evm.ErrInsufficientBalance
 		}
-		stateDB.SubBalance(caller, amount)
+		evm.SubAssetBalance(stateDB, asset, caller, amount)
 	}
 
 	return vm.pushBool(true, false)
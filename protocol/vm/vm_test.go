@@ -201,7 +201,7 @@ func TestVerifyTxInput(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		gasLeft, gotErr := Verify(c.vctx, 10000)
+		_, gasLeft, gotErr := Verify(c.vctx, 10000)
 		if errors.Root(gotErr) != c.wantErr {
 			t.Errorf("VerifyTxInput(%+v) err = %v want %v", c.vctx, gotErr, c.wantErr)
 		}
@@ -1,7 +1,10 @@
 package vmutil
 
 import (
+	"math/big"
+
 	"github.com/doslink/doslink/basis/crypto/ed25519"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
 	"github.com/doslink/doslink/basis/errors"
 	"github.com/doslink/doslink/protocol/vm"
 )
@@ -17,7 +20,7 @@ func IsUnspendable(prog []byte) bool {
 	return len(prog) > 0 && prog[0] == byte(vm.OP_FAIL)
 }
 
-func (b *Builder) addP2SPMultiSig(pubkeys []ed25519.PublicKey, nrequired int) error {
+func (b *Builder) addP2SPMultiSig(pubkeys [][]byte, nrequired int) error {
 	if err := checkMultiSigParams(int64(nrequired), int64(len(pubkeys))); err != nil {
 		return err
 	}
@@ -47,6 +50,14 @@ func P2WSHProgram(hash []byte) ([]byte, error) {
 	return builder.Build()
 }
 
+// P2WPKHProgram return the segwit pay to public key hash
+func P2WPKHProgram(hash []byte) ([]byte, error) {
+	builder := NewBuilder()
+	builder.AddInt64(1)
+	builder.AddData(hash)
+	return builder.Build()
+}
+
 // RetireProgram generates the script for retire output
 func RetireProgram(comment []byte) ([]byte, error) {
 	builder := NewBuilder()
@@ -71,8 +82,43 @@ func P2SHProgram(scriptHash []byte) ([]byte, error) {
 	return builder.Build()
 }
 
+// P2PKHSigProgram generates the script for control with public key hash, the
+// witness of the program is the signature and the public key
+func P2PKHSigProgram(pubkeyHash []byte) ([]byte, error) {
+	builder := NewBuilder()
+	builder.AddOp(vm.OP_DUP)
+	builder.AddOp(vm.OP_HASH160)
+	builder.AddData(pubkeyHash)
+	builder.AddOp(vm.OP_EQUALVERIFY)
+	builder.AddOp(vm.OP_TXSIGHASH)
+	builder.AddOp(vm.OP_SWAP)
+	builder.AddOp(vm.OP_CHECKSIG)
+	return builder.Build()
+}
+
 // P2SPMultiSigProgram generates the script for contorl transaction output
 func P2SPMultiSigProgram(pubkeys []ed25519.PublicKey, nrequired int) ([]byte, error) {
+	keys := make([][]byte, 0, len(pubkeys))
+	for _, p := range pubkeys {
+		keys = append(keys, p)
+	}
+
+	builder := NewBuilder()
+	if err := builder.addP2SPMultiSig(keys, nrequired); err != nil {
+		return nil, err
+	}
+	return builder.Build()
+}
+
+// P2SPSM2MultiSigProgram generates the script for contorl transaction output
+// with the compressed SM2 public keys
+func P2SPSM2MultiSigProgram(pubkeys [][]byte, nrequired int) ([]byte, error) {
+	for _, p := range pubkeys {
+		if !sm2kd.ValidPublicKey(p) {
+			return nil, errors.WithDetail(ErrBadValue, "invalid sm2 public key")
+		}
+	}
+
 	builder := NewBuilder()
 	if err := builder.addP2SPMultiSig(pubkeys, nrequired); err != nil {
 		return nil, err
@@ -162,6 +208,18 @@ func ContractProgram(input []byte) ([]byte, error) {
 	return builder.Build()
 }
 
+// EVMGasProgram builds the program of the EVM op which declares the gas limit
+// and the gas price of the message, it is the vm.GasPriceVersion program.
+func EVMGasProgram(op vm.Op, input []byte, gasLimit, gasPrice uint64) ([]byte, error) {
+	builder := NewBuilder()
+	builder.AddData(new(big.Int).SetUint64(gasPrice).Bytes())
+	builder.AddData(new(big.Int).SetUint64(gasLimit).Bytes())
+	builder.AddInt64(vm.GasPriceVersion)
+	builder.AddData(input)
+	builder.AddOp(op)
+	return builder.Build()
+}
+
 func P2ContractProgram(vmType int64, address []byte) ([]byte, error) {
 	builder := NewBuilder()
 	builder.AddOp(vm.OP_FAIL)
@@ -2,6 +2,11 @@ package vmutil
 
 import (
 	"testing"
+
+	"github.com/doslink/doslink/basis/crypto"
+	"github.com/doslink/doslink/basis/crypto/sm2/sm2kd"
+	"github.com/doslink/doslink/consensus"
+	"github.com/doslink/doslink/protocol/vm"
 )
 
 // TestIsUnspendable ensures the IsUnspendable function returns the expected
@@ -35,3 +40,101 @@ func TestIsUnspendable(t *testing.T) {
 		}
 	}
 }
+
+// TestEVMGasProgram ensures the programs declaring the gas of the message are
+// recognized as the programs of their EVM op.
+func TestEVMGasProgram(t *testing.T) {
+	input := []byte{0x60, 0x80, 0x60, 0x40}
+	tests := []struct {
+		op       vm.Op
+		gasLimit uint64
+		gasPrice uint64
+		isOp     func([]byte) bool
+	}{
+		{op: vm.OP_CREATE, gasLimit: 100000, gasPrice: 1, isOp: vm.IsOpCreate},
+		{op: vm.OP_CALL, gasLimit: 21000, gasPrice: 300, isOp: vm.IsOpCall},
+		{op: vm.OP_CONTRACT, gasLimit: 0, gasPrice: 0, isOp: vm.IsOpContract},
+	}
+
+	for i, test := range tests {
+		prog, err := EVMGasProgram(test.op, input, test.gasLimit, test.gasPrice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !test.isOp(prog) {
+			t.Errorf("TestEVMGasProgram #%d: program %x is not recognized", i, prog)
+		}
+
+		legacy, err := CallContractProgram(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.isOp(legacy) != (test.op == vm.OP_CALL) {
+			t.Errorf("TestEVMGasProgram #%d: call program %x recognized %v", i, legacy, test.isOp(legacy))
+		}
+	}
+}
+
+// TestSM2Programs ensures the pay to public key hash and the multisig programs
+// are spent with the SM2 signatures.
+func TestSM2Programs(t *testing.T) {
+	sigHash := make([]byte, 32)
+	sigHash[0] = 1
+
+	var (
+		pubkeys [][]byte
+		sigs    [][]byte
+	)
+	for i := 0; i < 3; i++ {
+		xprv, xpub, err := sm2kd.NewXKeys(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := xprv.Sign(sigHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubkeys = append(pubkeys, xpub.PublicKey())
+		sigs = append(sigs, sig)
+	}
+
+	p2pkh, err := P2PKHSigProgram(crypto.Ripemd160(pubkeys[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	multisig, err := P2SPSM2MultiSigProgram(pubkeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		code []byte
+		args [][]byte
+		ok   bool
+	}{
+		{code: p2pkh, args: [][]byte{sigs[0], pubkeys[0]}, ok: true},
+		{code: p2pkh, args: [][]byte{sigs[1], pubkeys[0]}, ok: false},
+		{code: p2pkh, args: [][]byte{sigs[1], pubkeys[1]}, ok: false},
+		{code: multisig, args: [][]byte{sigs[0], sigs[2]}, ok: true},
+		{code: multisig, args: [][]byte{sigs[2], sigs[0]}, ok: false},
+		{code: multisig, args: [][]byte{sigs[1], sigs[1]}, ok: false},
+	}
+
+	height := consensus.ActiveNetParams.SM2Height
+	for i, test := range tests {
+		_, _, err := vm.Verify(&vm.Context{
+			VMVersion:   1,
+			BlockHeight: &height,
+			Code:        test.code,
+			Arguments:   test.args,
+			TxSigHash:   func() []byte { return sigHash },
+		}, 100000)
+		if (err == nil) != test.ok {
+			t.Errorf("TestSM2Programs #%d: got err %v, want ok %v", i, err, test.ok)
+		}
+	}
+
+	if _, err := P2SPSM2MultiSigProgram([][]byte{pubkeys[0][1:]}, 1); err == nil {
+		t.Error("P2SPSM2MultiSigProgram accepts the invalid public key")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math/rand"
 
+	"github.com/doslink/doslink/protocol"
 	"github.com/doslink/doslink/protocol/bc"
 	"github.com/doslink/doslink/protocol/bc/types"
 )
@@ -89,6 +90,14 @@ func (c *Chain) GetTransactionStatus(hash *bc.Hash) (*bc.TransactionStatus, erro
 	return txStatus, nil
 }
 
+func (c *Chain) GetStateNode(hash *bc.Hash) ([]byte, error) {
+	return nil, errors.New("can't find state node")
+}
+
+func (c *Chain) HasState(header *types.BlockHeader) bool {
+	return true
+}
+
 func (c *Chain) InMainChain(hash bc.Hash) bool {
 	block, ok := c.blockMap[hash]
 	if !ok {
@@ -128,6 +137,21 @@ func (c *Chain) ProcessBlock(block *types.Block) (bool, error) {
 	return false, nil
 }
 
+func (c *Chain) NewStateSync(header *types.BlockHeader) *protocol.StateSync {
+	return nil
+}
+
+func (c *Chain) ProcessBlockWithoutState(block *types.Block, txStatus *bc.TransactionStatus) error {
+	if c.bestBlockHeader.Hash() != block.PreviousBlockHash {
+		return errors.New("block not extend the best block")
+	}
+
+	c.heightMap[block.Height] = block
+	c.blockMap[block.Hash()] = block
+	c.bestBlockHeader = &block.BlockHeader
+	return nil
+}
+
 func (c *Chain) SetBestBlockHeader(header *types.BlockHeader) {
 	c.bestBlockHeader = header
 }
@@ -29,11 +29,11 @@ type TxGenerator struct {
 	Builder        *txbuilder.TemplateBuilder
 	AccountManager *account.Manager
 	Assets         *asset.Registry
-	Hsm            *pseudohsm.HSM
+	Hsm            pseudohsm.Signer
 }
 
 // NewTxGenerator create a TxGenerator
-func NewTxGenerator(accountManager *account.Manager, assets *asset.Registry, hsm *pseudohsm.HSM) *TxGenerator {
+func NewTxGenerator(accountManager *account.Manager, assets *asset.Registry, hsm pseudohsm.Signer) *TxGenerator {
 	return &TxGenerator{
 		Builder:        txbuilder.NewBuilder(time.Now()),
 		AccountManager: accountManager,
@@ -61,7 +61,7 @@ func MockTx(utxo *account.UTXO, testAccount *account.Account) (*txbuilder.Templa
 }
 
 // MockSign sign a tx
-func MockSign(tpl *txbuilder.Template, hsm *pseudohsm.HSM, password string) (bool, error) {
+func MockSign(tpl *txbuilder.Template, hsm pseudohsm.Signer, password string) (bool, error) {
 	err := txbuilder.Sign(nil, tpl, password, func(_ context.Context, xpub chainkd.XPub, path [][]byte, data [32]byte, password string) ([]byte, error) {
 		return hsm.XSign(xpub, path, data[:], password)
 	})